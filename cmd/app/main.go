@@ -1,15 +1,21 @@
 package main
 
 import (
+	"context"
 	"time"
 
 	"github.com/davidafdal/post-app/config"
 	"github.com/davidafdal/post-app/internal/builder"
-	"github.com/davidafdal/post-app/pkg/cloudinary"
+	"github.com/davidafdal/post-app/internal/events"
 	"github.com/davidafdal/post-app/pkg/postgres"
 	"github.com/davidafdal/post-app/pkg/rabbitmq"
+	"github.com/davidafdal/post-app/pkg/redis"
+	"github.com/davidafdal/post-app/pkg/scheduler"
 	"github.com/davidafdal/post-app/pkg/server"
+	"github.com/davidafdal/post-app/pkg/socket"
+	"github.com/davidafdal/post-app/pkg/storage"
 	"github.com/davidafdal/post-app/pkg/token"
+	"github.com/davidafdal/post-app/pkg/upload"
 )
 
 func main() {
@@ -17,17 +23,35 @@ func main() {
 	checkError(err)
 	db, err := postgres.InitPostgres(&cfg.Postgres)
 	checkError(err)
-	clodinary, err := cloudinary.NewCloudinaryUseCase(&cfg.Cloudinary)
+	store, err := builder.BuildStorage(cfg)
 	checkError(err)
 	token := token.NewTokenUseCase(cfg.JWT.SecretKey, time.Duration(cfg.JWT.ExpiresAt)*time.Hour)
+	// Every route shares one resumable store so its upload locks cover all
+	// the writers of the directory.
+	resumable := upload.NewResumableStore(upload.TempDir)
 
 	rqm, err := rabbitmq.NewClient(&cfg.Rabbit)
 	checkError(err)
+	checkError(rqm.DeclareQueue(events.Queue))
 
-	publicRoutes := builder.BuildPublicRoute(db, clodinary, token)
-	privateRoutes := builder.BuildPrivateRoute(db, clodinary, token, rqm)
+	rdb, err := redis.InitRedis(&cfg.Redis)
+	checkError(err)
+
+	filter, err := builder.BuildContentFilter(cfg)
+	checkError(err)
+
+	publicRoutes := builder.BuildPublicRoute(db, cfg, store, resumable, token)
+	hub := socket.NewHub(rdb)
+	go hub.Run(context.Background())
 
-	srv := server.NewServer(publicRoutes, privateRoutes, cfg.JWT.SecretKey, token)
+	privateRoutes := builder.BuildPrivateRoute(db, rdb, cfg, store, resumable, token, rqm, filter, hub)
+
+	scheduler.Start(context.Background(), builder.BuildJobs(db, rdb, cfg, store))
+
+	srv := server.NewServer(publicRoutes, privateRoutes, cfg.JWT.SecretKey, token, builder.BuildAccountCheck(db))
+	if local, ok := store.(*storage.Local); ok {
+		srv.Mount("/media", local.Handler())
+	}
 	srv.Run()
 }
 
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+
+	"github.com/davidafdal/post-app/config"
+	"github.com/davidafdal/post-app/internal/builder"
+	"github.com/davidafdal/post-app/internal/events"
+	"github.com/davidafdal/post-app/pkg/postgres"
+	"github.com/davidafdal/post-app/pkg/rabbitmq"
+	"github.com/davidafdal/post-app/pkg/redis"
+	"github.com/davidafdal/post-app/pkg/worker"
+)
+
+func main() {
+	cfg, err := config.NewConfig()
+	checkError(err)
+	db, err := postgres.InitPostgres(&cfg.Postgres)
+	checkError(err)
+	store, err := builder.BuildStorage(cfg)
+	checkError(err)
+	rdb, err := redis.InitRedis(&cfg.Redis)
+	checkError(err)
+
+	rqm, err := rabbitmq.NewClient(&cfg.Rabbit)
+	checkError(err)
+	defer rqm.Close()
+	checkError(rqm.DeclareQueue(events.Queue))
+
+	checkError(worker.Run(context.Background(), rqm, events.Queue, builder.BuildMediaHandlers(db, rdb, cfg, store)))
+}
+
+func checkError(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
@@ -15,6 +15,17 @@ type Config struct {
 	JWT        JWTConfig        `envPrefix:"JWT_"`
 	Rabbit     RabbitConfig     `envPrefix:"RABBITMQ_"`
 	Cloudinary CloudinaryConfig `envPrefix:"CLOUDINARY_"`
+	Storage    StorageConfig    `envPrefix:"STORAGE_"`
+	Redis      RedisConfig      `envPrefix:"REDIS_"`
+	Trending   TrendingConfig   `envPrefix:"TRENDING_"`
+	Suggestion SuggestionConfig `envPrefix:"SUGGESTION_"`
+	Moderation ModerationConfig `envPrefix:"MODERATION_"`
+	Reaction   ReactionConfig   `envPrefix:"REACTION_"`
+	Upload     UploadConfig     `envPrefix:"UPLOAD_"`
+	Image      ImageConfig      `envPrefix:"IMAGE_"`
+	Video      VideoConfig      `envPrefix:"VIDEO_"`
+	Janitor    JanitorConfig    `envPrefix:"JANITOR_"`
+	Schedule   ScheduleConfig   `envPrefix:"SCHEDULE_"`
 }
 
 type PostgresConfig struct {
@@ -34,10 +45,106 @@ type CloudinaryConfig struct {
 	Url string `env:"URL" envDefault:""`
 }
 
+// StorageConfig selects where uploaded media is stored: "local", "s3" or
+// "cloudinary" (configured through CloudinaryConfig). The local driver signs
+// its URLs with SigningKey, which has no default and must be set.
+type StorageConfig struct {
+	Driver     string             `env:"DRIVER" envDefault:"local"`
+	SigningKey string             `env:"SIGNING_KEY"`
+	Local      LocalStorageConfig `envPrefix:"LOCAL_"`
+	S3         S3StorageConfig    `envPrefix:"S3_"`
+}
+
+type LocalStorageConfig struct {
+	Root    string `env:"ROOT" envDefault:"./storage"`
+	BaseURL string `env:"BASE_URL" envDefault:"http://localhost:8080/media"`
+}
+
+type S3StorageConfig struct {
+	Endpoint  string `env:"ENDPOINT" envDefault:"http://localhost:9000"`
+	Region    string `env:"REGION" envDefault:"us-east-1"`
+	Bucket    string `env:"BUCKET" envDefault:"post-app"`
+	AccessKey string `env:"ACCESS_KEY" envDefault:""`
+	SecretKey string `env:"SECRET_KEY" envDefault:""`
+	PublicURL string `env:"PUBLIC_URL" envDefault:""`
+}
+
 type RabbitConfig struct {
 	Url string `env:"URL"`
 }
 
+type RedisConfig struct {
+	Addr     string `env:"ADDR" envDefault:"localhost:6379"`
+	Password string `env:"PASSWORD" envDefault:""`
+	DB       int    `env:"DB" envDefault:"0"`
+}
+
+type TrendingConfig struct {
+	IntervalMinutes int `env:"INTERVAL_MINUTES" envDefault:"10"`
+	Limit           int `env:"LIMIT" envDefault:"100"`
+}
+
+type SuggestionConfig struct {
+	IntervalMinutes int `env:"INTERVAL_MINUTES" envDefault:"60"`
+}
+
+type ModerationConfig struct {
+	ReportThreshold    int      `env:"REPORT_THRESHOLD" envDefault:"5"`
+	AutoHideHours      int      `env:"AUTO_HIDE_HOURS" envDefault:"24"`
+	FilterFile         string   `env:"FILTER_FILE" envDefault:""`
+	FilterLocales      []string `env:"FILTER_LOCALES" envDefault:"id,en" envSeparator:","`
+	FilterHoldRejected bool     `env:"FILTER_HOLD_REJECTED" envDefault:"false"`
+	// MediaHashDistance is how many bits of its perceptual hash an image may
+	// differ in from removed content and still be blocked as a copy of it.
+	MediaHashDistance int `env:"MEDIA_HASH_DISTANCE" envDefault:"6"`
+}
+
+type ReactionConfig struct {
+	Types []string `env:"TYPES" envDefault:"like,love,laugh,wow,sad,angry" envSeparator:","`
+}
+
+// UploadConfig limits the media attached to feeds and avatars. Sizes are in
+// megabytes.
+type UploadConfig struct {
+	MaxFiles          int `env:"MAX_FILES" envDefault:"10"`
+	MaxImageSizeMB    int `env:"MAX_IMAGE_SIZE_MB" envDefault:"10"`
+	MaxVideoSizeMB    int `env:"MAX_VIDEO_SIZE_MB" envDefault:"100"`
+	MaxAvatarSizeMB   int `env:"MAX_AVATAR_SIZE_MB" envDefault:"5"`
+	MaxImageDimension int `env:"MAX_IMAGE_DIMENSION" envDefault:"8000"`
+	MaxVideoSeconds   int `env:"MAX_VIDEO_SECONDS" envDefault:"180"`
+}
+
+// ImageConfig sets the longest side, in pixels, of each variant generated for
+// uploaded images.
+type ImageConfig struct {
+	ThumbnailSize int `env:"THUMBNAIL_SIZE" envDefault:"320"`
+	FeedSize      int `env:"FEED_SIZE" envDefault:"1080"`
+	FullSize      int `env:"FULL_SIZE" envDefault:"2048"`
+	JPEGQuality   int `env:"JPEG_QUALITY" envDefault:"85"`
+}
+
+// VideoConfig sets how the worker transcodes uploaded videos. Videos taller
+// than MaxHeight pixels are scaled down.
+type VideoConfig struct {
+	FFmpegPath string `env:"FFMPEG_PATH" envDefault:"ffmpeg"`
+	MaxHeight  int    `env:"MAX_HEIGHT" envDefault:"720"`
+}
+
+// JanitorConfig sets how often leftover temp files and stored media nothing
+// points to are removed. With DryRun set the janitor only logs what it would
+// remove.
+type JanitorConfig struct {
+	IntervalMinutes     int  `env:"INTERVAL_MINUTES" envDefault:"60"`
+	TempFileMaxAgeHours int  `env:"TEMP_FILE_MAX_AGE_HOURS" envDefault:"24"`
+	OrphanMinAgeHours   int  `env:"ORPHAN_MIN_AGE_HOURS" envDefault:"24"`
+	DryRun              bool `env:"DRY_RUN" envDefault:"false"`
+}
+
+// ScheduleConfig sets how often scheduled feeds are checked for publishing.
+type ScheduleConfig struct {
+	IntervalMinutes int `env:"INTERVAL_MINUTES" envDefault:"1"`
+}
+
 func NewConfig() (*Config, error) {
 	err := godotenv.Load(".env")
 	if err != nil {
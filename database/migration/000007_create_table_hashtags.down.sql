@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS comment_hashtags;
+DROP TABLE IF EXISTS feed_hashtags;
+DROP TABLE IF EXISTS hashtags;
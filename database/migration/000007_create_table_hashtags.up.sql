@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS hashtags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS feed_hashtags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    hashtag_id UUID NOT NULL REFERENCES hashtags(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(feed_id, hashtag_id)
+);
+
+CREATE TABLE IF NOT EXISTS comment_hashtags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    comment_id UUID NOT NULL REFERENCES feed_comments(id) ON DELETE CASCADE,
+    hashtag_id UUID NOT NULL REFERENCES hashtags(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(comment_id, hashtag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_feed_hashtags_hashtag_id ON feed_hashtags(hashtag_id);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS feed_mentions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(feed_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS comment_mentions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    comment_id UUID NOT NULL REFERENCES feed_comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(comment_id, user_id)
+);
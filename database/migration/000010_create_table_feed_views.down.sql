@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_feeds_created_at;
+DROP TABLE IF EXISTS feed_views;
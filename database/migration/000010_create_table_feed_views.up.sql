@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS feed_views (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(feed_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_feeds_created_at ON feeds(created_at DESC);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_user_folows_follower;
+DROP INDEX IF EXISTS idx_user_folows_following;
+ALTER TABLE user_folows DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,4 @@
+ALTER TABLE user_folows ADD COLUMN IF NOT EXISTS created_at TIMESTAMP DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_user_folows_following ON user_folows(following_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_user_folows_follower ON user_folows(follower_id, created_at DESC);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS moderation_audit_logs;
+DROP TABLE IF EXISTS reports;
+
+ALTER TABLE feed_comments DROP COLUMN IF EXISTS hidden_until;
+ALTER TABLE feed_comments DROP COLUMN IF EXISTS is_hidden;
+
+ALTER TABLE feeds DROP COLUMN IF EXISTS hidden_until;
+ALTER TABLE feeds DROP COLUMN IF EXISTS is_hidden;
+
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_at;
@@ -0,0 +1,35 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP;
+
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS is_hidden BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS hidden_until TIMESTAMP;
+
+ALTER TABLE feed_comments ADD COLUMN IF NOT EXISTS is_hidden BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE feed_comments ADD COLUMN IF NOT EXISTS hidden_until TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_type VARCHAR(20) NOT NULL,
+    target_id UUID NOT NULL,
+    reason VARCHAR(30) NOT NULL,
+    note TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (reporter_id, target_type, target_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reports_target ON reports(target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_reports_status ON reports(status, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS moderation_audit_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    moderator_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(30) NOT NULL,
+    report_id UUID REFERENCES reports(id) ON DELETE SET NULL,
+    target_type VARCHAR(20) NOT NULL,
+    target_id UUID NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+ALTER TABLE feeds DROP COLUMN IF EXISTS pinned_comment_id;
+
+ALTER TABLE feed_comments DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE feed_comments DROP COLUMN IF EXISTS edited_at;
@@ -0,0 +1,4 @@
+ALTER TABLE feed_comments ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP;
+ALTER TABLE feed_comments ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS pinned_comment_id UUID REFERENCES feed_comments(id) ON DELETE SET NULL;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS feed_likes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(feed_id, user_id)
+);
+
+INSERT INTO feed_likes (feed_id, user_id, created_at)
+SELECT feed_id, user_id, created_at
+FROM feed_reactions
+ON CONFLICT (feed_id, user_id) DO NOTHING;
+
+DROP TABLE IF EXISTS feed_reactions;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS feed_reactions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(feed_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_feed_reactions_feed_type ON feed_reactions(feed_id, type, created_at DESC);
+
+INSERT INTO feed_reactions (feed_id, user_id, type, created_at)
+SELECT feed_id, user_id, 'like', created_at
+FROM feed_likes
+ON CONFLICT (feed_id, user_id) DO NOTHING;
+
+DROP TABLE IF EXISTS feed_likes;
@@ -0,0 +1,4 @@
+ALTER TABLE feed_media DROP COLUMN IF EXISTS height;
+ALTER TABLE feed_media DROP COLUMN IF EXISTS width;
+ALTER TABLE feed_media DROP COLUMN IF EXISTS blurhash;
+ALTER TABLE feed_media DROP COLUMN IF EXISTS variants;
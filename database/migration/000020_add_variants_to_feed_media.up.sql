@@ -0,0 +1,4 @@
+ALTER TABLE feed_media ADD COLUMN IF NOT EXISTS variants JSONB NOT NULL DEFAULT '{}'::jsonb;
+ALTER TABLE feed_media ADD COLUMN IF NOT EXISTS blurhash TEXT NOT NULL DEFAULT '';
+ALTER TABLE feed_media ADD COLUMN IF NOT EXISTS width INT NOT NULL DEFAULT 0;
+ALTER TABLE feed_media ADD COLUMN IF NOT EXISTS height INT NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+ALTER TABLE feed_media DROP CONSTRAINT IF EXISTS feed_media_status_check;
+ALTER TABLE feed_media DROP CONSTRAINT IF EXISTS feed_media_type_check;
+
+ALTER TABLE feed_media ALTER COLUMN url DROP DEFAULT;
+ALTER TABLE feed_media DROP COLUMN IF EXISTS duration_ms;
+ALTER TABLE feed_media DROP COLUMN IF EXISTS poster_url;
+ALTER TABLE feed_media DROP COLUMN IF EXISTS status;
@@ -0,0 +1,9 @@
+UPDATE feed_media SET type = LOWER(type);
+
+ALTER TABLE feed_media ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'ready';
+ALTER TABLE feed_media ADD COLUMN IF NOT EXISTS poster_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE feed_media ADD COLUMN IF NOT EXISTS duration_ms INT NOT NULL DEFAULT 0;
+ALTER TABLE feed_media ALTER COLUMN url SET DEFAULT '';
+
+ALTER TABLE feed_media ADD CONSTRAINT feed_media_type_check CHECK (type IN ('image', 'video'));
+ALTER TABLE feed_media ADD CONSTRAINT feed_media_status_check CHECK (status IN ('pending', 'processing', 'ready', 'failed'));
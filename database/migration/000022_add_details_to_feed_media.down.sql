@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_feed_media_feed_position;
+
+ALTER TABLE feed_media DROP COLUMN IF EXISTS caption;
+ALTER TABLE feed_media DROP COLUMN IF EXISTS alt_text;
+ALTER TABLE feed_media DROP COLUMN IF EXISTS position;
@@ -0,0 +1,13 @@
+ALTER TABLE feed_media ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;
+ALTER TABLE feed_media ADD COLUMN IF NOT EXISTS alt_text TEXT NOT NULL DEFAULT '';
+ALTER TABLE feed_media ADD COLUMN IF NOT EXISTS caption TEXT NOT NULL DEFAULT '';
+
+UPDATE feed_media fm
+SET position = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY feed_id ORDER BY created_at, id) - 1 AS position
+    FROM feed_media
+) ordered
+WHERE fm.id = ordered.id;
+
+CREATE INDEX IF NOT EXISTS idx_feed_media_feed_position ON feed_media (feed_id, position);
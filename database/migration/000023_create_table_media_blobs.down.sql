@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS blocked_media;
+
+DROP TRIGGER IF EXISTS feed_media_blob_refs ON feed_media;
+DROP FUNCTION IF EXISTS count_media_blob_refs();
+
+DROP INDEX IF EXISTS idx_feed_media_blob;
+ALTER TABLE feed_media DROP COLUMN IF EXISTS blob_id;
+
+DROP TABLE IF EXISTS media_blobs;
@@ -0,0 +1,57 @@
+CREATE TABLE IF NOT EXISTS media_blobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sha256 CHAR(64) NOT NULL UNIQUE,
+    phash BIGINT,
+    type VARCHAR(20) NOT NULL,
+    url TEXT NOT NULL,
+    variants JSONB NOT NULL DEFAULT '{}'::jsonb,
+    blurhash TEXT NOT NULL DEFAULT '',
+    poster_url TEXT NOT NULL DEFAULT '',
+    duration_ms INT NOT NULL DEFAULT 0,
+    width INT NOT NULL DEFAULT 0,
+    height INT NOT NULL DEFAULT 0,
+    keys JSONB NOT NULL DEFAULT '[]'::jsonb,
+    ref_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_blobs_unreferenced ON media_blobs (updated_at) WHERE ref_count = 0;
+
+ALTER TABLE feed_media ADD COLUMN IF NOT EXISTS blob_id UUID REFERENCES media_blobs(id);
+
+CREATE INDEX IF NOT EXISTS idx_feed_media_blob ON feed_media (blob_id);
+
+-- ref_count follows the feed media pointing at a blob, including rows removed
+-- by cascades, so a blob is only deleted once nothing uses it.
+CREATE OR REPLACE FUNCTION count_media_blob_refs() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.blob_id IS NOT DISTINCT FROM NEW.blob_id THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.blob_id IS NOT NULL THEN
+        UPDATE media_blobs SET ref_count = ref_count - 1, updated_at = NOW() WHERE id = OLD.blob_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.blob_id IS NOT NULL THEN
+        UPDATE media_blobs SET ref_count = ref_count + 1, updated_at = NOW() WHERE id = NEW.blob_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER feed_media_blob_refs
+AFTER INSERT OR DELETE OR UPDATE OF blob_id ON feed_media
+FOR EACH ROW EXECUTE FUNCTION count_media_blob_refs();
+
+CREATE TABLE IF NOT EXISTS blocked_media (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sha256 CHAR(64) NOT NULL UNIQUE,
+    phash BIGINT,
+    feed_id UUID,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_blocked_media_feed ON blocked_media (feed_id);
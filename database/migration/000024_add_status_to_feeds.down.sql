@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_feeds_user_drafts;
+DROP INDEX IF EXISTS idx_feeds_due;
+DROP INDEX IF EXISTS idx_feeds_published_at;
+
+ALTER TABLE feeds DROP CONSTRAINT IF EXISTS feeds_publish_at_check;
+ALTER TABLE feeds DROP CONSTRAINT IF EXISTS feeds_status_check;
+
+-- Drafts and posts still waiting for their time have never been shown.
+DELETE FROM feeds WHERE status <> 'published';
+
+ALTER TABLE feeds DROP COLUMN IF EXISTS published_at;
+ALTER TABLE feeds DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE feeds DROP COLUMN IF EXISTS status;
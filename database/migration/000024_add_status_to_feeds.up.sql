@@ -0,0 +1,12 @@
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published';
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS published_at TIMESTAMP;
+
+UPDATE feeds SET published_at = created_at WHERE published_at IS NULL;
+
+ALTER TABLE feeds ADD CONSTRAINT feeds_status_check CHECK (status IN ('draft', 'scheduled', 'published'));
+ALTER TABLE feeds ADD CONSTRAINT feeds_publish_at_check CHECK (status <> 'scheduled' OR publish_at IS NOT NULL);
+
+CREATE INDEX IF NOT EXISTS idx_feeds_published_at ON feeds (published_at DESC) WHERE status = 'published';
+CREATE INDEX IF NOT EXISTS idx_feeds_due ON feeds (publish_at) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS idx_feeds_user_drafts ON feeds (user_id, created_at DESC) WHERE status <> 'published';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_feeds_quoted_feed_id;
+ALTER TABLE feeds DROP COLUMN IF EXISTS quoted_feed_id;
+
+DROP TABLE IF EXISTS feed_reposts;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS feed_reposts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(feed_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_feed_reposts_user ON feed_reposts(user_id, created_at DESC);
+
+-- A quote outlives the feed it quotes, so quoted_feed_id is not a foreign key:
+-- once the original is deleted the quote keeps the id and shows it as gone.
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS quoted_feed_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_feeds_quoted_feed_id ON feeds(quoted_feed_id);
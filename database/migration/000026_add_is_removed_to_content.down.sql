@@ -0,0 +1,2 @@
+ALTER TABLE feed_comments DROP COLUMN IF EXISTS is_removed;
+ALTER TABLE feeds DROP COLUMN IF EXISTS is_removed;
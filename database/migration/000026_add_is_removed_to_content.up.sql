@@ -0,0 +1,16 @@
+-- Content hidden by a moderator stays hidden when later reports on it are
+-- dismissed; only automatic hides are lifted.
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS is_removed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE feed_comments ADD COLUMN IF NOT EXISTS is_removed BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE feeds SET is_removed = TRUE
+WHERE id IN (
+    SELECT target_id FROM moderation_audit_logs
+    WHERE action = 'hide_content' AND target_type = 'feed'
+);
+
+UPDATE feed_comments SET is_removed = TRUE
+WHERE id IN (
+    SELECT target_id FROM moderation_audit_logs
+    WHERE action = 'hide_content' AND target_type = 'comment'
+);
@@ -0,0 +1,7 @@
+DELETE FROM blocked_media a
+USING blocked_media b
+WHERE a.sha256 = b.sha256
+    AND (a.created_at, a.id) > (b.created_at, b.id);
+
+ALTER TABLE blocked_media DROP CONSTRAINT IF EXISTS blocked_media_sha256_feed_id_key;
+ALTER TABLE blocked_media ADD CONSTRAINT blocked_media_sha256_key UNIQUE (sha256);
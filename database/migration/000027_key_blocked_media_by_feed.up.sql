@@ -0,0 +1,12 @@
+-- A hash stays blocked while any removed feed still carries it, so restoring
+-- one feed does not unblock content another removed feed shares.
+ALTER TABLE blocked_media DROP CONSTRAINT IF EXISTS blocked_media_sha256_key;
+ALTER TABLE blocked_media ADD CONSTRAINT blocked_media_sha256_feed_id_key UNIQUE (sha256, feed_id);
+
+INSERT INTO blocked_media (sha256, phash, feed_id)
+SELECT b.sha256, b.phash, fm.feed_id
+FROM feeds f
+JOIN feed_media fm ON fm.feed_id = f.id
+JOIN media_blobs b ON b.id = fm.blob_id
+WHERE f.is_removed
+ON CONFLICT (sha256, feed_id) DO NOTHING;
@@ -1,45 +1,250 @@
 package builder
 
 import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/davidafdal/post-app/config"
+	"github.com/davidafdal/post-app/internal/events"
 	"github.com/davidafdal/post-app/internal/http/handler"
 	"github.com/davidafdal/post-app/internal/http/router"
 	"github.com/davidafdal/post-app/internal/repositories"
 	"github.com/davidafdal/post-app/internal/services"
-	"github.com/davidafdal/post-app/pkg/cloudinary"
+	"github.com/davidafdal/post-app/pkg/contentfilter"
+	"github.com/davidafdal/post-app/pkg/imageproc"
+	"github.com/davidafdal/post-app/pkg/mediacheck"
 	"github.com/davidafdal/post-app/pkg/rabbitmq"
 	"github.com/davidafdal/post-app/pkg/route"
+	"github.com/davidafdal/post-app/pkg/scheduler"
+	"github.com/davidafdal/post-app/pkg/server"
+	"github.com/davidafdal/post-app/pkg/socket"
+	"github.com/davidafdal/post-app/pkg/storage"
 	"github.com/davidafdal/post-app/pkg/token"
 	"github.com/davidafdal/post-app/pkg/upload"
+	"github.com/davidafdal/post-app/pkg/videoproc"
+	"github.com/davidafdal/post-app/pkg/worker"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
 )
 
-func BuildPublicRoute(db *sqlx.DB, cloudinary cloudinary.CloudinaryUseCase, token token.TokenUseCase) []*route.Route {
+func BuildPublicRoute(db *sqlx.DB, cfg *config.Config, store storage.Storage, resumable upload.ResumableStore, token token.TokenUseCase) []*route.Route {
 
 	userRepo := repositories.NewUserRepository(db)
-	userService := services.NewUserService(userRepo, cloudinary, token)
+	userService := services.NewUserService(userRepo, store, avatarValidator(cfg), token)
 	userHandler := handler.NewUserHandler(userService)
 
-	handler := handler.NewHandler(userHandler, nil, nil)
+	uploadService := services.NewUploadService(resumable, maxUploadSize(cfg))
+	uploadHandler := handler.NewUploadHandler(uploadService)
+
+	handler := handler.NewHandler(userHandler, nil, nil, nil, nil, nil, nil, nil, nil, uploadHandler)
 
 	return router.PublicRoute(handler)
 }
 
-func BuildPrivateRoute(db *sqlx.DB, cloudinary cloudinary.CloudinaryUseCase, token token.TokenUseCase, msgBroker *rabbitmq.Client) []*route.Route {
+func BuildPrivateRoute(db *sqlx.DB, rdb *redis.Client, cfg *config.Config, store storage.Storage, resumable upload.ResumableStore, token token.TokenUseCase, msgBroker *rabbitmq.Client, filter contentfilter.Filter, hub *socket.Hub) []*route.Route {
 	uploadUsecase := upload.NewUploadUseCase()
 
 	userRepo := repositories.NewUserRepository(db)
-	userService := services.NewUserService(userRepo, cloudinary, token)
+	userService := services.NewUserService(userRepo, store, avatarValidator(cfg), token)
 	userHandler := handler.NewUserHandler(userService)
 
-	commentRepo := repositories.NewCommentRepository(db)
-	commentService := services.NewCommentService(commentRepo)
-	commentHandler := handler.NewCommentHandler(commentService)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	notificationService := services.NewNotificationService(notificationRepo, userRepo)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+
+	reportRepo := repositories.NewReportRepository(db)
+	mediaRepo := repositories.NewMediaRepository(db)
+	contentModerator := services.NewContentModerator(filter, reportRepo, mediaRepo, cfg.Moderation.MediaHashDistance)
 
 	feedRepo := repositories.NewFeedRepository(db)
-	feedService := services.NewFeedService(feedRepo, uploadUsecase, msgBroker)
+	feedService := services.NewFeedService(feedRepo, userRepo, notificationRepo, contentModerator, cfg.Reaction.Types, feedMediaValidator(cfg), uploadUsecase, resumable, msgBroker)
 	feedHandler := handler.NewFeedHandler(feedService)
 
-	handler := handler.NewHandler(userHandler, feedHandler, commentHandler)
+	commentRepo := repositories.NewCommentRepository(db)
+	commentService := services.NewCommentService(commentRepo, feedRepo, notificationRepo, contentModerator)
+	commentHandler := handler.NewCommentHandler(commentService)
+
+	trendingRepo := repositories.NewTrendingRepository(db, rdb)
+	trendingService := services.NewTrendingService(trendingRepo, feedRepo, cfg.Trending.Limit)
+	trendingHandler := handler.NewTrendingHandler(trendingService)
+
+	suggestionRepo := repositories.NewSuggestionRepository(db, rdb)
+	suggestionService := services.NewSuggestionService(suggestionRepo, suggestionCacheTTL(cfg))
+	suggestionHandler := handler.NewSuggestionHandler(suggestionService)
+
+	reportService := services.NewReportService(reportRepo, userRepo, mediaRepo, cfg.Moderation.ReportThreshold, time.Duration(cfg.Moderation.AutoHideHours)*time.Hour)
+	reportHandler := handler.NewReportHandler(reportService)
+
+	adminService := services.NewAdminService(userRepo, reportRepo)
+	adminHandler := handler.NewAdminHandler(adminService)
+
+	socketHandler := handler.NewSocketHandler(hub)
+
+	uploadService := services.NewUploadService(resumable, maxUploadSize(cfg))
+	uploadHandler := handler.NewUploadHandler(uploadService)
+
+	handler := handler.NewHandler(userHandler, feedHandler, commentHandler, notificationHandler, trendingHandler, suggestionHandler, reportHandler, adminHandler, socketHandler, uploadHandler)
 
 	return router.PrivateRoute(handler)
 }
+
+func BuildJobs(db *sqlx.DB, rdb *redis.Client, cfg *config.Config, store storage.Storage) []*scheduler.Job {
+	feedRepo := repositories.NewFeedRepository(db)
+
+	trendingRepo := repositories.NewTrendingRepository(db, rdb)
+	trendingService := services.NewTrendingService(trendingRepo, feedRepo, cfg.Trending.Limit)
+
+	suggestionRepo := repositories.NewSuggestionRepository(db, rdb)
+	suggestionService := services.NewSuggestionService(suggestionRepo, suggestionCacheTTL(cfg))
+
+	scheduleService := services.NewScheduleService(feedRepo, repositories.NewNotificationRepository(db))
+
+	mediaRepo := repositories.NewMediaRepository(db)
+	janitorService := services.NewJanitorService(mediaRepo, store, services.JanitorOptions{
+		TempDir:        upload.TempDir,
+		TempFileMaxAge: time.Duration(cfg.Janitor.TempFileMaxAgeHours) * time.Hour,
+		OrphanMinAge:   time.Duration(cfg.Janitor.OrphanMinAgeHours) * time.Hour,
+		DryRun:         cfg.Janitor.DryRun,
+	})
+
+	return []*scheduler.Job{
+		{
+			Name:     "compute_trending",
+			Interval: time.Duration(cfg.Trending.IntervalMinutes) * time.Minute,
+			Run:      trendingService.ComputeTrending,
+		},
+		{
+			Name:     "compute_suggestions",
+			Interval: time.Duration(cfg.Suggestion.IntervalMinutes) * time.Minute,
+			Run:      suggestionService.ComputeSuggestions,
+		},
+		{
+			Name:     "publish_scheduled_feeds",
+			Interval: time.Duration(cfg.Schedule.IntervalMinutes) * time.Minute,
+			Run:      scheduleService.PublishDueFeeds,
+		},
+		{
+			Name:     "clean_media",
+			Interval: time.Duration(cfg.Janitor.IntervalMinutes) * time.Minute,
+			Run: func(ctx context.Context) error {
+				_, err := janitorService.Clean(ctx)
+				return err
+			},
+		},
+	}
+}
+
+// BuildMediaHandlers returns the worker handlers that move feed media into
+// storage. Processing results reach the API's websocket clients through
+// Redis.
+func BuildMediaHandlers(db *sqlx.DB, rdb *redis.Client, cfg *config.Config, store storage.Storage) []*worker.Handler {
+	feedRepo := repositories.NewFeedRepository(db)
+	images := imageproc.New([]imageproc.Variant{
+		{Name: "thumbnail", MaxSize: cfg.Image.ThumbnailSize},
+		{Name: "feed", MaxSize: cfg.Image.FeedSize},
+		{Name: "full", MaxSize: cfg.Image.FullSize},
+	}, cfg.Image.JPEGQuality)
+	videos := videoproc.NewFFmpeg(cfg.Video.FFmpegPath, cfg.Video.MaxHeight)
+	mediaService := services.NewMediaService(feedRepo, repositories.NewMediaRepository(db), store, images, videos, socket.NewPublisher(rdb))
+
+	return []*worker.Handler{
+		{
+			EventType: string(events.UploadFeedMedias),
+			Run:       worker.JSON(mediaService.StoreFeedMedias),
+		},
+		{
+			EventType: string(events.DeleteFeedMedias),
+			Run:       worker.JSON(mediaService.DeleteFeedMedias),
+		},
+	}
+}
+
+const megabyte = 1 << 20
+
+func feedMediaValidator(cfg *config.Config) *mediacheck.Validator {
+	return mediacheck.New(mediacheck.Limits{
+		MaxFiles:         cfg.Upload.MaxFiles,
+		MaxImageSize:     int64(cfg.Upload.MaxImageSizeMB) * megabyte,
+		MaxVideoSize:     int64(cfg.Upload.MaxVideoSizeMB) * megabyte,
+		MaxImageWidth:    cfg.Upload.MaxImageDimension,
+		MaxImageHeight:   cfg.Upload.MaxImageDimension,
+		MaxVideoDuration: time.Duration(cfg.Upload.MaxVideoSeconds) * time.Second,
+	})
+}
+
+// maxUploadSize is the largest resumable upload accepted, which is the
+// largest media a feed may have.
+func maxUploadSize(cfg *config.Config) int64 {
+	return int64(max(cfg.Upload.MaxImageSizeMB, cfg.Upload.MaxVideoSizeMB)) * megabyte
+}
+
+func avatarValidator(cfg *config.Config) *mediacheck.Validator {
+	return mediacheck.New(mediacheck.Limits{
+		MaxFiles:       1,
+		MaxImageSize:   int64(cfg.Upload.MaxAvatarSizeMB) * megabyte,
+		MaxImageWidth:  cfg.Upload.MaxImageDimension,
+		MaxImageHeight: cfg.Upload.MaxImageDimension,
+	})
+}
+
+// BuildStorage returns the storage selected by STORAGE_DRIVER.
+func BuildStorage(cfg *config.Config) (storage.Storage, error) {
+	switch cfg.Storage.Driver {
+	case "local":
+		return storage.NewLocal(cfg.Storage.Local.Root, cfg.Storage.Local.BaseURL, cfg.Storage.SigningKey)
+	case "s3":
+		return storage.NewS3(storage.S3Config{
+			Endpoint:  cfg.Storage.S3.Endpoint,
+			Region:    cfg.Storage.S3.Region,
+			Bucket:    cfg.Storage.S3.Bucket,
+			AccessKey: cfg.Storage.S3.AccessKey,
+			SecretKey: cfg.Storage.S3.SecretKey,
+			PublicURL: cfg.Storage.S3.PublicURL,
+		})
+	case "cloudinary":
+		return storage.NewCloudinary(cfg.Cloudinary.Url)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
+
+// suggestionCacheTTL keeps cached suggestions alive across one missed run of
+// the suggestion job.
+func suggestionCacheTTL(cfg *config.Config) time.Duration {
+	return 2 * time.Duration(cfg.Suggestion.IntervalMinutes) * time.Minute
+}
+
+// BuildContentFilter loads the denylists from MODERATION_FILTER_FILE, or the
+// embedded defaults when it is not set.
+func BuildContentFilter(cfg *config.Config) (contentfilter.Filter, error) {
+	var rules *contentfilter.Rules
+	var err error
+
+	if cfg.Moderation.FilterFile != "" {
+		rules, err = contentfilter.LoadRules(cfg.Moderation.FilterFile)
+	} else {
+		rules, err = contentfilter.DefaultRules()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return contentfilter.New(rules, cfg.Moderation.FilterLocales, cfg.Moderation.FilterHoldRejected)
+}
+
+// BuildAccountCheck lets the server reject requests from suspended accounts
+// and authorize the others by their current role.
+func BuildAccountCheck(db *sqlx.DB) server.AccountCheck {
+	userRepo := repositories.NewUserRepository(db)
+
+	return func(ctx context.Context, userID string) (string, bool, error) {
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			return "", false, err
+		}
+		return userRepo.FindAccountStatus(id)
+	}
+}
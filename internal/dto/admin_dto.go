@@ -0,0 +1,15 @@
+package dto
+
+import "github.com/google/uuid"
+
+type ChangeRoleRequest struct {
+	UserID    uuid.UUID `param:"user_id" validate:"required"`
+	Role      string    `json:"role" validate:"required,oneof=user moderator admin"`
+	ActorID   uuid.UUID
+	ActorRole string
+}
+
+type UserListResponse struct {
+	Items      []*UserResponse `json:"items"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type CreateCommentRequest struct {
 	FeedID   uuid.UUID `param:"feed_id" validate:"required"`
@@ -10,14 +14,35 @@ type CreateCommentRequest struct {
 
 type CreateReplyCommentRequest struct {
 	CommentID uuid.UUID `param:"comment_id" validate:"required"`
-	FeedID    uuid.UUID `json:"feed_id" validate:"requried"`
+	FeedID    uuid.UUID `json:"feed_id"`
 	SenderID  uuid.UUID
 	Comment   string `json:"comment" validate:"required"`
 }
 
+type UpdateCommentRequest struct {
+	CommentID uuid.UUID `param:"comment_id" validate:"required"`
+	Comment   string    `json:"comment" validate:"required"`
+	UserID    uuid.UUID
+}
+
 type CommentResponse struct {
-	ID         uuid.UUID     `json:"id"`
-	Comment    string        `json:"comment"`
-	User       *UserResponse `json:"user"`
-	ReplyCount int           `json:"replies,omizero"`
+	ID            uuid.UUID             `json:"id"`
+	Comment       string                `json:"comment"`
+	Entities      []*TextEntityResponse `json:"entities"`
+	User          *UserResponse         `json:"user"`
+	ReplyCount    int                   `json:"replies"`
+	ReplyPreview  []*CommentResponse    `json:"reply_preview,omitempty"`
+	LikeCount     int                   `json:"like_count"`
+	LikedByMe     bool                  `json:"liked_by_me"`
+	HeldForReview bool                  `json:"held_for_review,omitzero"`
+	IsPinned      bool                  `json:"is_pinned,omitzero"`
+	IsEdited      bool                  `json:"is_edited"`
+	EditedAt      *time.Time            `json:"edited_at,omitempty"`
+	IsDeleted     bool                  `json:"is_deleted,omitzero"`
+	CreatedAt     time.Time             `json:"created_at"`
+}
+
+type CommentListResponse struct {
+	Items      []*CommentResponse `json:"items"`
+	NextCursor string             `json:"next_cursor,omitempty"`
 }
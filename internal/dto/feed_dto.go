@@ -1,22 +1,124 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
 
+	"github.com/google/uuid"
+)
+
+// CreateFeedRequest carries the text of a new feed. AltTexts and
+// MediaCaptions follow the order of the media: the uploaded files, then the
+// resumable uploads. Status defaults to scheduled when PublishAt is given and
+// to published otherwise. A quote post sets QuotedFeedID and needs no media.
 type CreateFeedRequest struct {
-	Caption string `form:"caption" validate:"required"`
+	Caption       string     `form:"caption" json:"caption" validate:"required"`
+	UploadIDs     []string   `form:"upload_ids" json:"upload_ids"`
+	AltTexts      []string   `form:"alt_texts" json:"alt_texts" validate:"dive,max=1000"`
+	MediaCaptions []string   `form:"media_captions" json:"media_captions" validate:"dive,max=2200"`
+	Status        string     `form:"status" json:"status" validate:"omitempty,oneof=draft scheduled published"`
+	PublishAt     *time.Time `form:"publish_at" json:"publish_at"`
+	QuotedFeedID  *uuid.UUID `form:"quoted_feed_id" json:"quoted_feed_id"`
+	UserID        uuid.UUID
+}
+
+type UpdateFeedRequest struct {
+	FeedID  uuid.UUID `param:"feed_id" validate:"required"`
+	Caption string    `json:"caption" validate:"required"`
 	UserID  uuid.UUID
 }
 
+// UpdateDraftRequest rewrites a draft or scheduled feed. Status defaults to
+// scheduled when PublishAt is given and to draft otherwise; published
+// publishes it right away.
+type UpdateDraftRequest struct {
+	FeedID    uuid.UUID  `param:"feed_id" validate:"required"`
+	Caption   string     `json:"caption" validate:"required"`
+	Status    string     `json:"status" validate:"omitempty,oneof=draft scheduled published"`
+	PublishAt *time.Time `json:"publish_at"`
+	UserID    uuid.UUID
+}
+
+type UpdateMediaRequest struct {
+	FeedID  uuid.UUID `param:"feed_id" validate:"required"`
+	MediaID uuid.UUID `param:"media_id" validate:"required"`
+	AltText string    `json:"alt_text" validate:"max=1000"`
+	Caption string    `json:"caption" validate:"max=2200"`
+	UserID  uuid.UUID
+}
+
+type ReorderMediasRequest struct {
+	FeedID   uuid.UUID   `param:"feed_id" validate:"required"`
+	MediaIDs []uuid.UUID `json:"media_ids" validate:"required,min=1"`
+	UserID   uuid.UUID
+}
+
+type ReactFeedRequest struct {
+	FeedID uuid.UUID `param:"feed_id" validate:"required"`
+	Type   string    `json:"type" validate:"required"`
+	UserID uuid.UUID
+}
+
+type MarkFeedsSeenRequest struct {
+	FeedIDs []uuid.UUID `json:"feed_ids" validate:"required,min=1"`
+	UserID  uuid.UUID
+}
+
+// FeedResponse describes a feed. Quote posts carry the quoted feed, or
+// QuoteUnavailable once it was deleted or the viewer may no longer see it.
+// RepostedBy is set on feeds listed in the home feed because someone the
+// viewer follows reposted them.
 type FeedResponse struct {
-	ID       uuid.UUID        `json:"id"`
-	Caption  string           `json:"caption"`
-	User     *UserResponse    `json:"user,omitzero"`
-	Medias   []*MediaResponse `json:"medias,omitzero"`
-	Likes    int              `json:"likes"`
-	Comments int              `json:"comments"`
+	ID               uuid.UUID             `json:"id"`
+	Caption          string                `json:"caption"`
+	Entities         []*TextEntityResponse `json:"entities"`
+	User             *UserResponse         `json:"user,omitzero"`
+	Medias           []*MediaResponse      `json:"medias,omitzero"`
+	ReactionCount    int                   `json:"reaction_count"`
+	Reactions        map[string]int        `json:"reactions"`
+	Reposts          int                   `json:"reposts"`
+	Comments         int                   `json:"comments"`
+	HeldForReview    bool                  `json:"held_for_review,omitzero"`
+	Status           string                `json:"status,omitzero"`
+	PublishAt        *time.Time            `json:"publish_at,omitzero"`
+	QuotedFeedID     *uuid.UUID            `json:"quoted_feed_id,omitzero"`
+	QuotedFeed       *FeedResponse         `json:"quoted_feed,omitzero"`
+	QuoteUnavailable bool                  `json:"quote_unavailable,omitzero"`
+	RepostedBy       *UserResponse         `json:"reposted_by,omitzero"`
 }
 
+// MediaResponse describes a feed media. Status is pending or processing
+// until the worker has stored the file, then ready or failed; Url is only set
+// once it is ready.
 type MediaResponse struct {
-	Url  string `json:"url"`
-	Type string `json:"type"`
+	ID         uuid.UUID         `json:"id"`
+	Url        string            `json:"url"`
+	Type       string            `json:"type"`
+	Status     string            `json:"status"`
+	Position   int               `json:"position"`
+	AltText    string            `json:"alt_text"`
+	Caption    string            `json:"caption,omitempty"`
+	Variants   map[string]string `json:"variants,omitempty"`
+	Blurhash   string            `json:"blurhash,omitempty"`
+	PosterUrl  string            `json:"poster_url,omitempty"`
+	DurationMs int               `json:"duration_ms,omitempty"`
+	Width      int               `json:"width,omitempty"`
+	Height     int               `json:"height,omitempty"`
+}
+
+// MediaProcessedEvent is pushed to the owner of a feed when one of its media
+// is ready or failed.
+type MediaProcessedEvent struct {
+	FeedID uuid.UUID      `json:"feed_id"`
+	Media  *MediaResponse `json:"media"`
+}
+
+type ReactionResponse struct {
+	User      *UserResponse `json:"user"`
+	Type      string        `json:"type"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type ReactionListResponse struct {
+	Items      []*ReactionResponse `json:"items"`
+	NextCursor string              `json:"next_cursor,omitempty"`
 }
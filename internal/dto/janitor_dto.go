@@ -0,0 +1,9 @@
+package dto
+
+// CleanupReport lists what a janitor run removed, or would have removed when
+// DryRun is set.
+type CleanupReport struct {
+	DryRun    bool     `json:"dry_run"`
+	TempFiles []string `json:"temp_files"`
+	Objects   []string `json:"objects"`
+}
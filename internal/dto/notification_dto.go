@@ -0,0 +1,17 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationResponse struct {
+	ID        uuid.UUID     `json:"id"`
+	Type      string        `json:"type"`
+	Actor     *UserResponse `json:"actor"`
+	FeedID    *uuid.UUID    `json:"feed_id,omitempty"`
+	CommentID *uuid.UUID    `json:"comment_id,omitempty"`
+	IsRead    bool          `json:"is_read"`
+	CreatedAt time.Time     `json:"created_at"`
+}
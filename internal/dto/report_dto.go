@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateReportRequest struct {
+	TargetType string    `json:"target_type" validate:"required,oneof=feed comment user"`
+	TargetID   uuid.UUID `json:"target_id" validate:"required"`
+	Reason     string    `json:"reason" validate:"required,oneof=spam harassment hate_speech nudity violence misinformation other"`
+	Note       string    `json:"note" validate:"max=500"`
+	ReporterID uuid.UUID
+}
+
+type ModerationActionRequest struct {
+	ReportID      uuid.UUID `param:"report_id" validate:"required"`
+	Action        string    `json:"action" validate:"required,oneof=dismiss hide_content suspend_user"`
+	Note          string    `json:"note" validate:"max=500"`
+	ModeratorID   uuid.UUID
+	ModeratorRole string
+}
+
+type ReportResponse struct {
+	ID          uuid.UUID     `json:"id"`
+	TargetType  string        `json:"target_type"`
+	TargetID    uuid.UUID     `json:"target_id"`
+	Reason      string        `json:"reason"`
+	Note        string        `json:"note,omitempty"`
+	Status      string        `json:"status"`
+	ReportCount int           `json:"report_count,omitzero"`
+	Reporter    *UserResponse `json:"reporter,omitzero"`
+	ResolvedAt  *time.Time    `json:"resolved_at,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+type ReportListResponse struct {
+	Items      []*ReportResponse `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+type AuditLogResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	ModeratorID *uuid.UUID `json:"moderator_id"`
+	Action      string     `json:"action"`
+	ReportID    *uuid.UUID `json:"report_id,omitempty"`
+	TargetType  string     `json:"target_type"`
+	TargetID    uuid.UUID  `json:"target_id"`
+	Note        string     `json:"note,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
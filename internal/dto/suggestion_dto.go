@@ -0,0 +1,7 @@
+package dto
+
+type SuggestionResponse struct {
+	User            *UserResponse   `json:"user"`
+	MutualCount     int             `json:"mutual_count"`
+	MutualFollowers []*UserResponse `json:"mutual_followers"`
+}
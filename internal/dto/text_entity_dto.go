@@ -0,0 +1,8 @@
+package dto
+
+type TextEntityResponse struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
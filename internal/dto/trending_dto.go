@@ -0,0 +1,6 @@
+package dto
+
+type TrendingTagResponse struct {
+	Tag   string  `json:"tag"`
+	Score float64 `json:"score"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+type CreateUploadRequest struct {
+	Length   int64
+	Metadata map[string]string
+	UserID   uuid.UUID
+}
+
+type AppendUploadRequest struct {
+	UploadID string
+	Offset   int64
+	UserID   uuid.UUID
+}
+
+type UploadResponse struct {
+	ID       string `json:"id"`
+	Length   int64  `json:"length"`
+	Offset   int64  `json:"offset"`
+	Complete bool   `json:"complete"`
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type CreateUserRequest struct {
 	Username string `form:"username" validate:"required"`
@@ -15,18 +19,40 @@ type LoginRequest struct {
 }
 
 type UpdatedUserRequest struct {
-	Username string `form:"username" validate:"required"`
-	Avatar   string `form:"avatar"`
-	Bio      string `form:"bio"`
+	Username  string `form:"username" validate:"required"`
+	Avatar    string `form:"avatar"`
+	Bio       string `form:"bio"`
+	IsPrivate *bool  `form:"is_private"`
 }
 
 type UserResponse struct {
-	ID        string    `json:"id,omitzero"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email,omitzero"`
-	Avatar    string    `json:"avatar"`
-	Bio       string    `json:"bio,omitzero"`
-	Followers int       `json:"followers,omitzero"`
-	Following int       `json:"following,omitzero"`
-	CreatedAt time.Time `json:"created_at,omitzero"`
+	ID          string     `json:"id,omitzero"`
+	Username    string     `json:"username"`
+	Email       string     `json:"email,omitzero"`
+	Avatar      string     `json:"avatar"`
+	Bio         string     `json:"bio,omitzero"`
+	Followers   int        `json:"followers,omitzero"`
+	Following   int        `json:"following,omitzero"`
+	IsPrivate   bool       `json:"is_private,omitzero"`
+	Role        string     `json:"role,omitzero"`
+	SuspendedAt *time.Time `json:"suspended_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at,omitzero"`
+}
+
+type FollowUserResponse struct {
+	User             *UserResponse `json:"user"`
+	FollowedAt       time.Time     `json:"followed_at"`
+	FollowedByViewer bool          `json:"followed_by_viewer"`
+	FollowsViewer    bool          `json:"follows_viewer"`
+}
+
+type FollowListResponse struct {
+	Items      []*FollowUserResponse `json:"items"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+type FollowRequestResponse struct {
+	ID        uuid.UUID     `json:"id"`
+	Requester *UserResponse `json:"requester"`
+	CreatedAt time.Time     `json:"created_at"`
 }
@@ -6,13 +6,30 @@ import (
 	"github.com/google/uuid"
 )
 
+// Orders in which top-level comments can be listed. Top ranks comments by
+// their like count.
+const (
+	CommentSortNewest = "newest"
+	CommentSortOldest = "oldest"
+	CommentSortTop    = "top"
+)
+
 type Comment struct {
-	ID        uuid.UUID `db:"id"`
-	UserID    uuid.UUID `db:"user_id"`
-	FeedID    uuid.UUID `db:"feed_id"`
-	ParentID  uuid.UUID `db:"parent_id"`
-	Comment   string    `db:"comment"`
-	ReplyCout int       `db:"reply_count"`
-	CreatedAt time.Time `db:"created_at"`
-	User      *User
+	ID             uuid.UUID  `db:"id"`
+	UserID         uuid.UUID  `db:"user_id"`
+	FeedID         uuid.UUID  `db:"feed_id"`
+	ParentID       uuid.UUID  `db:"parent_id"`
+	Comment        string     `db:"comment"`
+	IsHidden       bool       `db:"is_hidden"`
+	ReplyCout      int        `db:"reply_count"`
+	LikeCount      int        `db:"like_count"`
+	LikedByMe      bool       `db:"liked_by_me"`
+	IsPinned       bool       `db:"is_pinned"`
+	CreatedAt      time.Time  `db:"created_at"`
+	EditedAt       *time.Time `db:"edited_at"`
+	DeletedAt      *time.Time `db:"deleted_at"`
+	User           *User
+	Hashtags       []string
+	Mentions       []string
+	MentionedUsers []uuid.UUID
 }
@@ -6,21 +6,95 @@ import (
 	"github.com/google/uuid"
 )
 
+// Feed is a post. Only published feeds are shown to anyone; drafts and
+// scheduled feeds are seen by their author alone, and a scheduled feed is
+// published once PublishAt has passed. A quote post embeds the feed at
+// QuotedFeedID, which is loaded into QuotedFeed only while the viewer may see
+// it. RepostedBy is set when a feed is listed because someone reposted it.
 type Feed struct {
-	ID        uuid.UUID `db:"id"`
-	UserID    uuid.UUID `db:"user_id"`
-	Caption   string    `db:"caption"`
-	User      *User
-	Medias    []*FeedMedia
-	Likes     int
-	Comments  int
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID             uuid.UUID  `db:"id"`
+	UserID         uuid.UUID  `db:"user_id"`
+	Caption        string     `db:"caption"`
+	IsHidden       bool       `db:"is_hidden"`
+	Status         string     `db:"status"`
+	PublishAt      *time.Time `db:"publish_at"`
+	QuotedFeedID   *uuid.UUID `db:"quoted_feed_id"`
+	QuotedFeed     *Feed
+	RepostedBy     *User
+	User           *User
+	Medias         []*FeedMedia
+	ReactionCount  int
+	Reactions      map[string]int
+	Reposts        int
+	Comments       int
+	Hashtags       []string
+	Mentions       []string
+	MentionedUsers []uuid.UUID
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
 }
 
+const (
+	FeedDraft     = "draft"
+	FeedScheduled = "scheduled"
+	FeedPublished = "published"
+)
+
+const (
+	MediaImage = "image"
+	MediaVideo = "video"
+)
+
+// Media go from pending, when the feed is created, through processing in the
+// worker to ready or failed. Url is empty until the media is ready.
+const (
+	MediaPending    = "pending"
+	MediaProcessing = "processing"
+	MediaReady      = "ready"
+	MediaFailed     = "failed"
+)
+
+// FeedMedia is a stored media file. Processed images also have Variants, a
+// map of variant name to URL, and a Blurhash placeholder; videos have a
+// poster frame. Position orders the media of a feed, starting at zero.
+// BlobID is the stored content the media shares with identical uploads.
 type FeedMedia struct {
-	ID     uuid.UUID `db:"id"`
-	FeedId uuid.UUID `db:"feed_id"`
-	Url    string    `db:"id"`
-	Type   string    `db:"id"`
+	ID         uuid.UUID `db:"id"`
+	FeedId     uuid.UUID `db:"feed_id"`
+	Url        string    `db:"url"`
+	Type       string    `db:"type"`
+	Status     string    `db:"status"`
+	Position   int
+	AltText    string
+	Caption    string
+	Variants   map[string]string
+	Blurhash   string
+	PosterUrl  string
+	DurationMs int
+	Width      int
+	Height     int
+	BlobID     *uuid.UUID
+}
+
+// MediaBlob is processed media content, stored once however many feed media
+// were uploaded with the same bytes. SHA256 is the hex digest of the uploaded
+// file and PHash the perceptual hash of the image or video poster, if any.
+// Keys are the stored objects. RefCount is kept by the database as feed
+// media point to the blob and go away.
+type MediaBlob struct {
+	ID         uuid.UUID
+	SHA256     string
+	PHash      *uint64
+	Type       string
+	Url        string
+	Variants   map[string]string
+	Blurhash   string
+	PosterUrl  string
+	DurationMs int
+	Width      int
+	Height     int
+	Keys       []string
+	RefCount   int
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
 }
@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	NotificationFeedMention    = "feed_mention"
+	NotificationCommentMention = "comment_mention"
+	NotificationRepost         = "repost"
+	NotificationQuote          = "quote"
+)
+
+type Notification struct {
+	ID        uuid.UUID  `db:"id"`
+	UserID    uuid.UUID  `db:"user_id"`
+	ActorID   uuid.UUID  `db:"actor_id"`
+	Type      string     `db:"type"`
+	FeedID    *uuid.UUID `db:"feed_id"`
+	CommentID *uuid.UUID `db:"comment_id"`
+	IsRead    bool       `db:"is_read"`
+	CreatedAt time.Time  `db:"created_at"`
+	Actor     *User
+}
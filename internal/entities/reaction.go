@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReactionLike is the reaction the legacy like endpoint toggles.
+const ReactionLike = "like"
+
+type Reaction struct {
+	FeedID    uuid.UUID `db:"feed_id"`
+	UserID    uuid.UUID `db:"user_id"`
+	Type      string    `db:"type"`
+	CreatedAt time.Time `db:"created_at"`
+	User      *User
+}
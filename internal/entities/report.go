@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ReportTargetFeed    = "feed"
+	ReportTargetComment = "comment"
+	ReportTargetUser    = "user"
+)
+
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusDismissed = "dismissed"
+	ReportStatusActioned  = "actioned"
+)
+
+const (
+	ModerationActionDismiss     = "dismiss"
+	ModerationActionHideContent = "hide_content"
+	ModerationActionSuspendUser = "suspend_user"
+	ModerationActionAutoHide    = "auto_hide"
+	ModerationActionUnsuspend   = "unsuspend_user"
+	ModerationActionChangeRole  = "change_role"
+	ModerationActionFilterHold  = "filter_hold"
+)
+
+// ReportReasonContentFilter marks reports filed by the content filter rather
+// than by a user.
+const ReportReasonContentFilter = "content_filter"
+
+type Report struct {
+	ID          uuid.UUID  `db:"id"`
+	ReporterID  uuid.UUID  `db:"reporter_id"`
+	TargetType  string     `db:"target_type"`
+	TargetID    uuid.UUID  `db:"target_id"`
+	Reason      string     `db:"reason"`
+	Note        string     `db:"note"`
+	Status      string     `db:"status"`
+	ResolvedBy  *uuid.UUID `db:"resolved_by"`
+	ResolvedAt  *time.Time `db:"resolved_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+	ReportCount int        `db:"report_count"`
+	Reporter    *User
+}
+
+type AuditLog struct {
+	ID          uuid.UUID  `db:"id"`
+	ModeratorID *uuid.UUID `db:"moderator_id"`
+	Action      string     `db:"action"`
+	ReportID    *uuid.UUID `db:"report_id"`
+	TargetType  string     `db:"target_type"`
+	TargetID    uuid.UUID  `db:"target_id"`
+	Note        string     `db:"note"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
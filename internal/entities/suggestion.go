@@ -0,0 +1,7 @@
+package entities
+
+type Suggestion struct {
+	User        *User
+	MutualCount int
+	Mutuals     []*User
+}
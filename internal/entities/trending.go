@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+const (
+	EngagementPost     = "post"
+	EngagementReaction = "reaction"
+	EngagementComment  = "comment"
+)
+
+type Engagement struct {
+	Key       string    `db:"key"`
+	Kind      string    `db:"kind"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type RankedItem struct {
+	Key   string
+	Score float64
+}
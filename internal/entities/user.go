@@ -7,14 +7,33 @@ import (
 )
 
 type User struct {
-	ID         uuid.UUID `db:"id"`
-	Username   string    `db:"username"`
-	Email      string    `db:"email"`
-	Password   string    `db:"password"`
-	Avatar     string    `db:"avatar"`
-	Bio        string    `db:"bio"`
-	Followers  int       `db:"followers_count"`
-	Followings int       `db:"followings_count"`
-	CreatedAt  time.Time `db:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at"`
+	ID          uuid.UUID  `db:"id"`
+	Username    string     `db:"username"`
+	Email       string     `db:"email"`
+	Password    string     `db:"password"`
+	Avatar      string     `db:"avatar"`
+	Bio         string     `db:"bio"`
+	IsPrivate   bool       `db:"is_private"`
+	Role        string     `db:"role"`
+	SuspendedAt *time.Time `db:"suspended_at"`
+	Followers   int        `db:"followers_count"`
+	Followings  int        `db:"followings_count"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+type FollowUser struct {
+	FollowID         uuid.UUID `db:"follow_id"`
+	FollowedAt       time.Time `db:"followed_at"`
+	FollowedByViewer bool      `db:"followed_by_viewer"`
+	FollowsViewer    bool      `db:"follows_viewer"`
+	User             *User
+}
+
+type FollowRequest struct {
+	ID          uuid.UUID `db:"id"`
+	RequesterID uuid.UUID `db:"requester_id"`
+	TargetID    uuid.UUID `db:"target_id"`
+	CreatedAt   time.Time `db:"created_at"`
+	Requester   *User
 }
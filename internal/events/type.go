@@ -4,6 +4,9 @@ import "encoding/json"
 
 type EventType string
 
+// Queue is the queue events are published to and consumed from.
+const Queue = "events"
+
 const (
 	UploadFeedMedias EventType = "upload_feed_medias"
 	DeleteFeedMedias EventType = "delete_feed_medias"
@@ -19,11 +22,15 @@ type UploadPayload struct {
 	Content []ContentData `json:"content"`
 }
 
+// ContentData points at an uploaded temp file and the pending media it fills.
+// Messages queued before media were created up front have no MediaID.
 type ContentData struct {
+	MediaID  string `json:"media_id,omitempty"`
 	FilePath string `json:"file_path"`
 	FileType string `json:"file_type"`
 }
 
+// DeletePayload names the storage keys of the media to delete.
 type DeletePayload struct {
-	PublicID string `json:"public_id"`
+	Keys []string `json:"keys"`
 }
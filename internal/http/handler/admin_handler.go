@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/services"
+	"github.com/davidafdal/post-app/pkg/response"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type AdminHandler struct {
+	adminService services.AdminService
+}
+
+func NewAdminHandler(adminService services.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+func (h *AdminHandler) GetUsers(c echo.Context) error {
+	users, err := h.adminService.GetUsers(c.Request().Context(), c.QueryParam("search"), c.QueryParam("cursor"), pageLimit(c))
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success get users", users)
+}
+
+func (h *AdminHandler) ChangeRole(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	req := new(dto.ChangeRoleRequest)
+
+	if err := c.Bind(req); err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if errMessage, data := checkValidation(req); errMessage != "" {
+		return response.SuccessResponse(c, http.StatusBadRequest, errMessage, data)
+	}
+
+	req.ActorID = userID
+	req.ActorRole = c.Get("user_role").(string)
+
+	if err := h.adminService.ChangeRole(c.Request().Context(), req); err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success change user role", nil)
+}
+
+func (h *AdminHandler) SuspendUser(c echo.Context) error {
+	return h.suspensionAction(c, h.adminService.SuspendUser, "success suspend user")
+}
+
+func (h *AdminHandler) UnsuspendUser(c echo.Context) error {
+	return h.suspensionAction(c, h.adminService.UnsuspendUser, "success unsuspend user")
+}
+
+func (h *AdminHandler) suspensionAction(c echo.Context, action func(context.Context, uuid.UUID, string, uuid.UUID) error, message string) error {
+	id := c.Get("user_id").(string)
+	actorID := uuid.MustParse(id)
+	actorRole := c.Get("user_role").(string)
+
+	targetID, err := uuid.Parse(c.Param("user_id"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid user id")
+	}
+
+	if err := action(c.Request().Context(), actorID, actorRole, targetID); err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, message, nil)
+}
@@ -35,11 +35,13 @@ func (h *CommentHandler) CreateComment(c echo.Context) error {
 
 	req.SenderID = userID
 
-	if err := h.commentService.CreateComment(c.Request().Context(), req); err != nil {
-		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	comment, err := h.commentService.CreateComment(c.Request().Context(), req)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
 	}
 
-	return response.SuccessResponse(c, http.StatusCreated, "success create a comment", nil)
+	return response.SuccessResponse(c, http.StatusCreated, "success create a comment", comment)
 }
 
 func (h *CommentHandler) CreateReplyComment(c echo.Context) error {
@@ -58,32 +60,137 @@ func (h *CommentHandler) CreateReplyComment(c echo.Context) error {
 
 	req.SenderID = senderID
 
-	if err := h.commentService.CreateCommentReplies(c.Request().Context(), req); err != nil {
-		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	comment, err := h.commentService.CreateCommentReplies(c.Request().Context(), req)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
 	}
 
-	return response.SuccessResponse(c, http.StatusCreated, "succes create a new comment", nil)
+	return response.SuccessResponse(c, http.StatusCreated, "succes create a new comment", comment)
 }
 
 func (h *CommentHandler) GetTopLevelComment(c echo.Context) error {
-	feedID := uuid.MustParse(c.Param("feed_id"))
+	payloadID := c.Get("user_id").(string)
+	viewerID := uuid.MustParse(payloadID)
 
-	responData, err := h.commentService.GetTopLevelComment(c.Request().Context(), feedID)
+	feedID, err := uuid.Parse(c.Param("feed_id"))
 
 	if err != nil {
-		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid feed id")
+	}
+
+	responData, err := h.commentService.GetTopLevelComment(c.Request().Context(), feedID, viewerID, c.QueryParam("sort"), c.QueryParam("cursor"), pageLimit(c))
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
 	}
 
 	return response.SuccessResponse(c, http.StatusOK, "success get top level comment", responData)
 }
 
 func (h *CommentHandler) GetCommentReplies(c echo.Context) error {
-	commentID := uuid.MustParse(c.Param("comment_id"))
+	payloadID := c.Get("user_id").(string)
+	viewerID := uuid.MustParse(payloadID)
 
-	responData, err := h.commentService.GetRepliedComment(c.Request().Context(), commentID)
+	commentID, err := uuid.Parse(c.Param("comment_id"))
 
 	if err != nil {
-		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid comment id")
+	}
+
+	responData, err := h.commentService.GetRepliedComment(c.Request().Context(), commentID, viewerID, c.QueryParam("cursor"), pageLimit(c))
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
 	}
 	return response.SuccessResponse(c, http.StatusOK, "success get top reply comment", responData)
 }
+
+func (h *CommentHandler) UpdateComment(c echo.Context) error {
+	payloadID := c.Get("user_id").(string)
+	userID := uuid.MustParse(payloadID)
+	req := new(dto.UpdateCommentRequest)
+
+	if err := c.Bind(req); err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if errMsg, data := checkValidation(req); errMsg != "" {
+		return response.SuccessResponse(c, http.StatusBadRequest, errMsg, data)
+	}
+
+	req.UserID = userID
+
+	comment, err := h.commentService.UpdateComment(c.Request().Context(), req)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success update comment", comment)
+}
+
+func (h *CommentHandler) DeleteComment(c echo.Context) error {
+	payloadID := c.Get("user_id").(string)
+	userID := uuid.MustParse(payloadID)
+
+	commentID, err := uuid.Parse(c.Param("comment_id"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid comment id")
+	}
+
+	if err := h.commentService.DeleteComment(c.Request().Context(), commentID, userID); err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success delete comment", nil)
+}
+
+func (h *CommentHandler) PinComment(c echo.Context) error {
+	payloadID := c.Get("user_id").(string)
+	userID := uuid.MustParse(payloadID)
+
+	feedID, err := uuid.Parse(c.Param("feed_id"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid feed id")
+	}
+
+	commentID, err := uuid.Parse(c.Param("comment_id"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid comment id")
+	}
+
+	status, err := h.commentService.PinComment(c.Request().Context(), feedID, commentID, userID)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success pin comment", map[string]interface{}{
+		"status": status,
+	})
+}
+
+func (h *CommentHandler) LikeComment(c echo.Context) error {
+	payloadID := c.Get("user_id").(string)
+	userID := uuid.MustParse(payloadID)
+
+	commentID, err := uuid.Parse(c.Param("comment_id"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid comment id")
+	}
+
+	status, err := h.commentService.LikeComment(c.Request().Context(), commentID, userID)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success like comment", map[string]interface{}{
+		"status": status,
+	})
+}
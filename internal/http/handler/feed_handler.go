@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"mime/multipart"
 	"net/http"
 
 	"github.com/davidafdal/post-app/internal/dto"
@@ -35,22 +37,24 @@ func (h *FeedHandler) CreateFeed(c echo.Context) error {
 
 	req.UserID = userID
 
-	form, err := c.MultipartForm()
+	// Media may be all resumable uploads, in which case the request needs
+	// not be multipart.
+	var files []*multipart.FileHeader
 
-	if err != nil {
+	if form, err := c.MultipartForm(); err == nil {
+		files = form.File["files"]
+	} else if !errors.Is(err, http.ErrNotMultipart) {
 		return response.ErrorResponse(c, http.StatusBadRequest, "invalid multipart form")
 	}
 
-	files := form.File["files"]
-
-	if len(files) == 0 {
+	if len(files) == 0 && len(req.UploadIDs) == 0 && req.QuotedFeedID == nil {
 		return response.ErrorResponse(c, http.StatusBadRequest, "no files uploaded")
 	}
 
 	feed, err := h.feedService.CreateFeed(c.Request().Context(), req, files)
 
 	if err != nil {
-		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return uploadErrorResponse(c, errorStatus(err), err)
 	}
 
 	return response.SuccessResponse(c, http.StatusOK, "success create feed", feed)
@@ -69,20 +73,296 @@ func (h *FeedHandler) GetFeeds(c echo.Context) error {
 	return response.SuccessResponse(c, http.StatusOK, "success get home feeds", feeds)
 }
 
-func (h *UserHandler) FollowingUser(c echo.Context) error {
+func (h *FeedHandler) UpdateFeed(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+	req := new(dto.UpdateFeedRequest)
+
+	if err := c.Bind(req); err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if errMessage, data := checkValidation(req); errMessage != "" {
+		return response.SuccessResponse(c, http.StatusBadRequest, errMessage, data)
+	}
+
+	req.UserID = userID
+
+	feed, err := h.feedService.UpdateFeedCaption(c.Request().Context(), req)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success update feed", feed)
+}
+
+func (h *FeedHandler) GetDrafts(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	feeds, err := h.feedService.GetDrafts(c.Request().Context(), userID)
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success get drafts", feeds)
+}
+
+func (h *FeedHandler) UpdateDraft(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+	req := new(dto.UpdateDraftRequest)
+
+	if err := c.Bind(req); err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if errMessage, data := checkValidation(req); errMessage != "" {
+		return response.SuccessResponse(c, http.StatusBadRequest, errMessage, data)
+	}
+
+	req.UserID = userID
+
+	feed, err := h.feedService.UpdateDraft(c.Request().Context(), req)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success update draft", feed)
+}
+
+func (h *FeedHandler) GetFeedsByHashtag(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+	tag := c.Param("tag")
+
+	feeds, err := h.feedService.GetFeedsByHashtag(c.Request().Context(), tag, userID)
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success get feeds by hashtag", feeds)
+}
+
+func (h *FeedHandler) GetUserFeeds(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	feeds, err := h.feedService.GetUserFeeds(c.Request().Context(), c.Param("username"), userID)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success get user feeds", feeds)
+}
+
+func (h *FeedHandler) GetExploreFeeds(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	feeds, err := h.feedService.GetExploreFeeds(c.Request().Context(), userID, pageLimit(c))
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success get explore feeds", feeds)
+}
+
+func (h *FeedHandler) MarkFeedsSeen(c echo.Context) error {
 	id := c.Get("user_id").(string)
-	paramId := c.Param("following_id")
+	userID := uuid.MustParse(id)
+	req := new(dto.MarkFeedsSeenRequest)
+
+	if err := c.Bind(req); err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if errMessage, data := checkValidation(req); errMessage != "" {
+		return response.SuccessResponse(c, http.StatusBadRequest, errMessage, data)
+	}
+
+	req.UserID = userID
+
+	if err := h.feedService.MarkFeedsSeen(c.Request().Context(), req); err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success mark feeds as seen", nil)
+}
+
+func (h *FeedHandler) ReactToFeed(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+	req := new(dto.ReactFeedRequest)
+
+	if err := c.Bind(req); err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if errMessage, data := checkValidation(req); errMessage != "" {
+		return response.SuccessResponse(c, http.StatusBadRequest, errMessage, data)
+	}
 
+	req.UserID = userID
+
+	status, err := h.feedService.ReactToFeed(c.Request().Context(), req)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success react to feed", map[string]interface{}{
+		"status": status,
+	})
+}
+
+func (h *FeedHandler) RemoveReaction(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	feedID, err := uuid.Parse(c.Param("feed_id"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid feed id")
+	}
+
+	if err := h.feedService.RemoveReaction(c.Request().Context(), feedID, userID); err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success remove reaction", nil)
+}
+
+func (h *FeedHandler) RepostFeed(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	feedID, err := uuid.Parse(c.Param("feed_id"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid feed id")
+	}
+
+	status, err := h.feedService.RepostFeed(c.Request().Context(), feedID, userID)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success repost feed", map[string]interface{}{
+		"status": status,
+	})
+}
+
+func (h *FeedHandler) GetFeedReactions(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	viewerID := uuid.MustParse(id)
+
+	feedID, err := uuid.Parse(c.Param("feed_id"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid feed id")
+	}
+
+	reactions, err := h.feedService.GetFeedReactions(c.Request().Context(), feedID, viewerID, c.QueryParam("type"), c.QueryParam("cursor"), pageLimit(c))
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success get feed reactions", reactions)
+}
+
+func (h *FeedHandler) GetFeedMedias(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	viewerID := uuid.MustParse(id)
+
+	feedID, err := uuid.Parse(c.Param("feed_id"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid feed id")
+	}
+
+	medias, err := h.feedService.GetFeedMedias(c.Request().Context(), feedID, viewerID)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success get feed medias", medias)
+}
+
+func (h *UserHandler) FollowingUser(c echo.Context) error {
+	id := c.Get("user_id").(string)
 	followerID := uuid.MustParse(id)
-	followingID := uuid.MustParse(paramId)
+
+	followingID, err := uuid.Parse(c.Param("following_id"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid user id")
+	}
 
 	status, err := h.userService.FollowUser(followerID, followingID)
 
 	if err != nil {
-		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
 	}
 
 	return response.SuccessResponse(c, http.StatusOK, "succes following user", map[string]interface{}{
 		"status": status,
 	})
 }
+
+func (h *FeedHandler) UpdateMedia(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+	req := new(dto.UpdateMediaRequest)
+
+	if err := c.Bind(req); err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if errMessage, data := checkValidation(req); errMessage != "" {
+		return response.SuccessResponse(c, http.StatusBadRequest, errMessage, data)
+	}
+
+	req.UserID = userID
+
+	media, err := h.feedService.UpdateMedia(c.Request().Context(), req)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success update media", media)
+}
+
+func (h *FeedHandler) ReorderMedias(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+	req := new(dto.ReorderMediasRequest)
+
+	if err := c.Bind(req); err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if errMessage, data := checkValidation(req); errMessage != "" {
+		return response.SuccessResponse(c, http.StatusBadRequest, errMessage, data)
+	}
+
+	req.UserID = userID
+
+	medias, err := h.feedService.ReorderMedias(c.Request().Context(), req)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success reorder medias", medias)
+}
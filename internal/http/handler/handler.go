@@ -1,18 +1,49 @@
 package handler
 
-import "github.com/davidafdal/post-app/pkg/validator"
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/davidafdal/post-app/internal/services"
+	"github.com/davidafdal/post-app/pkg/cursor"
+	"github.com/davidafdal/post-app/pkg/mediacheck"
+	"github.com/davidafdal/post-app/pkg/response"
+	"github.com/davidafdal/post-app/pkg/validator"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
 
 type Handler struct {
-	UserHandler    *UserHandler
-	FeedHandler    *FeedHandler
-	CommentHandler *CommentHandler
+	UserHandler         *UserHandler
+	FeedHandler         *FeedHandler
+	CommentHandler      *CommentHandler
+	NotificationHandler *NotificationHandler
+	TrendingHandler     *TrendingHandler
+	SuggestionHandler   *SuggestionHandler
+	ReportHandler       *ReportHandler
+	AdminHandler        *AdminHandler
+	SocketHandler       *SocketHandler
+	UploadHandler       *UploadHandler
 }
 
-func NewHandler(userhHandler *UserHandler, feedHnadler *FeedHandler, commentHandler *CommentHandler) Handler {
+func NewHandler(userhHandler *UserHandler, feedHnadler *FeedHandler, commentHandler *CommentHandler, notificationHandler *NotificationHandler, trendingHandler *TrendingHandler, suggestionHandler *SuggestionHandler, reportHandler *ReportHandler, adminHandler *AdminHandler, socketHandler *SocketHandler, uploadHandler *UploadHandler) Handler {
 	return Handler{
-		UserHandler:    userhHandler,
-		FeedHandler:    feedHnadler,
-		CommentHandler: commentHandler,
+		UserHandler:         userhHandler,
+		FeedHandler:         feedHnadler,
+		CommentHandler:      commentHandler,
+		NotificationHandler: notificationHandler,
+		TrendingHandler:     trendingHandler,
+		SuggestionHandler:   suggestionHandler,
+		ReportHandler:       reportHandler,
+		AdminHandler:        adminHandler,
+		SocketHandler:       socketHandler,
+		UploadHandler:       uploadHandler,
 	}
 }
 
@@ -26,3 +57,67 @@ func checkValidation(input interface{}) (errorMessage string, data interface{})
 	}
 	return "", nil
 }
+
+func pageLimit(c echo.Context) int {
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		return defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		return maxPageLimit
+	}
+	return limit
+}
+
+// uploadErrorResponse writes the error of a request carrying files. Uploads
+// rejected by validation get 422 and the list of files that failed; other
+// errors get status.
+func uploadErrorResponse(c echo.Context, status int, err error) error {
+	var invalid *mediacheck.ValidationError
+	if errors.As(err, &invalid) {
+		return response.ErrorResponseWithData(c, http.StatusUnprocessableEntity, invalid.Message, invalid.Files)
+	}
+	return response.ErrorResponse(c, status, err.Error())
+}
+
+// errorStatus maps the errors returned by services to a HTTP status code.
+func errorStatus(err error) int {
+	var notFound *services.NotFoundError
+	var forbidden *services.ForbiddenError
+
+	switch {
+	case errors.As(err, &notFound),
+		errors.Is(err, repositories.ErrFeedNotFound),
+		errors.Is(err, repositories.ErrCommentNotFound),
+		errors.Is(err, repositories.ErrReportNotFound),
+		errors.Is(err, repositories.ErrReportTargetNotFound):
+		return http.StatusNotFound
+	case errors.As(err, &forbidden):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrContentRejected),
+		errors.Is(err, services.ErrMediaBlocked):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrUploadOffsetMismatch),
+		errors.Is(err, services.ErrFeedPublished),
+		errors.Is(err, services.ErrReportResolved):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrUploadTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, cursor.ErrInvalidCursor),
+		errors.Is(err, services.ErrSelfAction),
+		errors.Is(err, services.ErrInvalidReportStatus),
+		errors.Is(err, services.ErrInvalidModerationAction),
+		errors.Is(err, services.ErrInvalidRole),
+		errors.Is(err, services.ErrInvalidCommentSort),
+		errors.Is(err, services.ErrInvalidReaction),
+		errors.Is(err, services.ErrInvalidUploadLength),
+		errors.Is(err, services.ErrUploadIncomplete),
+		errors.Is(err, services.ErrTooManyMediaDetails),
+		errors.Is(err, services.ErrInvalidMediaOrder),
+		errors.Is(err, services.ErrInvalidPublishAt),
+		errors.Is(err, services.ErrInvalidSeenFeeds):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/davidafdal/post-app/internal/services"
+	"github.com/davidafdal/post-app/pkg/response"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationHandler struct {
+	notificationService services.NotificationService
+}
+
+func NewNotificationHandler(notificationService services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+func (h *NotificationHandler) GetNotifications(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	notifications, err := h.notificationService.GetNotifications(c.Request().Context(), userID)
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success get notifications", notifications)
+}
+
+func (h *NotificationHandler) ReadAllNotifications(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	if err := h.notificationService.ReadAllNotifications(c.Request().Context(), userID); err != nil {
+		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success read all notifications", nil)
+}
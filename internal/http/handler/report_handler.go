@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/services"
+	"github.com/davidafdal/post-app/pkg/response"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type ReportHandler struct {
+	reportService services.ReportService
+}
+
+func NewReportHandler(reportService services.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+func (h *ReportHandler) CreateReport(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	req := new(dto.CreateReportRequest)
+
+	if err := c.Bind(req); err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if errMessage, data := checkValidation(req); errMessage != "" {
+		return response.SuccessResponse(c, http.StatusBadRequest, errMessage, data)
+	}
+
+	req.ReporterID = userID
+
+	report, err := h.reportService.CreateReport(c.Request().Context(), req)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, "success create report", report)
+}
+
+func (h *ReportHandler) GetReports(c echo.Context) error {
+	reports, err := h.reportService.GetReports(c.Request().Context(), c.QueryParam("status"), c.QueryParam("cursor"), pageLimit(c))
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success get reports", reports)
+}
+
+func (h *ReportHandler) TakeAction(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	req := new(dto.ModerationActionRequest)
+
+	if err := c.Bind(req); err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if errMessage, data := checkValidation(req); errMessage != "" {
+		return response.SuccessResponse(c, http.StatusBadRequest, errMessage, data)
+	}
+
+	req.ModeratorID = userID
+	req.ModeratorRole = c.Get("user_role").(string)
+
+	if err := h.reportService.TakeAction(c.Request().Context(), req); err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success take moderation action", nil)
+}
+
+func (h *ReportHandler) GetAuditLogs(c echo.Context) error {
+	logs, err := h.reportService.GetAuditLogs(c.Request().Context(), pageLimit(c))
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success get audit logs", logs)
+}
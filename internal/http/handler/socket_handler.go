@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/davidafdal/post-app/pkg/socket"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+type SocketHandler struct {
+	hub      *socket.Hub
+	upgrader websocket.Upgrader
+}
+
+func NewSocketHandler(hub *socket.Hub) *SocketHandler {
+	return &SocketHandler{
+		hub: hub,
+		upgrader: websocket.Upgrader{
+			// The connection is authenticated by its token, not by cookies,
+			// so any origin may connect.
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// Connect upgrades the request to a websocket that receives the events of the
+// logged in user, such as media.processed, until it is closed.
+func (h *SocketHandler) Connect(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	conn, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+
+	if err != nil {
+		return nil
+	}
+
+	h.hub.Serve(userID, conn)
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/davidafdal/post-app/internal/services"
+	"github.com/davidafdal/post-app/pkg/response"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type SuggestionHandler struct {
+	suggestionService services.SuggestionService
+}
+
+func NewSuggestionHandler(suggestionService services.SuggestionService) *SuggestionHandler {
+	return &SuggestionHandler{
+		suggestionService: suggestionService,
+	}
+}
+
+func (h *SuggestionHandler) GetSuggestions(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	suggestions, err := h.suggestionService.GetSuggestions(c.Request().Context(), userID)
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success get follow suggestions", suggestions)
+}
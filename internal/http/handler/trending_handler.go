@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davidafdal/post-app/internal/services"
+	"github.com/davidafdal/post-app/pkg/response"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type TrendingHandler struct {
+	trendingService services.TrendingService
+}
+
+func NewTrendingHandler(trendingService services.TrendingService) *TrendingHandler {
+	return &TrendingHandler{
+		trendingService: trendingService,
+	}
+}
+
+func (h *TrendingHandler) GetTrendingTags(c echo.Context) error {
+	window, limit := trendingQuery(c)
+
+	tags, err := h.trendingService.GetTrendingTags(c.Request().Context(), window, limit)
+
+	if errors.Is(err, services.ErrInvalidTrendingWindow) {
+		return response.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success get trending tags", tags)
+}
+
+func (h *TrendingHandler) GetTrendingFeeds(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+	window, limit := trendingQuery(c)
+
+	feeds, err := h.trendingService.GetTrendingFeeds(c.Request().Context(), userID, window, limit)
+
+	if errors.Is(err, services.ErrInvalidTrendingWindow) {
+		return response.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success get trending feeds", feeds)
+}
+
+func trendingQuery(c echo.Context) (string, int) {
+	window := c.QueryParam("window")
+	if window == "" {
+		window = services.DefaultTrendingWindow
+	}
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	return window, limit
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/services"
+	"github.com/davidafdal/post-app/pkg/response"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// The tus resumable upload protocol, core and the creation and termination
+// extensions. See https://tus.io/protocols/resumable-upload.
+const (
+	tusVersion     = "1.0.0"
+	tusExtensions  = "creation,termination"
+	tusContentType = "application/offset+octet-stream"
+)
+
+type UploadHandler struct {
+	uploadService services.UploadService
+}
+
+func NewUploadHandler(uploadService services.UploadService) *UploadHandler {
+	return &UploadHandler{uploadService}
+}
+
+// Options tells tus clients what this server supports.
+func (h *UploadHandler) Options(c echo.Context) error {
+	header := c.Response().Header()
+	header.Set("Tus-Resumable", tusVersion)
+	header.Set("Tus-Version", tusVersion)
+	header.Set("Tus-Extension", tusExtensions)
+
+	if maxSize := h.uploadService.MaxSize(); maxSize > 0 {
+		header.Set("Tus-Max-Size", strconv.FormatInt(maxSize, 10))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *UploadHandler) CreateUpload(c echo.Context) error {
+	if !checkTusVersion(c) {
+		return nil
+	}
+
+	length, err := strconv.ParseInt(c.Request().Header.Get("Upload-Length"), 10, 64)
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid Upload-Length header")
+	}
+
+	metadata, err := parseUploadMetadata(c.Request().Header.Get("Upload-Metadata"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid Upload-Metadata header")
+	}
+
+	id := c.Get("user_id").(string)
+
+	created, err := h.uploadService.CreateUpload(c.Request().Context(), &dto.CreateUploadRequest{
+		Length:   length,
+		Metadata: metadata,
+		UserID:   uuid.MustParse(id),
+	})
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	c.Response().Header().Set("Location", c.Request().URL.Path+"/"+created.ID)
+	setUploadHeaders(c, created)
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// GetUpload answers a tus HEAD request with the current offset in the
+// headers. Plain GET requests get the same progress as JSON.
+func (h *UploadHandler) GetUpload(c echo.Context) error {
+	id := c.Get("user_id").(string)
+
+	found, err := h.uploadService.GetUpload(c.Request().Context(), c.Param("upload_id"), uuid.MustParse(id))
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	setUploadHeaders(c, found)
+	c.Response().Header().Set("Cache-Control", "no-store")
+
+	if c.Request().Method == http.MethodHead {
+		return c.NoContent(http.StatusOK)
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success get upload", found)
+}
+
+func (h *UploadHandler) AppendUpload(c echo.Context) error {
+	if !checkTusVersion(c) {
+		return nil
+	}
+
+	if c.Request().Header.Get(echo.HeaderContentType) != tusContentType {
+		return response.ErrorResponse(c, http.StatusUnsupportedMediaType, "Content-Type must be "+tusContentType)
+	}
+
+	offset, err := strconv.ParseInt(c.Request().Header.Get("Upload-Offset"), 10, 64)
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid Upload-Offset header")
+	}
+
+	id := c.Get("user_id").(string)
+
+	appended, err := h.uploadService.AppendUpload(c.Request().Context(), &dto.AppendUploadRequest{
+		UploadID: c.Param("upload_id"),
+		Offset:   offset,
+		UserID:   uuid.MustParse(id),
+	}, c.Request().Body)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	setUploadHeaders(c, appended)
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *UploadHandler) DeleteUpload(c echo.Context) error {
+	if !checkTusVersion(c) {
+		return nil
+	}
+
+	id := c.Get("user_id").(string)
+
+	if err := h.uploadService.DeleteUpload(c.Request().Context(), c.Param("upload_id"), uuid.MustParse(id)); err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	c.Response().Header().Set("Tus-Resumable", tusVersion)
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// checkTusVersion rejects requests made for another version of the protocol.
+// It writes the 412 response and returns false when the request must stop.
+func checkTusVersion(c echo.Context) bool {
+	if version := c.Request().Header.Get("Tus-Resumable"); version != tusVersion {
+		c.Response().Header().Set("Tus-Version", tusVersion)
+		response.ErrorResponse(c, http.StatusPreconditionFailed, "unsupported Tus-Resumable version")
+		return false
+	}
+	return true
+}
+
+func setUploadHeaders(c echo.Context, upload *dto.UploadResponse) {
+	header := c.Response().Header()
+	header.Set("Tus-Resumable", tusVersion)
+	header.Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	header.Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+}
+
+// parseUploadMetadata decodes the comma separated "key base64value" pairs of
+// the Upload-Metadata header. Values may be left out.
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			continue
+		}
+
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, err
+		}
+		metadata[key] = string(value)
+	}
+
+	return metadata, nil
+}
@@ -37,7 +37,7 @@ func (h *UserHandler) GetByUsername(c echo.Context) error {
 	user, err := h.userService.GetUserByUsername(usernameParam)
 
 	if err != nil {
-		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
 	}
 
 	return response.SuccessResponse(c, http.StatusOK, "success get user by username", user)
@@ -58,7 +58,7 @@ func (h *UserHandler) Login(c echo.Context) error {
 	responData, err := h.userService.Login(req)
 
 	if err != nil {
-		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
 	}
 
 	return response.SuccessResponse(c, http.StatusOK, "success login by credentials", responData)
@@ -84,7 +84,7 @@ func (h *UserHandler) Register(c echo.Context) error {
 	user, err := h.userService.Register(req, avatarFile)
 
 	if err != nil {
-		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return uploadErrorResponse(c, http.StatusInternalServerError, err)
 	}
 
 	return response.SuccessResponse(c, http.StatusCreated, "success registed user", user)
@@ -112,7 +112,7 @@ func (h *UserHandler) UpdateUser(c echo.Context) error {
 	user, err := h.userService.UpdateUser(req, avatarFile, userID)
 
 	if err != nil {
-		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return uploadErrorResponse(c, http.StatusInternalServerError, err)
 	}
 
 	return response.SuccessResponse(c, http.StatusOK, "success update data user", user)
@@ -129,19 +129,127 @@ func (h *UserHandler) DeleteUser(c echo.Context) error {
 	return response.SuccessResponse(c, http.StatusOK, "sucess delete user", nil)
 }
 
-func (h *FeedHandler) LikeFeed(c echo.Context) error {
+func (h *UserHandler) GetFollowers(c echo.Context) error {
+	return h.getFollowList(c, h.userService.GetFollowers, "success get followers")
+}
+
+func (h *UserHandler) GetFollowings(c echo.Context) error {
+	return h.getFollowList(c, h.userService.GetFollowings, "success get followings")
+}
+
+func (h *UserHandler) GetMutuals(c echo.Context) error {
+	return h.getFollowList(c, h.userService.GetMutuals, "success get mutual followers")
+}
+
+func (h *UserHandler) getFollowList(c echo.Context, get func(string, uuid.UUID, string, int) (*dto.FollowListResponse, error), message string) error {
 	id := c.Get("user_id").(string)
-	paramId := c.Param("feed_id")
+	viewerID := uuid.MustParse(id)
+
+	list, err := get(c.Param("username"), viewerID, c.QueryParam("cursor"), pageLimit(c))
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, message, list)
+}
+
+func (h *UserHandler) BlockUser(c echo.Context) error {
+	return h.relationAction(c, h.userService.BlockUser, "success block user")
+}
 
+func (h *UserHandler) UnblockUser(c echo.Context) error {
+	return h.relationAction(c, h.userService.UnblockUser, "success unblock user")
+}
+
+func (h *UserHandler) MuteUser(c echo.Context) error {
+	return h.relationAction(c, h.userService.MuteUser, "success mute user")
+}
+
+func (h *UserHandler) UnmuteUser(c echo.Context) error {
+	return h.relationAction(c, h.userService.UnmuteUser, "success unmute user")
+}
+
+func (h *UserHandler) relationAction(c echo.Context, action func(uuid.UUID, uuid.UUID) error, message string) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	targetID, err := uuid.Parse(c.Param("user_id"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid user id")
+	}
+
+	if err := action(userID, targetID); err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, message, nil)
+}
+
+func (h *UserHandler) GetFollowRequests(c echo.Context) error {
+	id := c.Get("user_id").(string)
 	userID := uuid.MustParse(id)
-	feedID := uuid.MustParse(paramId)
 
-	status, err := h.feedService.LikeFeed(feedID, userID)
+	requests, err := h.userService.GetFollowRequests(userID)
 
 	if err != nil {
 		return response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 	}
 
+	return response.SuccessResponse(c, http.StatusOK, "success get follow requests", requests)
+}
+
+func (h *UserHandler) ApproveFollowRequest(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	requestID, err := uuid.Parse(c.Param("request_id"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid follow request id")
+	}
+
+	if err := h.userService.ApproveFollowRequest(requestID, userID); err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success approve follow request", nil)
+}
+
+func (h *UserHandler) RejectFollowRequest(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	requestID, err := uuid.Parse(c.Param("request_id"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid follow request id")
+	}
+
+	if err := h.userService.RejectFollowRequest(requestID, userID); err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, "success reject follow request", nil)
+}
+
+func (h *FeedHandler) LikeFeed(c echo.Context) error {
+	id := c.Get("user_id").(string)
+	userID := uuid.MustParse(id)
+
+	feedID, err := uuid.Parse(c.Param("feed_id"))
+
+	if err != nil {
+		return response.ErrorResponse(c, http.StatusBadRequest, "invalid feed id")
+	}
+
+	status, err := h.feedService.LikeFeed(c.Request().Context(), feedID, userID)
+
+	if err != nil {
+		return response.ErrorResponse(c, errorStatus(err), err.Error())
+	}
+
 	return response.SuccessResponse(c, http.StatusOK, "succes likes feed", map[string]interface{}{
 		"status": status,
 	})
@@ -4,11 +4,13 @@ import (
 	"net/http"
 
 	"github.com/davidafdal/post-app/internal/http/handler"
+	"github.com/davidafdal/post-app/pkg/rbac"
 	"github.com/davidafdal/post-app/pkg/route"
 )
 
 func PublicRoute(handler handler.Handler) []*route.Route {
 	userHandler := handler.UserHandler
+	uploadHandler := handler.UploadHandler
 
 	return []*route.Route{
 		{
@@ -31,6 +33,11 @@ func PublicRoute(handler handler.Handler) []*route.Route {
 			Path:    "/users/:username",
 			Handler: userHandler.GetByUsername,
 		},
+		{
+			Method:  http.MethodOptions,
+			Path:    "/uploads",
+			Handler: uploadHandler.Options,
+		},
 	}
 }
 
@@ -38,6 +45,13 @@ func PrivateRoute(handler handler.Handler) []*route.Route {
 	userHandler := handler.UserHandler
 	feedHandler := handler.FeedHandler
 	commentHandler := handler.CommentHandler
+	notificationHandler := handler.NotificationHandler
+	trendingHandler := handler.TrendingHandler
+	suggestionHandler := handler.SuggestionHandler
+	reportHandler := handler.ReportHandler
+	adminHandler := handler.AdminHandler
+	socketHandler := handler.SocketHandler
+	uploadHandler := handler.UploadHandler
 
 	return []*route.Route{
 		{
@@ -50,11 +64,71 @@ func PrivateRoute(handler handler.Handler) []*route.Route {
 			Path:    "/users",
 			Handler: userHandler.DeleteUser,
 		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/users/suggestions",
+			Handler: suggestionHandler.GetSuggestions,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/users/:username/followers",
+			Handler: userHandler.GetFollowers,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/users/:username/following",
+			Handler: userHandler.GetFollowings,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/users/:username/mutuals",
+			Handler: userHandler.GetMutuals,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/users/:username/feeds",
+			Handler: feedHandler.GetUserFeeds,
+		},
 		{
 			Method:  http.MethodPost,
 			Path:    "/users/:following_id/follow",
 			Handler: userHandler.FollowingUser,
 		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/users/:user_id/block",
+			Handler: userHandler.BlockUser,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/users/:user_id/block",
+			Handler: userHandler.UnblockUser,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/users/:user_id/mute",
+			Handler: userHandler.MuteUser,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/users/:user_id/mute",
+			Handler: userHandler.UnmuteUser,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/follow-requests",
+			Handler: userHandler.GetFollowRequests,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/follow-requests/:request_id/approve",
+			Handler: userHandler.ApproveFollowRequest,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/follow-requests/:request_id/reject",
+			Handler: userHandler.RejectFollowRequest,
+		},
 		{
 			Method:  http.MethodPost,
 			Path:    "/feeds",
@@ -65,11 +139,107 @@ func PrivateRoute(handler handler.Handler) []*route.Route {
 			Path:    "/feeds",
 			Handler: feedHandler.GetFeeds,
 		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/feeds/explore",
+			Handler: feedHandler.GetExploreFeeds,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/feeds/seen",
+			Handler: feedHandler.MarkFeedsSeen,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/feeds/drafts",
+			Handler: feedHandler.GetDrafts,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/feeds/drafts/:feed_id",
+			Handler: feedHandler.UpdateDraft,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/feeds/:feed_id",
+			Handler: feedHandler.UpdateFeed,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/tags/:tag/feeds",
+			Handler: feedHandler.GetFeedsByHashtag,
+		},
 		{
 			Method:  http.MethodPost,
 			Path:    "/feeds/:feed_id/like",
 			Handler: feedHandler.LikeFeed,
 		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/feeds/:feed_id/repost",
+			Handler: feedHandler.RepostFeed,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/feeds/:feed_id/reactions",
+			Handler: feedHandler.ReactToFeed,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/feeds/:feed_id/reactions",
+			Handler: feedHandler.RemoveReaction,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/feeds/:feed_id/reactions",
+			Handler: feedHandler.GetFeedReactions,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/feeds/:feed_id/medias",
+			Handler: feedHandler.GetFeedMedias,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/feeds/:feed_id/medias",
+			Handler: feedHandler.ReorderMedias,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/feeds/:feed_id/medias/:media_id",
+			Handler: feedHandler.UpdateMedia,
+		},
+		{
+			Method:     http.MethodGet,
+			Path:       "/ws",
+			Handler:    socketHandler.Connect,
+			QueryToken: true,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/uploads",
+			Handler: uploadHandler.CreateUpload,
+		},
+		{
+			Method:  http.MethodHead,
+			Path:    "/uploads/:upload_id",
+			Handler: uploadHandler.GetUpload,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/uploads/:upload_id",
+			Handler: uploadHandler.GetUpload,
+		},
+		{
+			Method:  http.MethodPatch,
+			Path:    "/uploads/:upload_id",
+			Handler: uploadHandler.AppendUpload,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/uploads/:upload_id",
+			Handler: uploadHandler.DeleteUpload,
+		},
 		{
 			Method:  http.MethodPost,
 			Path:    "/feeds/:feed_id/comment",
@@ -80,6 +250,26 @@ func PrivateRoute(handler handler.Handler) []*route.Route {
 			Path:    "/feeds/:feed_id/comment",
 			Handler: commentHandler.GetTopLevelComment,
 		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/feeds/:feed_id/comments/:comment_id/pin",
+			Handler: commentHandler.PinComment,
+		},
+		{
+			Method:  http.MethodPatch,
+			Path:    "/comments/:comment_id",
+			Handler: commentHandler.UpdateComment,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/comments/:comment_id",
+			Handler: commentHandler.DeleteComment,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/comments/:comment_id/like",
+			Handler: commentHandler.LikeComment,
+		},
 		{
 			Method:  http.MethodGet,
 			Path:    "/comments/:comment_id/reply",
@@ -90,5 +280,72 @@ func PrivateRoute(handler handler.Handler) []*route.Route {
 			Path:    "/comments/:comment_id/reply",
 			Handler: commentHandler.CreateReplyComment,
 		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/notifications",
+			Handler: notificationHandler.GetNotifications,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/notifications/read",
+			Handler: notificationHandler.ReadAllNotifications,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/trending/tags",
+			Handler: trendingHandler.GetTrendingTags,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/trending/feeds",
+			Handler: trendingHandler.GetTrendingFeeds,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/reports",
+			Handler: reportHandler.CreateReport,
+		},
+		{
+			Method:     http.MethodGet,
+			Path:       "/admin/reports",
+			Handler:    reportHandler.GetReports,
+			Permission: rbac.PermModerateContent,
+		},
+		{
+			Method:     http.MethodPost,
+			Path:       "/admin/reports/:report_id/actions",
+			Handler:    reportHandler.TakeAction,
+			Permission: rbac.PermModerateContent,
+		},
+		{
+			Method:     http.MethodGet,
+			Path:       "/admin/audit-logs",
+			Handler:    reportHandler.GetAuditLogs,
+			Permission: rbac.PermModerateContent,
+		},
+		{
+			Method:     http.MethodGet,
+			Path:       "/admin/users",
+			Handler:    adminHandler.GetUsers,
+			Permission: rbac.PermManageUsers,
+		},
+		{
+			Method:     http.MethodPut,
+			Path:       "/admin/users/:user_id/role",
+			Handler:    adminHandler.ChangeRole,
+			Permission: rbac.PermManageUsers,
+		},
+		{
+			Method:     http.MethodPost,
+			Path:       "/admin/users/:user_id/suspend",
+			Handler:    adminHandler.SuspendUser,
+			Permission: rbac.PermSuspendUsers,
+		},
+		{
+			Method:     http.MethodDelete,
+			Path:       "/admin/users/:user_id/suspend",
+			Handler:    adminHandler.UnsuspendUser,
+			Permission: rbac.PermSuspendUsers,
+		},
 	}
 }
@@ -2,18 +2,108 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/pkg/cursor"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
+var ErrCommentNotFound = errors.New("comment not found")
+
+type commentRow struct {
+	ID         uuid.UUID  `db:"id"`
+	UserID     uuid.UUID  `db:"user_id"`
+	FeedID     uuid.UUID  `db:"feed_id"`
+	ParentID   *uuid.UUID `db:"parent_id"`
+	Comment    string     `db:"comment"`
+	ReplyCount int        `db:"reply_count"`
+	LikeCount  int        `db:"like_count"`
+	LikedByMe  bool       `db:"liked_by_me"`
+	IsPinned   bool       `db:"is_pinned"`
+	CreatedAt  time.Time  `db:"created_at"`
+	EditedAt   *time.Time `db:"edited_at"`
+	DeletedAt  *time.Time `db:"deleted_at"`
+
+	Username string `db:"username"`
+	Avatar   string `db:"avatar"`
+}
+
+// replyPreviewRow is a reply ranked by its position under its parent.
+type replyPreviewRow struct {
+	commentRow
+	Position int `db:"position"`
+}
+
+func (row commentRow) toEntity() *entities.Comment {
+	comment := &entities.Comment{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		FeedID:    row.FeedID,
+		Comment:   row.Comment,
+		ReplyCout: row.ReplyCount,
+		LikeCount: row.LikeCount,
+		LikedByMe: row.LikedByMe,
+		IsPinned:  row.IsPinned,
+		CreatedAt: row.CreatedAt,
+		EditedAt:  row.EditedAt,
+		DeletedAt: row.DeletedAt,
+	}
+
+	if row.ParentID != nil {
+		comment.ParentID = *row.ParentID
+	}
+
+	if row.Username != "" {
+		comment.User = &entities.User{
+			ID:       row.UserID,
+			Username: row.Username,
+			Avatar:   row.Avatar,
+		}
+	}
+
+	return comment
+}
+
 type CommentRepository interface {
 	Create(ctx context.Context, comment *entities.Comment) (*entities.Comment, error)
 	CreateReply(ctx context.Context, comment *entities.Comment) (*entities.Comment, error)
-	FindTopComment(ctx context.Context, feedID uuid.UUID) ([]*entities.Comment, error)
-	FindRepliesComment(ctx context.Context, commentID uuid.UUID) ([]*entities.Comment, error)
+	FindTopComment(ctx context.Context, feedID, viewerID uuid.UUID, sort string, after *cursor.Cursor, limit int) ([]*entities.Comment, error)
+	FindPinnedComment(ctx context.Context, feedID, viewerID uuid.UUID) (*entities.Comment, error)
+	FindRepliesComment(ctx context.Context, commentID, viewerID uuid.UUID, after *cursor.Cursor, limit int) ([]*entities.Comment, error)
+	FindReplyPreviews(ctx context.Context, parentIDs []uuid.UUID, viewerID uuid.UUID, perParent int) ([]*entities.Comment, error)
+	FindByID(ctx context.Context, commentID uuid.UUID) (*entities.Comment, error)
+	Update(ctx context.Context, comment *entities.Comment) (*entities.Comment, error)
+	SoftDelete(ctx context.Context, commentID uuid.UUID) error
+	TogglePin(ctx context.Context, feedID, commentID uuid.UUID) (string, error)
+	ToggleLiked(ctx context.Context, commentID, userID uuid.UUID) (string, error)
+}
+
+// commentOrder is how a page of top-level comments is sorted, and where the
+// page after a cursor starts. Scored orders compare the cursor score too.
+type commentOrder struct {
+	orderBy string
+	after   string
+	scored  bool
+}
+
+var commentOrders = map[string]commentOrder{
+	entities.CommentSortNewest: {
+		orderBy: "t.created_at DESC, t.id DESC",
+		after:   "(t.created_at, t.id) < ($4::timestamp, $5)",
+	},
+	entities.CommentSortOldest: {
+		orderBy: "t.created_at ASC, t.id ASC",
+		after:   "(t.created_at, t.id) > ($4::timestamp, $5)",
+	},
+	entities.CommentSortTop: {
+		orderBy: "t.like_count DESC, t.created_at DESC, t.id DESC",
+		after:   "(t.like_count, t.created_at, t.id) < ($6, $4::timestamp, $5)",
+		scored:  true,
+	},
 }
 
 type commentRepositoryImpl struct {
@@ -27,125 +117,500 @@ func NewCommentRepository(db *sqlx.DB) CommentRepository {
 }
 
 func (r *commentRepositoryImpl) Create(ctx context.Context, comment *entities.Comment) (*entities.Comment, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
 	query := `
-		INSERT INTO feed_comments (feed_id, user_id, comment)
-		VALUES ($1, $2, $3)
+		INSERT INTO feed_comments (feed_id, user_id, comment, is_hidden)
+		VALUES ($1, $2, $3, $4)
 		RETURNING id;
 	`
 
-	err := r.db.QueryRowContext(ctx, query, comment.FeedID, comment.UserID, comment.Comment).Scan(&comment.ID)
+	err = tx.QueryRowContext(ctx, query, comment.FeedID, comment.UserID, comment.Comment, comment.IsHidden).Scan(&comment.ID)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err = r.syncEntities(ctx, tx, comment); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return comment, nil
 }
 
 func (r *commentRepositoryImpl) CreateReply(ctx context.Context, comment *entities.Comment) (*entities.Comment, error) {
-	if err := r.findByID(ctx, comment.ParentID); err != nil {
+	tx, err := r.db.BeginTxx(ctx, nil)
+
+	if err != nil {
 		return nil, err
 	}
 
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
 	query := `
-		INSERT INTO feed_comments (feed_id, user_id, parent_id, comment)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO feed_comments (feed_id, user_id, parent_id, comment, is_hidden)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id;
 	`
 
-	err := r.db.QueryRowContext(ctx, query, comment.FeedID, comment.UserID, comment.ParentID, comment.Comment).Scan(&comment.ID)
+	err = tx.QueryRowContext(ctx, query, comment.FeedID, comment.UserID, comment.ParentID, comment.Comment, comment.IsHidden).Scan(&comment.ID)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err = r.syncEntities(ctx, tx, comment); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return comment, nil
 }
 
-func (r *commentRepositoryImpl) findByID(ctx context.Context, commentID uuid.UUID) error {
-	var exits bool
+func (r *commentRepositoryImpl) syncEntities(ctx context.Context, tx *sqlx.Tx, comment *entities.Comment) error {
+	if err := syncHashtags(ctx, tx, "comment_hashtags", "comment_id", comment.ID, comment.Hashtags); err != nil {
+		return err
+	}
+
+	mentioned, err := syncMentions(ctx, tx, "comment_mentions", "comment_id", comment.ID, comment.UserID, comment.Mentions)
+
+	if err != nil {
+		return err
+	}
+
+	comment.MentionedUsers = mentioned
+
+	return nil
+}
+
+// FindByID returns the comment without its author. Hidden comments are
+// reported as not found; deleted ones are returned with DeletedAt set so
+// their replies stay reachable.
+func (r *commentRepositoryImpl) FindByID(ctx context.Context, commentID uuid.UUID) (*entities.Comment, error) {
+	var row commentRow
 
 	query := `
-		SELECT EXISTS (
-			SELECT 1 
-			FROM feed_comments
-			WHERE id = $1
-		);
+		SELECT id, user_id, feed_id, parent_id, comment, created_at, edited_at, deleted_at
+		FROM feed_comments c
+		WHERE c.id = $1
+			AND ` + notHidden("c") + `;
 	`
 
-	if err := r.db.QueryRowContext(ctx, query, commentID).Scan(&exits); err != nil {
-		return err
+	err := r.db.GetContext(ctx, &row, query, commentID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCommentNotFound
 	}
 
-	if !exits {
-		return errors.New("comment not found")
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return row.toEntity(), nil
+}
+
+// Update replaces the text of a comment written by comment.UserID and marks it
+// as edited. Deleted comments can't be edited.
+func (r *commentRepositoryImpl) Update(ctx context.Context, comment *entities.Comment) (*entities.Comment, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	query := `
+		UPDATE feed_comments
+		SET comment = $1,
+			edited_at = NOW()
+		WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL
+		RETURNING feed_id, parent_id, created_at, edited_at;
+	`
+
+	var parentID *uuid.UUID
+
+	err = tx.QueryRowContext(ctx, query, comment.Comment, comment.ID, comment.UserID).Scan(&comment.FeedID, &parentID, &comment.CreatedAt, &comment.EditedAt)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	if parentID != nil {
+		comment.ParentID = *parentID
+	}
+
+	if err = r.syncEntities(ctx, tx, comment); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
 }
 
-func (r *commentRepositoryImpl) FindTopComment(ctx context.Context, feedID uuid.UUID) ([]*entities.Comment, error) {
-	comments := make([]*entities.Comment, 0)
+// SoftDelete blanks a comment and marks it deleted while keeping the row, so
+// its replies keep their parent. The comment loses its hashtags, mentions and
+// pin.
+func (r *commentRepositoryImpl) SoftDelete(ctx context.Context, commentID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
 
 	query := `
-		SELECT 
-			c.*,
+		UPDATE feed_comments
+		SET comment = '',
+			deleted_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL;
+	`
+
+	result, err := tx.ExecContext(ctx, query, commentID)
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		err = ErrCommentNotFound
+		return err
+	}
+
+	cleanup := []string{
+		`DELETE FROM comment_hashtags WHERE comment_id = $1;`,
+		`DELETE FROM comment_mentions WHERE comment_id = $1;`,
+		`UPDATE feeds SET pinned_comment_id = NULL WHERE pinned_comment_id = $1;`,
+	}
+
+	for _, q := range cleanup {
+		if _, err = tx.ExecContext(ctx, q, commentID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// TogglePin pins the comment to its feed, replacing any pinned comment, or
+// unpins it when it already is the pinned one.
+func (r *commentRepositoryImpl) TogglePin(ctx context.Context, feedID, commentID uuid.UUID) (string, error) {
+	query := `
+		UPDATE feeds
+		SET pinned_comment_id = CASE
+			WHEN pinned_comment_id = $2 THEN NULL
+			ELSE $2
+		END
+		WHERE id = $1
+		RETURNING pinned_comment_id IS NOT NULL;
+	`
+
+	var pinned bool
+
+	err := r.db.QueryRowContext(ctx, query, feedID, commentID).Scan(&pinned)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrFeedNotFound
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if pinned {
+		return "pinned", nil
+	}
+
+	return "unpinned", nil
+}
+
+// threadVisible returns a SQL condition that drops deleted comments once no
+// reply is left below them.
+func threadVisible(alias string) string {
+	return `(
+		` + alias + `.deleted_at IS NULL
+		OR EXISTS (
+			SELECT 1 FROM feed_comments dr
+			WHERE dr.parent_id = ` + alias + `.id AND dr.deleted_at IS NULL
+		)
+	)`
+}
+
+// commentSelect selects the comments aliased as c together with their author,
+// reply count, like count and whether the viewer bound at viewerParam liked
+// them. Replies hidden from the viewer are not counted.
+func commentSelect(viewerParam string) string {
+	return `
+		SELECT
+			c.id,
+			c.user_id,
+			c.feed_id,
+			c.parent_id,
+			c.comment,
+			c.created_at,
+			c.edited_at,
+			c.deleted_at,
+			u.username,
+			COALESCE(u.avatar, '') AS avatar,
 			(
 				SELECT COUNT(*)
 				FROM feed_comments rc
 				WHERE rc.parent_id = c.id
-			) as reply_count
+					AND rc.deleted_at IS NULL
+					AND ` + notHidden("rc") + `
+					AND ` + notBlocked("rc.user_id", viewerParam) + `
+			) AS reply_count,
+			` + commentLikeColumns("c", viewerParam) + `,
+			COALESCE(f.pinned_comment_id = c.id, FALSE) AS is_pinned
 		FROM feed_comments c
-		WHERE c.feed_id = $1 
-			AND c.parent_id IS NULL
-		ORDER BY c.created_at DESC;
+		JOIN users u ON u.id = c.user_id
+		JOIN feeds f ON f.id = c.feed_id
 	`
+}
 
-	rows, err := r.db.QueryContext(ctx, query, feedID)
+// threadFilter returns the conditions every listed comment aliased as c must
+// meet for the viewer bound at viewerParam.
+func threadFilter(viewerParam string) string {
+	return notBlocked("c.user_id", viewerParam) + `
+			AND ` + notHidden("c") + `
+			AND ` + threadVisible("c")
+}
 
-	if err != nil {
-		return nil, err
+// FindTopComment returns a page of the top-level comments of a feed in the
+// given order. The pinned comment is left out; see FindPinnedComment. Unknown
+// orders fall back to newest first.
+func (r *commentRepositoryImpl) FindTopComment(ctx context.Context, feedID, viewerID uuid.UUID, sort string, after *cursor.Cursor, limit int) ([]*entities.Comment, error) {
+	order, ok := commentOrders[sort]
+	if !ok {
+		order = commentOrders[entities.CommentSortNewest]
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var c entities.Comment
+	args := []interface{}{feedID, viewerID, limit}
 
-		if err := rows.Scan(&c); err != nil {
-			return nil, err
+	query := `
+		SELECT * FROM (` + commentSelect("$2") + `
+			WHERE c.feed_id = $1
+				AND c.parent_id IS NULL
+				AND f.pinned_comment_id IS DISTINCT FROM c.id
+				AND ` + threadFilter("$2") + `
+		) t
+	`
+
+	if after != nil {
+		query += ` WHERE ` + order.after
+		args = append(args, after.CreatedAt, after.ID)
+		if order.scored {
+			args = append(args, after.Score)
 		}
-		comments = append(comments, &c)
 	}
 
-	return comments, nil
+	query += `
+		ORDER BY ` + order.orderBy + `
+		LIMIT $3
+	`
+
+	return r.selectComments(ctx, query, args...)
+}
+
+// FindPinnedComment returns the comment pinned to the feed, or nil when there
+// is none or the viewer may not see it.
+func (r *commentRepositoryImpl) FindPinnedComment(ctx context.Context, feedID, viewerID uuid.UUID) (*entities.Comment, error) {
+	query := commentSelect("$2") + `
+		WHERE f.id = $1
+			AND c.id = f.pinned_comment_id
+			AND c.deleted_at IS NULL
+			AND ` + threadFilter("$2") + `;
+	`
+
+	comments, err := r.selectComments(ctx, query, feedID, viewerID)
+
+	if err != nil || len(comments) == 0 {
+		return nil, err
+	}
+
+	return comments[0], nil
 }
 
-func (r *commentRepositoryImpl) FindRepliesComment(ctx context.Context, commentID uuid.UUID) ([]*entities.Comment, error) {
-	comments := make([]*entities.Comment, 0)
+// FindRepliesComment returns a page of the replies to a comment, oldest first
+// so a thread reads in order.
+func (r *commentRepositoryImpl) FindRepliesComment(ctx context.Context, commentID, viewerID uuid.UUID, after *cursor.Cursor, limit int) ([]*entities.Comment, error) {
+	args := []interface{}{commentID, viewerID, limit}
+
+	query := commentSelect("$2") + `
+		WHERE c.parent_id = $1
+			AND ` + threadFilter("$2")
+
+	if after != nil {
+		query += ` AND (c.created_at, c.id) > ($4::timestamp, $5)`
+		args = append(args, after.CreatedAt, after.ID)
+	}
+
+	query += `
+		ORDER BY c.created_at ASC, c.id ASC
+		LIMIT $3
+	`
+
+	return r.selectComments(ctx, query, args...)
+}
+
+// FindReplyPreviews returns up to perParent of the first replies of each
+// parent comment, oldest first.
+func (r *commentRepositoryImpl) FindReplyPreviews(ctx context.Context, parentIDs []uuid.UUID, viewerID uuid.UUID, perParent int) ([]*entities.Comment, error) {
+	if len(parentIDs) == 0 {
+		return []*entities.Comment{}, nil
+	}
 
 	query := `
-		SELECT * 
-		FROM feed_comments
-		WHERE parent_id = $1
-		ORDER BY created_at DESC
+		SELECT * FROM (
+			SELECT
+				t.*,
+				ROW_NUMBER() OVER (PARTITION BY t.parent_id ORDER BY t.created_at, t.id) AS position
+			FROM (` + commentSelect("$2") + `
+				WHERE c.parent_id = ANY($1)
+					AND ` + threadFilter("$2") + `
+			) t
+		) p
+		WHERE p.position <= $3
+		ORDER BY p.created_at, p.id;
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, commentID)
+	rows := make([]replyPreviewRow, 0)
 
-	if err != nil {
+	if err := r.db.SelectContext(ctx, &rows, query, parentIDs, viewerID, perParent); err != nil {
 		return nil, err
 	}
 
-	defer rows.Close()
+	comments := make([]*entities.Comment, len(rows))
 
-	for rows.Next() {
-		var c entities.Comment
+	for i, row := range rows {
+		comments[i] = row.toEntity()
+	}
 
-		if err := rows.Scan(&c); err != nil {
-			return nil, err
-		}
-		comments = append(comments, &c)
+	return comments, nil
+}
+
+// commentLikeColumns selects the like count of the comment aliased as alias
+// and whether the viewer bound at viewerParam liked it.
+func commentLikeColumns(alias, viewerParam string) string {
+	return `(
+				SELECT COUNT(*) FROM comment_likes cl
+				WHERE cl.comment_id = ` + alias + `.id
+			) AS like_count,
+			EXISTS (
+				SELECT 1 FROM comment_likes cl
+				WHERE cl.comment_id = ` + alias + `.id AND cl.user_id = ` + viewerParam + `
+			) AS liked_by_me`
+}
+
+func (r *commentRepositoryImpl) selectComments(ctx context.Context, query string, args ...any) ([]*entities.Comment, error) {
+	rows := make([]commentRow, 0)
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	comments := make([]*entities.Comment, len(rows))
+
+	for i, row := range rows {
+		comments[i] = row.toEntity()
 	}
 
 	return comments, nil
 }
+
+func (r *commentRepositoryImpl) ToggleLiked(ctx context.Context, commentID, userID uuid.UUID) (string, error) {
+	isLiked, err := r.isLiked(ctx, commentID, userID)
+
+	if err != nil {
+		return "", err
+	}
+
+	if isLiked {
+		err = r.unlikeComment(ctx, commentID, userID)
+		if err != nil {
+			return "", err
+		}
+		return "unliked", nil
+	}
+
+	err = r.likeComment(ctx, commentID, userID)
+
+	if err != nil {
+		return "", err
+	}
+
+	return "liked", nil
+}
+
+func (r *commentRepositoryImpl) isLiked(ctx context.Context, commentID, userID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM comment_likes
+			WHERE comment_id = $1 AND user_id = $2
+		);
+	`
+	err := r.db.GetContext(ctx, &exists, query, commentID, userID)
+	return exists, err
+}
+
+func (r *commentRepositoryImpl) likeComment(ctx context.Context, commentID, userID uuid.UUID) error {
+	query := `
+		INSERT INTO comment_likes (comment_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (comment_id, user_id) DO NOTHING;
+	`
+	_, err := r.db.ExecContext(ctx, query, commentID, userID)
+	return err
+}
+
+func (r *commentRepositoryImpl) unlikeComment(ctx context.Context, commentID, userID uuid.UUID) error {
+	query := `
+		DELETE FROM comment_likes
+		WHERE comment_id = $1 AND user_id = $2;
+	`
+	_, err := r.db.ExecContext(ctx, query, commentID, userID)
+	return err
+}
@@ -2,34 +2,92 @@ package repositories
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/pkg/cursor"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
 type feedRow struct {
-	FeedID    uuid.UUID `db:"id"`
-	Caption   string    `db:"caption"`
-	CreatedAt time.Time `db:"created_at"`
+	FeedID       uuid.UUID  `db:"id"`
+	Caption      string     `db:"caption"`
+	Status       string     `db:"feed_status"`
+	QuotedFeedID *uuid.UUID `db:"quoted_feed_id"`
+	CreatedAt    time.Time  `db:"created_at"`
+
+	RepostedByID       *uuid.UUID `db:"reposted_by_id"`
+	RepostedByUsername *string    `db:"reposted_by_username"`
+	RepostedByAvatar   *string    `db:"reposted_by_avatar"`
 
 	UserID   uuid.UUID `db:"user_id"`
 	Username string    `db:"username"`
 	Avatar   string    `db:"avatar"`
 
-	MediaURL  string `db:"url"`
-	MediaType string `db:"type"`
+	MediaID         *uuid.UUID    `db:"media_id"`
+	MediaURL        string        `db:"url"`
+	MediaType       string        `db:"type"`
+	MediaVariants   mediaVariants `db:"variants"`
+	MediaBlurhash   string        `db:"blurhash"`
+	MediaWidth      int           `db:"width"`
+	MediaHeight     int           `db:"height"`
+	MediaStatus     string        `db:"status"`
+	MediaPosterURL  string        `db:"poster_url"`
+	MediaDurationMs int           `db:"duration_ms"`
+	MediaPosition   int           `db:"position"`
+	MediaAltText    string        `db:"alt_text"`
+	MediaCaption    string        `db:"media_caption"`
+
+	ReactionCount int            `db:"reaction_count"`
+	Reactions     reactionCounts `db:"reactions"`
+	Reposts       int            `db:"reposts"`
+	Comments      int            `db:"comments"`
+}
 
-	Likes    int `db:"likes"`
-	Comments int `db:"comments"`
+type reactionRow struct {
+	FeedID    uuid.UUID `db:"feed_id"`
+	UserID    uuid.UUID `db:"user_id"`
+	Type      string    `db:"type"`
+	CreatedAt time.Time `db:"created_at"`
+	Username  string    `db:"username"`
+	Avatar    string    `db:"avatar"`
 }
 
+var (
+	ErrFeedNotFound  = errors.New("feed not found")
+	ErrMediaNotFound = errors.New("media not found")
+)
+
 type FeedRepository interface {
 	Create(ctx context.Context, feed *entities.Feed) (*entities.Feed, error)
+	UpdateCaption(ctx context.Context, feed *entities.Feed) (*entities.Feed, error)
+	UpdateDraft(ctx context.Context, feed *entities.Feed) (*entities.Feed, error)
+	GetDrafts(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.Feed, error)
+	PublishDueFeeds(ctx context.Context) ([]*entities.Feed, error)
+	FindMentionedUsers(ctx context.Context, feedID uuid.UUID) ([]uuid.UUID, error)
 	GetFeeds(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.Feed, error)
-	ToggleLiked(feedID, userID uuid.UUID) (string, error)
+	GetFeedsByHashtag(ctx context.Context, tag string, viewerID uuid.UUID, limit int) ([]*entities.Feed, error)
+	GetFeedsByIDs(ctx context.Context, feedIDs []uuid.UUID, viewerID uuid.UUID) ([]*entities.Feed, error)
+	GetFeedsByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.Feed, error)
+	IsVisible(ctx context.Context, feedID, viewerID uuid.UUID) (bool, error)
+	FindOwnerID(ctx context.Context, feedID uuid.UUID) (uuid.UUID, error)
+	GetExploreFeedIDs(ctx context.Context, userID uuid.UUID, excludedAuthorIDs []uuid.UUID, limit int) ([]uuid.UUID, error)
+	MarkSeen(ctx context.Context, userID uuid.UUID, feedIDs []uuid.UUID) error
+	AddMedia(ctx context.Context, media *entities.FeedMedia) error
+	UpdateMedia(ctx context.Context, media *entities.FeedMedia) error
+	SetMediaStatus(ctx context.Context, mediaID uuid.UUID, status string) error
+	FindMedias(ctx context.Context, feedID uuid.UUID) ([]*entities.FeedMedia, error)
+	UpdateMediaDetails(ctx context.Context, media *entities.FeedMedia) (*entities.FeedMedia, error)
+	ReorderMedias(ctx context.Context, feedID uuid.UUID, mediaIDs []uuid.UUID) error
+	SetReaction(ctx context.Context, feedID, userID uuid.UUID, reactionType string) error
+	AddRepost(ctx context.Context, feedID, userID uuid.UUID) (bool, error)
+	RemoveRepost(ctx context.Context, feedID, userID uuid.UUID) (bool, error)
+	RemoveReaction(ctx context.Context, feedID, userID uuid.UUID) error
+	FindReaction(ctx context.Context, feedID, userID uuid.UUID) (string, error)
+	FindReactions(ctx context.Context, feedID, viewerID uuid.UUID, reactionType string, after *cursor.Cursor, limit int) ([]*entities.Reaction, error)
 }
 
 type feedRepositoryImpl struct {
@@ -57,18 +115,28 @@ func (r *feedRepositoryImpl) Create(ctx context.Context, feed *entities.Feed) (*
 	var feedId uuid.UUID
 
 	query := `
-		INSERT INTO feeds (user_id, caption)
-		VALUES ($1, $2)
+		INSERT INTO feeds (user_id, caption, is_hidden, status, publish_at, published_at, quoted_feed_id)
+		VALUES ($1, $2, $3, $4, $5, CASE WHEN $4 = 'published' THEN NOW() END, $6)
 		RETURNING id
 	`
 
-	err = tx.QueryRowContext(ctx, query, feed.UserID.String(), feed.Caption).Scan(&feedId)
+	err = tx.QueryRowContext(ctx, query, feed.UserID.String(), feed.Caption, feed.IsHidden, feed.Status, feed.PublishAt, feed.QuotedFeedID).Scan(&feedId)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err = syncHashtags(ctx, tx, "feed_hashtags", "feed_id", feedId, feed.Hashtags); err != nil {
+		return nil, err
+	}
+
+	feed.MentionedUsers, err = syncMentions(ctx, tx, "feed_mentions", "feed_id", feedId, feed.UserID, feed.Mentions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
 		return nil, err
 	}
 
@@ -77,6 +145,169 @@ func (r *feedRepositoryImpl) Create(ctx context.Context, feed *entities.Feed) (*
 	return feed, nil
 }
 
+func (r *feedRepositoryImpl) UpdateCaption(ctx context.Context, feed *entities.Feed) (*entities.Feed, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	query := `
+		UPDATE feeds
+		SET caption = $1,
+			updated_at = NOW()
+		WHERE id = $2 AND user_id = $3
+		RETURNING status, publish_at, quoted_feed_id, created_at, updated_at;
+	`
+
+	err = tx.QueryRowContext(ctx, query, feed.Caption, feed.ID, feed.UserID).Scan(&feed.Status, &feed.PublishAt, &feed.QuotedFeedID, &feed.CreatedAt, &feed.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrFeedNotFound
+		}
+		return nil, err
+	}
+
+	if err = syncHashtags(ctx, tx, "feed_hashtags", "feed_id", feed.ID, feed.Hashtags); err != nil {
+		return nil, err
+	}
+
+	feed.MentionedUsers, err = syncMentions(ctx, tx, "feed_mentions", "feed_id", feed.ID, feed.UserID, feed.Mentions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+// UpdateDraft rewrites a draft or scheduled feed of feed.UserID, which may be
+// published by it. Published feeds are left alone and ErrFeedNotFound is
+// returned for them.
+func (r *feedRepositoryImpl) UpdateDraft(ctx context.Context, feed *entities.Feed) (*entities.Feed, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	query := `
+		UPDATE feeds
+		SET caption = $1,
+			status = $2,
+			publish_at = $3,
+			published_at = CASE WHEN $2 = 'published' THEN NOW() END,
+			updated_at = NOW()
+		WHERE id = $4 AND user_id = $5 AND status <> 'published'
+		RETURNING is_hidden, quoted_feed_id, created_at, updated_at;
+	`
+
+	err = tx.QueryRowContext(ctx, query, feed.Caption, feed.Status, feed.PublishAt, feed.ID, feed.UserID).
+		Scan(&feed.IsHidden, &feed.QuotedFeedID, &feed.CreatedAt, &feed.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrFeedNotFound
+		}
+		return nil, err
+	}
+
+	if err = syncHashtags(ctx, tx, "feed_hashtags", "feed_id", feed.ID, feed.Hashtags); err != nil {
+		return nil, err
+	}
+
+	feed.MentionedUsers, err = syncMentions(ctx, tx, "feed_mentions", "feed_id", feed.ID, feed.UserID, feed.Mentions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+// GetDrafts returns the drafts and scheduled feeds of a user, newest first,
+// without their media.
+func (r *feedRepositoryImpl) GetDrafts(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.Feed, error) {
+	query := `
+		SELECT id, user_id, caption, is_hidden, status, publish_at, quoted_feed_id, created_at, updated_at
+		FROM feeds
+		WHERE user_id = $1 AND status <> 'published'
+		ORDER BY created_at DESC, id
+		LIMIT $2;
+	`
+
+	feeds := make([]*entities.Feed, 0)
+
+	if err := r.db.SelectContext(ctx, &feeds, query, userID, limit); err != nil {
+		return nil, err
+	}
+
+	return feeds, nil
+}
+
+// PublishDueFeeds publishes every scheduled feed whose publish time has passed
+// and returns them. Publish times are stored in UTC.
+func (r *feedRepositoryImpl) PublishDueFeeds(ctx context.Context) ([]*entities.Feed, error) {
+	query := `
+		UPDATE feeds
+		SET status = 'published',
+			published_at = NOW(),
+			updated_at = NOW()
+		WHERE status = 'scheduled' AND publish_at <= NOW() AT TIME ZONE 'UTC'
+		RETURNING id, user_id, caption, is_hidden, status, publish_at, quoted_feed_id, created_at, updated_at;
+	`
+
+	feeds := make([]*entities.Feed, 0)
+
+	if err := r.db.SelectContext(ctx, &feeds, query); err != nil {
+		return nil, err
+	}
+
+	return feeds, nil
+}
+
+func (r *feedRepositoryImpl) FindMentionedUsers(ctx context.Context, feedID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT user_id
+		FROM feed_mentions
+		WHERE feed_id = $1;
+	`
+
+	userIDs := make([]uuid.UUID, 0)
+
+	if err := r.db.SelectContext(ctx, &userIDs, query, feedID); err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+// GetFeeds returns the home feed of a user: the feeds of the people they
+// follow and their own, and the feeds those people reposted, newest share
+// first. A feed shared more than once shows up once, at its latest share,
+// attributed to the reposter if that share was a repost. Reposted feeds are
+// only shown while the user may still see the original.
 func (r *feedRepositoryImpl) GetFeeds(
 	ctx context.Context,
 	userID uuid.UUID,
@@ -84,19 +315,82 @@ func (r *feedRepositoryImpl) GetFeeds(
 ) ([]*entities.Feed, error) {
 
 	query := `
+		WITH followed AS (
+			SELECT following_id AS user_id FROM user_folows WHERE follower_id = $1
+			UNION
+			SELECT $1::uuid
+		),
+		timeline AS (
+			SELECT DISTINCT ON (shares.feed_id) shares.feed_id, shares.reposted_by, shares.shared_at
+			FROM (
+				SELECT f.id AS feed_id, NULL::uuid AS reposted_by, f.published_at AS shared_at
+				FROM feeds f
+				WHERE f.user_id IN (SELECT user_id FROM followed)
+					AND ` + published("f") + `
+				UNION ALL
+				SELECT rp.feed_id, rp.user_id, rp.created_at
+				FROM feed_reposts rp
+				WHERE rp.user_id IN (SELECT user_id FROM followed)
+					AND ` + notMuted("rp.user_id", "$1") + `
+			) shares
+			ORDER BY shares.feed_id, shares.shared_at DESC
+		)
 		SELECT 
 			f.id,
 			f.caption,
+			f.status AS feed_status,
+			f.quoted_feed_id,
 			f.created_at,
+			f.user_id,
 			u.username,
 			u.avatar,
-			fm.url,
-			fm.type,
+			ru.id AS reposted_by_id,
+			ru.username AS reposted_by_username,
+			ru.avatar AS reposted_by_avatar,
+			` + feedMediaColumns("fm") + `,
+			` + reactionColumns("f") + `,
+			` + repostColumns("f") + `,
 			(
 			  SELECT COUNT(*) 
-			  FROM feed_likes fl 
-			  WHERE fl.feed_id = f.id
-			) AS likes,
+			  FROM feed_comments fc 
+			  WHERE fc.feed_id = f.id
+			) AS comments
+		FROM timeline t
+		JOIN feeds f ON f.id = t.feed_id
+		JOIN users u ON u.id = f.user_id
+		LEFT JOIN users ru ON ru.id = t.reposted_by
+		LEFT JOIN feed_media fm ON fm.feed_id = f.id
+		WHERE ` + visibleTo("f.user_id", "$1") + `
+			AND ` + notMuted("f.user_id", "$1") + `
+			AND ` + notHidden("f") + `
+			AND ` + published("f") + `
+		ORDER BY t.shared_at DESC, f.id, fm.position
+		LIMIT $2;
+	`
+
+	rows := make([]feedRow, 0)
+
+	if err := r.db.SelectContext(ctx, &rows, query, userID, limit); err != nil {
+		return nil, err
+	}
+
+	return groupFeedRows(rows), nil
+}
+
+func (r *feedRepositoryImpl) GetFeedsByHashtag(ctx context.Context, tag string, viewerID uuid.UUID, limit int) ([]*entities.Feed, error) {
+	query := `
+		SELECT 
+			f.id,
+			f.caption,
+			f.status AS feed_status,
+			f.quoted_feed_id,
+			f.created_at,
+			f.user_id,
+			u.username,
+			u.avatar,
+			` + feedMediaColumns("fm") + `,
+			` + reactionColumns("f") + `,
+			` + repostColumns("f") + `,
 			(
 			  SELECT COUNT(*) 
 			  FROM feed_comments fc 
@@ -104,12 +398,178 @@ func (r *feedRepositoryImpl) GetFeeds(
 			) AS comments
 		FROM feeds f
 		JOIN users u ON u.id = f.user_id
-		JOIN feed_media fm ON fm.feed_id = f.id
-		WHERE 
-			(f.user_id IN (
-				SELECT following_id FROM user_folows WHERE follower_id = $1
-			) OR f.user_id = $1)
-		ORDER BY f.created_at DESC
+		LEFT JOIN feed_media fm ON fm.feed_id = f.id
+		JOIN feed_hashtags fh ON fh.feed_id = f.id
+		JOIN hashtags h ON h.id = fh.hashtag_id
+		WHERE h.name = $1
+			AND ` + visibleTo("f.user_id", "$3") + `
+			AND ` + notHidden("f") + `
+			AND ` + published("f") + `
+		ORDER BY f.published_at DESC, f.id, fm.position
+		LIMIT $2;
+	`
+
+	rows := make([]feedRow, 0)
+
+	if err := r.db.SelectContext(ctx, &rows, query, tag, limit, viewerID); err != nil {
+		return nil, err
+	}
+
+	return groupFeedRows(rows), nil
+}
+
+// GetFeedsByIDs returns the feeds in the same order as feedIDs, skipping the
+// ones that no longer exist or that the viewer is not allowed to see.
+func (r *feedRepositoryImpl) GetFeedsByIDs(ctx context.Context, feedIDs []uuid.UUID, viewerID uuid.UUID) ([]*entities.Feed, error) {
+	query := `
+		SELECT 
+			f.id,
+			f.caption,
+			f.status AS feed_status,
+			f.quoted_feed_id,
+			f.created_at,
+			f.user_id,
+			u.username,
+			u.avatar,
+			` + feedMediaColumns("fm") + `,
+			` + reactionColumns("f") + `,
+			` + repostColumns("f") + `,
+			(
+			  SELECT COUNT(*) 
+			  FROM feed_comments fc 
+			  WHERE fc.feed_id = f.id
+			) AS comments
+		FROM feeds f
+		JOIN users u ON u.id = f.user_id
+		LEFT JOIN feed_media fm ON fm.feed_id = f.id
+		WHERE f.id = ANY($1)
+			AND ` + visibleTo("f.user_id", "$2") + `
+			AND ` + notHidden("f") + `
+			AND ` + published("f") + `
+		ORDER BY fm.position;
+	`
+
+	rows := make([]feedRow, 0)
+
+	if err := r.db.SelectContext(ctx, &rows, query, feedIDs, viewerID); err != nil {
+		return nil, err
+	}
+
+	feedMap := make(map[uuid.UUID]*entities.Feed)
+
+	for _, feed := range groupFeedRows(rows) {
+		feedMap[feed.ID] = feed
+	}
+
+	result := make([]*entities.Feed, 0, len(feedMap))
+
+	for _, id := range feedIDs {
+		if feed, ok := feedMap[id]; ok {
+			result = append(result, feed)
+		}
+	}
+
+	return result, nil
+}
+
+// GetExploreFeedIDs ranks recent feeds by authors other than excludedAuthorIDs.
+// A feed scores higher the more it is reacted to and commented on, the more of
+// the user's followings follow its author and the more it shares hashtags with
+// feeds the user reacted to; the score is then divided by its age so newer feeds
+// win ties. Feeds the user has already seen, hidden feeds and feeds the user
+// may not view are skipped.
+func (r *feedRepositoryImpl) GetExploreFeedIDs(ctx context.Context, userID uuid.UUID, excludedAuthorIDs []uuid.UUID, limit int) ([]uuid.UUID, error) {
+	query := `
+		WITH followings AS (
+			SELECT following_id FROM user_folows WHERE follower_id = $1
+		),
+		reacted_hashtags AS (
+			SELECT DISTINCT fh.hashtag_id
+			FROM feed_reactions fr
+			JOIN feed_hashtags fh ON fh.feed_id = fr.feed_id
+			WHERE fr.user_id = $1
+		),
+		candidates AS (
+			SELECT
+				f.id,
+				f.published_at,
+				(
+				  SELECT COUNT(*)
+				  FROM feed_reactions fr
+				  WHERE fr.feed_id = f.id
+				) AS reactions,
+				(
+				  SELECT COUNT(*)
+				  FROM feed_comments fc
+				  WHERE fc.feed_id = f.id
+				) AS comments,
+				(
+				  SELECT COUNT(*)
+				  FROM user_folows uf
+				  WHERE uf.following_id = f.user_id
+					AND uf.follower_id IN (SELECT following_id FROM followings)
+				) AS mutual_followers,
+				(
+				  SELECT COUNT(*)
+				  FROM feed_hashtags fh
+				  WHERE fh.feed_id = f.id
+					AND fh.hashtag_id IN (SELECT hashtag_id FROM reacted_hashtags)
+				) AS shared_hashtags
+			FROM feeds f
+			WHERE NOT (f.user_id = ANY($3))
+				AND ` + visibleTo("f.user_id", "$1") + `
+				AND ` + notHidden("f") + `
+				AND ` + published("f") + `
+				AND f.published_at >= NOW() - INTERVAL '7 days'
+				AND NOT EXISTS (
+					SELECT 1 FROM feed_views fv
+					WHERE fv.feed_id = f.id AND fv.user_id = $1
+				)
+		)
+		SELECT id
+		FROM candidates
+		ORDER BY
+			(1 + reactions + 2 * comments + 3 * mutual_followers + 2 * shared_hashtags)
+			/ POWER(EXTRACT(EPOCH FROM NOW() - published_at) / 3600 + 2, 1.5) DESC,
+			published_at DESC
+		LIMIT $2;
+	`
+
+	feedIDs := make([]uuid.UUID, 0)
+
+	if err := r.db.SelectContext(ctx, &feedIDs, query, userID, limit, excludedAuthorIDs); err != nil {
+		return nil, err
+	}
+
+	return feedIDs, nil
+}
+
+func (r *feedRepositoryImpl) GetFeedsByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.Feed, error) {
+	query := `
+		SELECT 
+			f.id,
+			f.caption,
+			f.status AS feed_status,
+			f.quoted_feed_id,
+			f.created_at,
+			f.user_id,
+			u.username,
+			u.avatar,
+			` + feedMediaColumns("fm") + `,
+			` + reactionColumns("f") + `,
+			` + repostColumns("f") + `,
+			(
+			  SELECT COUNT(*) 
+			  FROM feed_comments fc 
+			  WHERE fc.feed_id = f.id
+			) AS comments
+		FROM feeds f
+		JOIN users u ON u.id = f.user_id
+		LEFT JOIN feed_media fm ON fm.feed_id = f.id
+		WHERE f.user_id = $1
+			AND ` + notHidden("f") + `
+			AND ` + published("f") + `
+		ORDER BY f.published_at DESC, f.id, fm.position
 		LIMIT $2;
 	`
 
@@ -119,39 +579,240 @@ func (r *feedRepositoryImpl) GetFeeds(
 		return nil, err
 	}
 
+	return groupFeedRows(rows), nil
+}
+
+// IsVisible reports whether the viewer may see the feed and its comments. It
+// returns ErrFeedNotFound when the feed does not exist or is hidden.
+func (r *feedRepositoryImpl) IsVisible(ctx context.Context, feedID, viewerID uuid.UUID) (bool, error) {
+	var visible bool
+
+	query := `
+		SELECT ` + visibleTo("f.user_id", "$2") + `
+		FROM feeds f
+		WHERE f.id = $1
+			AND ` + notHidden("f") + `
+			AND ` + published("f") + `;
+	`
+
+	err := r.db.QueryRowContext(ctx, query, feedID, viewerID).Scan(&visible)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrFeedNotFound
+	}
+
+	return visible, err
+}
+
+func (r *feedRepositoryImpl) FindOwnerID(ctx context.Context, feedID uuid.UUID) (uuid.UUID, error) {
+	var ownerID uuid.UUID
+
+	query := `
+		SELECT user_id
+		FROM feeds
+		WHERE id = $1;
+	`
+
+	err := r.db.QueryRowContext(ctx, query, feedID).Scan(&ownerID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, ErrFeedNotFound
+	}
+
+	return ownerID, err
+}
+
+// MarkSeen records the feeds as seen by the user, ignoring the ones the user
+// is not allowed to see.
+func (r *feedRepositoryImpl) MarkSeen(ctx context.Context, userID uuid.UUID, feedIDs []uuid.UUID) error {
+	query := `
+		INSERT INTO feed_views (feed_id, user_id)
+		SELECT f.id, $1
+		FROM feeds f
+		WHERE f.id = ANY($2)
+			AND ` + visibleTo("f.user_id", "$1") + `
+			AND ` + published("f") + `
+		ON CONFLICT (feed_id, user_id) DO NOTHING;
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, feedIDs)
+	return err
+}
+
+// AddMedia attaches a media file to a feed.
+func (r *feedRepositoryImpl) AddMedia(ctx context.Context, media *entities.FeedMedia) error {
+	query := `
+		INSERT INTO feed_media (feed_id, url, type, status, variants, blurhash, poster_url, duration_ms, width, height, position, alt_text, caption, blob_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id
+	`
+	return r.db.QueryRowxContext(ctx, query,
+		media.FeedId, media.Url, media.Type, media.Status, mediaVariants(media.Variants),
+		media.Blurhash, media.PosterUrl, media.DurationMs, media.Width, media.Height,
+		media.Position, media.AltText, media.Caption, media.BlobID,
+	).Scan(&media.ID)
+}
+
+// UpdateMedia saves the result of processing a media and loads the details
+// the owner set on it.
+func (r *feedRepositoryImpl) UpdateMedia(ctx context.Context, media *entities.FeedMedia) error {
+	query := `
+		UPDATE feed_media
+		SET url = $2, type = $3, status = $4, variants = $5, blurhash = $6,
+			poster_url = $7, duration_ms = $8, width = $9, height = $10, blob_id = $11
+		WHERE id = $1
+		RETURNING position, alt_text, caption
+	`
+	err := r.db.QueryRowxContext(ctx, query,
+		media.ID, media.Url, media.Type, media.Status, mediaVariants(media.Variants),
+		media.Blurhash, media.PosterUrl, media.DurationMs, media.Width, media.Height,
+		media.BlobID,
+	).Scan(&media.Position, &media.AltText, &media.Caption)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrMediaNotFound
+	}
+
+	return err
+}
+
+func (r *feedRepositoryImpl) SetMediaStatus(ctx context.Context, mediaID uuid.UUID, status string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE feed_media SET status = $2 WHERE id = $1`, mediaID, status)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrMediaNotFound
+	}
+
+	return nil
+}
+
+func (r *feedRepositoryImpl) FindMedias(ctx context.Context, feedID uuid.UUID) ([]*entities.FeedMedia, error) {
+	query := `
+		SELECT ` + mediaColumns + `
+		FROM feed_media
+		WHERE feed_id = $1
+		ORDER BY position, created_at
+	`
+
+	rows := make([]mediaRow, 0)
+	if err := r.db.SelectContext(ctx, &rows, query, feedID); err != nil {
+		return nil, err
+	}
+
+	medias := make([]*entities.FeedMedia, len(rows))
+	for i, row := range rows {
+		medias[i] = row.toEntity()
+	}
+
+	return medias, nil
+}
+
+// UpdateMediaDetails saves the alt text and caption of a media of
+// media.FeedId.
+func (r *feedRepositoryImpl) UpdateMediaDetails(ctx context.Context, media *entities.FeedMedia) (*entities.FeedMedia, error) {
+	query := `
+		UPDATE feed_media
+		SET alt_text = $3, caption = $4
+		WHERE id = $1 AND feed_id = $2
+		RETURNING ` + mediaColumns + `
+	`
+
+	var row mediaRow
+
+	err := r.db.QueryRowxContext(ctx, query, media.ID, media.FeedId, media.AltText, media.Caption).StructScan(&row)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMediaNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return row.toEntity(), nil
+}
+
+// ReorderMedias gives each media of the feed its index in mediaIDs as
+// position. Ids of other feeds are ignored.
+func (r *feedRepositoryImpl) ReorderMedias(ctx context.Context, feedID uuid.UUID, mediaIDs []uuid.UUID) error {
+	query := `
+		UPDATE feed_media fm
+		SET position = ordered.position - 1
+		FROM UNNEST($2::uuid[]) WITH ORDINALITY AS ordered(id, position)
+		WHERE fm.id = ordered.id AND fm.feed_id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, feedID, mediaIDs)
+	return err
+}
+
+// groupFeedRows folds one row per media into feeds, keeping the row order.
+func groupFeedRows(rows []feedRow) []*entities.Feed {
 	feedMap := make(map[uuid.UUID]*entities.Feed)
+	result := make([]*entities.Feed, 0)
 
 	for _, row := range rows {
-		fmt.Println("contoh feedmap", feedMap[row.FeedID])
-
 		if _, ok := feedMap[row.FeedID]; !ok {
 			feedMap[row.FeedID] = &entities.Feed{
-				ID:      row.FeedID,
-				Caption: row.Caption,
+				ID:           row.FeedID,
+				UserID:       row.UserID,
+				Caption:      row.Caption,
+				Status:       row.Status,
+				QuotedFeedID: row.QuotedFeedID,
 				User: &entities.User{
 					ID:       row.UserID,
 					Username: row.Username,
 					Avatar:   row.Avatar,
 				},
-				Likes:    row.Likes,
-				Comments: row.Comments,
-				Medias:   []*entities.FeedMedia{},
+				ReactionCount: row.ReactionCount,
+				Reactions:     row.Reactions,
+				Reposts:       row.Reposts,
+				Comments:      row.Comments,
+				Medias:        []*entities.FeedMedia{},
+				CreatedAt:     row.CreatedAt,
 			}
+			if row.RepostedByID != nil {
+				feedMap[row.FeedID].RepostedBy = &entities.User{
+					ID:       *row.RepostedByID,
+					Username: *row.RepostedByUsername,
+					Avatar:   *row.RepostedByAvatar,
+				}
+			}
+			result = append(result, feedMap[row.FeedID])
+		}
+
+		// Feeds without media, such as quotes, come as a single row with
+		// no media.
+		if row.MediaID == nil {
+			continue
 		}
 
 		feedMap[row.FeedID].Medias = append(feedMap[row.FeedID].Medias, &entities.FeedMedia{
-			Url:  row.MediaURL,
-			Type: row.MediaType,
+			ID:         *row.MediaID,
+			FeedId:     row.FeedID,
+			Url:        row.MediaURL,
+			Type:       row.MediaType,
+			Status:     row.MediaStatus,
+			Variants:   row.MediaVariants,
+			Blurhash:   row.MediaBlurhash,
+			PosterUrl:  row.MediaPosterURL,
+			DurationMs: row.MediaDurationMs,
+			Position:   row.MediaPosition,
+			AltText:    row.MediaAltText,
+			Caption:    row.MediaCaption,
+			Width:      row.MediaWidth,
+			Height:     row.MediaHeight,
 		})
 	}
 
-	result := make([]*entities.Feed, 0, len(feedMap))
-
-	for _, f := range feedMap {
-		result = append(result, f)
-	}
-
-	return result, nil
+	return result
 }
 
 func (r *feedRepositoryImpl) GetFeed(ctx context.Context, feedID uuid.UUID) (*entities.Feed, error) {
@@ -162,13 +823,24 @@ func (r *feedRepositoryImpl) GetFeed(ctx context.Context, feedID uuid.UUID) (*en
 			f.created_at,
 			u.username,
 			u.avatar,
+			fm.id AS media_id,
 			fm.url,
 			fm.type,
+			fm.variants,
+			fm.blurhash,
+			fm.width,
+			fm.height,
+			fm.status,
+			fm.poster_url,
+			fm.duration_ms,
+			fm.position,
+			fm.alt_text,
+			fm.caption AS media_caption,
 			c.comment,
 			(SELECT COUNT (*)
-			 FROM feed_likes f1
+			 FROM feed_reactions f1
 			 WHERE f1.feed_id = f.id
-			) as likes,
+			) as reaction_count,
 		FROM f
 		JOIN users u ON u.id = f.user_id
 		LEFT JOIN feed_medias fm ON fm.feed_id = f.id
@@ -187,57 +859,139 @@ func (r *feedRepositoryImpl) GetFeed(ctx context.Context, feedID uuid.UUID) (*en
 
 }
 
-func (r *feedRepositoryImpl) ToggleLiked(feedID, userID uuid.UUID) (string, error) {
-	isLiked, err := r.isLiked(feedID, userID)
+// AddRepost reposts the feed for the user. It reports false if the user had
+// already reposted it.
+func (r *feedRepositoryImpl) AddRepost(ctx context.Context, feedID, userID uuid.UUID) (bool, error) {
+	query := `
+		INSERT INTO feed_reposts (feed_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (feed_id, user_id) DO NOTHING;
+	`
+
+	result, err := r.db.ExecContext(ctx, query, feedID, userID)
 
 	if err != nil {
-		return "", err
+		return false, err
 	}
 
-	if isLiked {
-		err = r.unlikeFeed(feedID, userID)
-		if err != nil {
-			return "", err
-		}
-		return "unliked", nil
-	}
+	affected, err := result.RowsAffected()
 
-	err = r.likeFeed(feedID, userID)
+	return affected > 0, err
+}
+
+// RemoveRepost undoes the user's repost of the feed. It reports false if
+// there was none.
+func (r *feedRepositoryImpl) RemoveRepost(ctx context.Context, feedID, userID uuid.UUID) (bool, error) {
+	query := `
+		DELETE FROM feed_reposts
+		WHERE feed_id = $1 AND user_id = $2;
+	`
+
+	result, err := r.db.ExecContext(ctx, query, feedID, userID)
 
 	if err != nil {
-		return "", err
+		return false, err
 	}
 
-	return "liked", nil
+	affected, err := result.RowsAffected()
+
+	return affected > 0, err
 }
 
-func (r *feedRepositoryImpl) isLiked(feedID, userID uuid.UUID) (bool, error) {
-	var exits bool
+// SetReaction stores the user's reaction to the feed, replacing the one they
+// had.
+func (r *feedRepositoryImpl) SetReaction(ctx context.Context, feedID, userID uuid.UUID, reactionType string) error {
 	query := `
-		SELECT EXISTS (
-			SELECT 1 FROM feed_likes
-			WHERE feed_id = $1 and user_id = $2
-		);
+		INSERT INTO feed_reactions (feed_id, user_id, type)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (feed_id, user_id) DO UPDATE
+		SET type = EXCLUDED.type,
+			created_at = NOW();
 	`
-	err := r.db.Get(&exits, query, feedID, userID)
-	return exits, err
+	_, err := r.db.ExecContext(ctx, query, feedID, userID, reactionType)
+	return err
 }
 
-func (r *feedRepositoryImpl) likeFeed(feedID, userID uuid.UUID) error {
+func (r *feedRepositoryImpl) RemoveReaction(ctx context.Context, feedID, userID uuid.UUID) error {
 	query := `
-		INSERT INTO feed_likes (feed_id, user_id)
-		VALUES ($1, $2)
-		ON CONFLICT (feed_id, user_id) DO NOTHING;
+		DELETE FROM feed_reactions
+		WHERE feed_id = $1 AND user_id = $2;
 	`
-	_, err := r.db.Exec(query, feedID, userID)
+	_, err := r.db.ExecContext(ctx, query, feedID, userID)
 	return err
 }
 
-func (r *feedRepositoryImpl) unlikeFeed(feedID, userID uuid.UUID) error {
+// FindReaction returns the type of the user's reaction to the feed, or an
+// empty string when they have not reacted.
+func (r *feedRepositoryImpl) FindReaction(ctx context.Context, feedID, userID uuid.UUID) (string, error) {
+	var reactionType string
+
 	query := `
-		DELETE FROM feed_likes 
+		SELECT type FROM feed_reactions
 		WHERE feed_id = $1 AND user_id = $2;
 	`
-	_, err := r.db.Exec(query, feedID, userID)
-	return err
+
+	err := r.db.GetContext(ctx, &reactionType, query, feedID, userID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+
+	return reactionType, err
+}
+
+// FindReactions lists who reacted to the feed, newest first. An empty
+// reactionType lists every reaction. Users blocking or blocked by the viewer
+// are left out.
+func (r *feedRepositoryImpl) FindReactions(ctx context.Context, feedID, viewerID uuid.UUID, reactionType string, after *cursor.Cursor, limit int) ([]*entities.Reaction, error) {
+	args := []interface{}{feedID, viewerID, reactionType, limit}
+
+	query := `
+		SELECT
+			fr.feed_id,
+			fr.user_id,
+			fr.type,
+			fr.created_at,
+			u.username,
+			COALESCE(u.avatar, '') AS avatar
+		FROM feed_reactions fr
+		JOIN users u ON u.id = fr.user_id
+		WHERE fr.feed_id = $1
+			AND ($3 = '' OR fr.type = $3)
+			AND ` + notBlocked("fr.user_id", "$2") + `
+	`
+
+	if after != nil {
+		query += ` AND (fr.created_at, fr.user_id) < ($5::timestamp, $6)`
+		args = append(args, after.CreatedAt, after.ID)
+	}
+
+	query += `
+		ORDER BY fr.created_at DESC, fr.user_id DESC
+		LIMIT $4
+	`
+
+	rows := make([]reactionRow, 0)
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	reactions := make([]*entities.Reaction, len(rows))
+
+	for i, row := range rows {
+		reactions[i] = &entities.Reaction{
+			FeedID:    row.FeedID,
+			UserID:    row.UserID,
+			Type:      row.Type,
+			CreatedAt: row.CreatedAt,
+			User: &entities.User{
+				ID:       row.UserID,
+				Username: row.Username,
+				Avatar:   row.Avatar,
+			},
+		}
+	}
+
+	return reactions, nil
 }
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+func upsertHashtags(ctx context.Context, tx *sqlx.Tx, names []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(names))
+
+	query := `
+		INSERT INTO hashtags (name)
+		VALUES ($1)
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id;
+	`
+
+	for _, name := range names {
+		var id uuid.UUID
+		if err := tx.QueryRowContext(ctx, query, name).Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// syncHashtags replaces the hashtags linked to a feed or comment. table is the
+// join table (feed_hashtags or comment_hashtags) and column its owner column.
+func syncHashtags(ctx context.Context, tx *sqlx.Tx, table, column string, ownerID uuid.UUID, names []string) error {
+	hashtagIDs, err := upsertHashtags(ctx, tx, names)
+
+	if err != nil {
+		return err
+	}
+
+	deleteQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE %s = $1 AND NOT (hashtag_id = ANY($2));
+	`, table, column)
+
+	if _, err := tx.ExecContext(ctx, deleteQuery, ownerID, hashtagIDs); err != nil {
+		return err
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s (%s, hashtag_id)
+		VALUES ($1, $2)
+		ON CONFLICT (%s, hashtag_id) DO NOTHING;
+	`, table, column, column)
+
+	for _, hashtagID := range hashtagIDs {
+		if _, err := tx.ExecContext(ctx, insertQuery, ownerID, hashtagID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncMentions replaces the mentions linked to a feed or comment and returns
+// only the users that were not mentioned before, so callers notify them once.
+// Users that blocked the author, or were blocked by them, are never mentioned.
+func syncMentions(ctx context.Context, tx *sqlx.Tx, table, column string, ownerID, authorID uuid.UUID, usernames []string) ([]uuid.UUID, error) {
+	deleteQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE %s = $1
+			AND user_id NOT IN (
+				SELECT id FROM users WHERE LOWER(username) = ANY($2)
+			);
+	`, table, column)
+
+	if _, err := tx.ExecContext(ctx, deleteQuery, ownerID, usernames); err != nil {
+		return nil, err
+	}
+
+	mentioned := make([]uuid.UUID, 0)
+
+	if len(usernames) == 0 {
+		return mentioned, nil
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s (%s, user_id)
+		SELECT $1, id FROM users
+		WHERE LOWER(username) = ANY($2)
+			AND %s
+		ON CONFLICT (%s, user_id) DO NOTHING
+		RETURNING user_id;
+	`, table, column, notBlocked("users.id", "$3"), column)
+
+	if err := tx.SelectContext(ctx, &mentioned, insertQuery, ownerID, usernames, authorID); err != nil {
+		return nil, err
+	}
+
+	return mentioned, nil
+}
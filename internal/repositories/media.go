@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/google/uuid"
+)
+
+// mediaColumns are the feed_media columns a mediaRow is scanned from.
+const mediaColumns = `id, feed_id, url, type, status, position, alt_text, caption, variants, blurhash, poster_url, duration_ms, width, height`
+
+type mediaRow struct {
+	ID         uuid.UUID     `db:"id"`
+	FeedID     uuid.UUID     `db:"feed_id"`
+	Url        string        `db:"url"`
+	Type       string        `db:"type"`
+	Status     string        `db:"status"`
+	Position   int           `db:"position"`
+	AltText    string        `db:"alt_text"`
+	Caption    string        `db:"caption"`
+	Variants   mediaVariants `db:"variants"`
+	Blurhash   string        `db:"blurhash"`
+	PosterUrl  string        `db:"poster_url"`
+	DurationMs int           `db:"duration_ms"`
+	Width      int           `db:"width"`
+	Height     int           `db:"height"`
+}
+
+func (r mediaRow) toEntity() *entities.FeedMedia {
+	return &entities.FeedMedia{
+		ID:         r.ID,
+		FeedId:     r.FeedID,
+		Url:        r.Url,
+		Type:       r.Type,
+		Status:     r.Status,
+		Position:   r.Position,
+		AltText:    r.AltText,
+		Caption:    r.Caption,
+		Variants:   r.Variants,
+		Blurhash:   r.Blurhash,
+		PosterUrl:  r.PosterUrl,
+		DurationMs: r.DurationMs,
+		Width:      r.Width,
+		Height:     r.Height,
+	}
+}
+
+// feedMediaColumns selects the media joined as alias for a feedRow. The media
+// is left joined, so its columns fall back to empty values for feeds without
+// any.
+func feedMediaColumns(alias string) string {
+	return fmt.Sprintf(`%[1]s.id AS media_id,
+			COALESCE(%[1]s.url, '') AS url,
+			COALESCE(%[1]s.type, '') AS type,
+			%[1]s.variants,
+			COALESCE(%[1]s.blurhash, '') AS blurhash,
+			COALESCE(%[1]s.width, 0) AS width,
+			COALESCE(%[1]s.height, 0) AS height,
+			COALESCE(%[1]s.status, '') AS status,
+			COALESCE(%[1]s.poster_url, '') AS poster_url,
+			COALESCE(%[1]s.duration_ms, 0) AS duration_ms,
+			COALESCE(%[1]s.position, 0) AS position,
+			COALESCE(%[1]s.alt_text, '') AS alt_text,
+			COALESCE(%[1]s.caption, '') AS media_caption`, alias)
+}
+
+// mediaVariants maps variant names to URLs, stored as a JSON object.
+type mediaVariants map[string]string
+
+func (v *mediaVariants) Scan(src any) error {
+	var raw []byte
+
+	switch s := src.(type) {
+	case nil:
+		*v = mediaVariants{}
+		return nil
+	case []byte:
+		raw = s
+	case string:
+		raw = []byte(s)
+	default:
+		return fmt.Errorf("cannot scan %T into media variants", src)
+	}
+
+	variants := make(mediaVariants)
+	if err := json.Unmarshal(raw, &variants); err != nil {
+		return err
+	}
+
+	*v = variants
+	return nil
+}
+
+func (v mediaVariants) Value() (driver.Value, error) {
+	if v == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(v)
+}
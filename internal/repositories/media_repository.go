@@ -0,0 +1,294 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrBlobNotFound = errors.New("media blob not found")
+	ErrBlobExists   = errors.New("media blob already exists")
+)
+
+// blobColumns are the media_blobs columns a blobRow is scanned from.
+const blobColumns = `id, sha256, phash, type, url, variants, blurhash, poster_url, duration_ms, width, height, keys, ref_count, created_at, updated_at`
+
+// MediaRepository keeps track of stored media content: which stored files
+// the database still points to, the blobs shared by identical uploads, and
+// the content moderators removed.
+type MediaRepository interface {
+	FindReferencedURLs(ctx context.Context) ([]string, error)
+	// FindBlob returns the blob of content with the given digest and marks it
+	// as just used, so DeleteBlob leaves it alone while it is being attached
+	// to a media.
+	FindBlob(ctx context.Context, sha256 string) (*entities.MediaBlob, error)
+	// CreateBlob returns ErrBlobExists when a blob with the same digest was
+	// created first.
+	CreateBlob(ctx context.Context, blob *entities.MediaBlob) error
+	// FindUnreferencedBlobs returns the blobs no media has pointed to since
+	// before.
+	FindUnreferencedBlobs(ctx context.Context, before time.Time) ([]*entities.MediaBlob, error)
+	// DeleteBlob deletes a blob found by FindUnreferencedBlobs, or returns
+	// ErrBlobNotFound if it has been used since.
+	DeleteBlob(ctx context.Context, blobID uuid.UUID, before time.Time) error
+	BlockFeedMedia(ctx context.Context, feedID uuid.UUID) error
+	// UnblockFeedMedia lifts the blocks recorded for a feed. Content another
+	// removed feed shares stays blocked.
+	UnblockFeedMedia(ctx context.Context, feedID uuid.UUID) error
+	// IsBlocked reports whether content with the given digest, or a perceptual
+	// hash at most maxDistance bits away from phash, was removed.
+	IsBlocked(ctx context.Context, sha256 string, phash *uint64, maxDistance int) (bool, error)
+}
+
+type mediaRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewMediaRepository(db *sqlx.DB) MediaRepository {
+	return &mediaRepositoryImpl{db: db}
+}
+
+type blobRow struct {
+	ID         uuid.UUID     `db:"id"`
+	SHA256     string        `db:"sha256"`
+	PHash      sql.NullInt64 `db:"phash"`
+	Type       string        `db:"type"`
+	Url        string        `db:"url"`
+	Variants   mediaVariants `db:"variants"`
+	Blurhash   string        `db:"blurhash"`
+	PosterUrl  string        `db:"poster_url"`
+	DurationMs int           `db:"duration_ms"`
+	Width      int           `db:"width"`
+	Height     int           `db:"height"`
+	Keys       blobKeys      `db:"keys"`
+	RefCount   int           `db:"ref_count"`
+	CreatedAt  time.Time     `db:"created_at"`
+	UpdatedAt  time.Time     `db:"updated_at"`
+}
+
+func (r blobRow) toEntity() *entities.MediaBlob {
+	blob := &entities.MediaBlob{
+		ID:         r.ID,
+		SHA256:     r.SHA256,
+		Type:       r.Type,
+		Url:        r.Url,
+		Variants:   r.Variants,
+		Blurhash:   r.Blurhash,
+		PosterUrl:  r.PosterUrl,
+		DurationMs: r.DurationMs,
+		Width:      r.Width,
+		Height:     r.Height,
+		Keys:       r.Keys,
+		RefCount:   r.RefCount,
+		CreatedAt:  r.CreatedAt,
+		UpdatedAt:  r.UpdatedAt,
+	}
+
+	if r.PHash.Valid {
+		phash := uint64(r.PHash.Int64)
+		blob.PHash = &phash
+	}
+
+	return blob
+}
+
+// FindReferencedURLs returns every URL of a stored file in use: feed media,
+// their variants and posters, the blobs they share, and avatars.
+func (r *mediaRepositoryImpl) FindReferencedURLs(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT url FROM feed_media WHERE url <> ''
+		UNION
+		SELECT poster_url FROM feed_media WHERE poster_url <> ''
+		UNION
+		SELECT variant.value FROM feed_media, jsonb_each_text(feed_media.variants) AS variant
+		UNION
+		SELECT url FROM media_blobs
+		UNION
+		SELECT poster_url FROM media_blobs WHERE poster_url <> ''
+		UNION
+		SELECT variant.value FROM media_blobs, jsonb_each_text(media_blobs.variants) AS variant
+		UNION
+		SELECT avatar FROM users WHERE avatar <> ''
+	`
+
+	urls := make([]string, 0)
+
+	if err := r.db.SelectContext(ctx, &urls, query); err != nil {
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+func (r *mediaRepositoryImpl) FindBlob(ctx context.Context, sha256 string) (*entities.MediaBlob, error) {
+	query := `
+		UPDATE media_blobs
+		SET updated_at = NOW()
+		WHERE sha256 = $1
+		RETURNING ` + blobColumns
+
+	var row blobRow
+	err := r.db.GetContext(ctx, &row, query, sha256)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrBlobNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return row.toEntity(), nil
+}
+
+func (r *mediaRepositoryImpl) CreateBlob(ctx context.Context, blob *entities.MediaBlob) error {
+	query := `
+		INSERT INTO media_blobs (sha256, phash, type, url, variants, blurhash, poster_url, duration_ms, width, height, keys)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (sha256) DO NOTHING
+		RETURNING id, ref_count, created_at, updated_at
+	`
+
+	var phash any
+	if blob.PHash != nil {
+		phash = int64(*blob.PHash)
+	}
+
+	err := r.db.QueryRowxContext(ctx, query,
+		blob.SHA256, phash, blob.Type, blob.Url, mediaVariants(blob.Variants),
+		blob.Blurhash, blob.PosterUrl, blob.DurationMs, blob.Width, blob.Height,
+		blobKeys(blob.Keys),
+	).Scan(&blob.ID, &blob.RefCount, &blob.CreatedAt, &blob.UpdatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrBlobExists
+	}
+
+	return err
+}
+
+func (r *mediaRepositoryImpl) FindUnreferencedBlobs(ctx context.Context, before time.Time) ([]*entities.MediaBlob, error) {
+	query := `
+		SELECT ` + blobColumns + `
+		FROM media_blobs
+		WHERE ref_count <= 0 AND updated_at < $1
+	`
+
+	rows := make([]blobRow, 0)
+	if err := r.db.SelectContext(ctx, &rows, query, before); err != nil {
+		return nil, err
+	}
+
+	blobs := make([]*entities.MediaBlob, len(rows))
+	for i, row := range rows {
+		blobs[i] = row.toEntity()
+	}
+
+	return blobs, nil
+}
+
+func (r *mediaRepositoryImpl) DeleteBlob(ctx context.Context, blobID uuid.UUID, before time.Time) error {
+	query := `DELETE FROM media_blobs WHERE id = $1 AND ref_count <= 0 AND updated_at < $2`
+
+	result, err := r.db.ExecContext(ctx, query, blobID, before)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrBlobNotFound
+	}
+
+	return nil
+}
+
+// BlockFeedMedia records the content of every media of a feed as removed.
+// Each feed keeps its own rows, so the same content removed from several
+// feeds is blocked once per feed.
+func (r *mediaRepositoryImpl) BlockFeedMedia(ctx context.Context, feedID uuid.UUID) error {
+	query := `
+		INSERT INTO blocked_media (sha256, phash, feed_id)
+		SELECT b.sha256, b.phash, fm.feed_id
+		FROM feed_media fm
+		JOIN media_blobs b ON b.id = fm.blob_id
+		WHERE fm.feed_id = $1
+		ON CONFLICT (sha256, feed_id) DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, feedID)
+	return err
+}
+
+func (r *mediaRepositoryImpl) UnblockFeedMedia(ctx context.Context, feedID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM blocked_media WHERE feed_id = $1`, feedID)
+	return err
+}
+
+func (r *mediaRepositoryImpl) IsBlocked(ctx context.Context, sha256 string, phash *uint64, maxDistance int) (bool, error) {
+	// The distance is the number of ones in the XOR of both hashes.
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM blocked_media
+			WHERE sha256 = $1
+				OR ($2::BIGINT IS NOT NULL AND phash IS NOT NULL
+					AND LENGTH(REPLACE(((phash # $2::BIGINT)::BIT(64))::TEXT, '0', '')) <= $3)
+		)
+	`
+
+	var hash any
+	if phash != nil {
+		hash = int64(*phash)
+	}
+
+	var blocked bool
+	err := r.db.GetContext(ctx, &blocked, query, sha256, hash, maxDistance)
+	return blocked, err
+}
+
+// blobKeys lists the storage keys of a blob, stored as a JSON array.
+type blobKeys []string
+
+func (k *blobKeys) Scan(src any) error {
+	var raw []byte
+
+	switch s := src.(type) {
+	case nil:
+		*k = blobKeys{}
+		return nil
+	case []byte:
+		raw = s
+	case string:
+		raw = []byte(s)
+	default:
+		return fmt.Errorf("cannot scan %T into blob keys", src)
+	}
+
+	keys := make(blobKeys, 0)
+	if err := json.Unmarshal(raw, &keys); err != nil {
+		return err
+	}
+
+	*k = keys
+	return nil
+}
+
+func (k blobKeys) Value() (driver.Value, error) {
+	if k == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(k))
+}
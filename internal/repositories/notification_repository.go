@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type notificationRow struct {
+	ID        uuid.UUID  `db:"id"`
+	UserID    uuid.UUID  `db:"user_id"`
+	ActorID   uuid.UUID  `db:"actor_id"`
+	Type      string     `db:"type"`
+	FeedID    *uuid.UUID `db:"feed_id"`
+	CommentID *uuid.UUID `db:"comment_id"`
+	IsRead    bool       `db:"is_read"`
+	CreatedAt time.Time  `db:"created_at"`
+
+	ActorUsername string `db:"username"`
+	ActorAvatar   string `db:"avatar"`
+}
+
+type NotificationRepository interface {
+	CreateMany(ctx context.Context, notifications []*entities.Notification) error
+	FindByUser(ctx context.Context, userID uuid.UUID, excludedActorIDs []uuid.UUID, limit int) ([]*entities.Notification, error)
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+}
+
+type notificationRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewNotificationRepository(db *sqlx.DB) NotificationRepository {
+	return &notificationRepositoryImpl{db: db}
+}
+
+func (r *notificationRepositoryImpl) CreateMany(ctx context.Context, notifications []*entities.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO notifications (user_id, actor_id, type, feed_id, comment_id)
+		VALUES (:user_id, :actor_id, :type, :feed_id, :comment_id)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, notifications)
+	return err
+}
+
+func (r *notificationRepositoryImpl) FindByUser(ctx context.Context, userID uuid.UUID, excludedActorIDs []uuid.UUID, limit int) ([]*entities.Notification, error) {
+	query := `
+		SELECT
+			n.id,
+			n.user_id,
+			n.actor_id,
+			n.type,
+			n.feed_id,
+			n.comment_id,
+			n.is_read,
+			n.created_at,
+			u.username,
+			COALESCE(u.avatar, '') AS avatar
+		FROM notifications n
+		JOIN users u ON u.id = n.actor_id
+		WHERE n.user_id = $1
+			AND NOT (n.actor_id = ANY($3))
+		ORDER BY n.created_at DESC
+		LIMIT $2;
+	`
+
+	rows := make([]notificationRow, 0)
+
+	if err := r.db.SelectContext(ctx, &rows, query, userID, limit, excludedActorIDs); err != nil {
+		return nil, err
+	}
+
+	notifications := make([]*entities.Notification, len(rows))
+
+	for i, row := range rows {
+		notifications[i] = &entities.Notification{
+			ID:        row.ID,
+			UserID:    row.UserID,
+			ActorID:   row.ActorID,
+			Type:      row.Type,
+			FeedID:    row.FeedID,
+			CommentID: row.CommentID,
+			IsRead:    row.IsRead,
+			CreatedAt: row.CreatedAt,
+			Actor: &entities.User{
+				ID:       row.ActorID,
+				Username: row.ActorUsername,
+				Avatar:   row.ActorAvatar,
+			},
+		}
+	}
+
+	return notifications, nil
+}
+
+func (r *notificationRepositoryImpl) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE notifications
+		SET is_read = TRUE
+		WHERE user_id = $1 AND is_read = FALSE;
+	`
+	_, err := r.db.ExecContext(ctx, query, userID)
+	return err
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// reactionCounts holds the number of reactions of each type, scanned from the
+// JSON object built by reactionColumns.
+type reactionCounts map[string]int
+
+func (c *reactionCounts) Scan(src any) error {
+	var raw []byte
+
+	switch v := src.(type) {
+	case nil:
+		*c = reactionCounts{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into reaction counts", src)
+	}
+
+	counts := make(reactionCounts)
+	if err := json.Unmarshal(raw, &counts); err != nil {
+		return err
+	}
+
+	*c = counts
+	return nil
+}
+
+// reactionColumns selects the total reaction count of the feed aliased as
+// alias and its count per reaction type.
+func reactionColumns(alias string) string {
+	return fmt.Sprintf(`(
+			  SELECT COUNT(*)
+			  FROM feed_reactions fr
+			  WHERE fr.feed_id = %[1]s.id
+			) AS reaction_count,
+			(
+			  SELECT COALESCE(jsonb_object_agg(rc.type, rc.total), '{}'::jsonb)
+			  FROM (
+				SELECT fr.type, COUNT(*) AS total
+				FROM feed_reactions fr
+				WHERE fr.feed_id = %[1]s.id
+				GROUP BY fr.type
+			  ) rc
+			) AS reactions`, alias)
+}
@@ -0,0 +1,380 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/pkg/cursor"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type reportRow struct {
+	ID          uuid.UUID  `db:"id"`
+	ReporterID  *uuid.UUID `db:"reporter_id"`
+	TargetType  string     `db:"target_type"`
+	TargetID    uuid.UUID  `db:"target_id"`
+	Reason      string     `db:"reason"`
+	Note        string     `db:"note"`
+	Status      string     `db:"status"`
+	ResolvedBy  *uuid.UUID `db:"resolved_by"`
+	ResolvedAt  *time.Time `db:"resolved_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+	ReportCount int        `db:"report_count"`
+
+	ReporterUsername string `db:"username"`
+	ReporterAvatar   string `db:"avatar"`
+}
+
+var (
+	ErrReportNotFound       = errors.New("report not found")
+	ErrReportTargetNotFound = errors.New("reported content not found")
+)
+
+type ReportRepository interface {
+	Create(ctx context.Context, report *entities.Report) (*entities.Report, error)
+	CreateSystemReport(ctx context.Context, report *entities.Report) (*entities.Report, error)
+	FindByID(ctx context.Context, reportID uuid.UUID) (*entities.Report, error)
+	FindByStatus(ctx context.Context, status string, after *cursor.Cursor, limit int) ([]*entities.Report, error)
+	FindTargetOwner(ctx context.Context, targetType string, targetID uuid.UUID) (uuid.UUID, error)
+	CountOpenReports(ctx context.Context, targetType string, targetID uuid.UUID) (int, error)
+	ResolveTarget(ctx context.Context, targetType string, targetID uuid.UUID, status string, moderatorID uuid.UUID) error
+	HideContent(ctx context.Context, targetType string, targetID uuid.UUID, until *time.Time) (bool, error)
+	RemoveContent(ctx context.Context, targetType string, targetID uuid.UUID) error
+	UnhideContent(ctx context.Context, targetType string, targetID uuid.UUID) (bool, error)
+	CreateAuditLog(ctx context.Context, log *entities.AuditLog) error
+	FindAuditLogs(ctx context.Context, limit int) ([]*entities.AuditLog, error)
+}
+
+type reportRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewReportRepository(db *sqlx.DB) ReportRepository {
+	return &reportRepositoryImpl{db: db}
+}
+
+// contentTables maps a reportable content type to the table holding it.
+var contentTables = map[string]string{
+	entities.ReportTargetFeed:    "feeds",
+	entities.ReportTargetComment: "feed_comments",
+}
+
+// Create files a report. Reporting the same target again replaces the reason
+// and reopens the report if it was already resolved.
+func (r *reportRepositoryImpl) Create(ctx context.Context, report *entities.Report) (*entities.Report, error) {
+	query := `
+		INSERT INTO reports (reporter_id, target_type, target_id, reason, note)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (reporter_id, target_type, target_id) DO UPDATE
+		SET reason = EXCLUDED.reason,
+			note = EXCLUDED.note,
+			status = 'open',
+			resolved_by = NULL,
+			resolved_at = NULL
+		RETURNING id, status, created_at;
+	`
+
+	err := r.db.QueryRowContext(ctx, query, report.ReporterID, report.TargetType, report.TargetID, report.Reason, report.Note).
+		Scan(&report.ID, &report.Status, &report.CreatedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// CreateSystemReport files a report without a reporter, used when content is
+// flagged automatically.
+func (r *reportRepositoryImpl) CreateSystemReport(ctx context.Context, report *entities.Report) (*entities.Report, error) {
+	query := `
+		INSERT INTO reports (target_type, target_id, reason, note)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, created_at;
+	`
+
+	err := r.db.QueryRowContext(ctx, query, report.TargetType, report.TargetID, report.Reason, report.Note).
+		Scan(&report.ID, &report.Status, &report.CreatedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+const reportColumns = `
+	r.id,
+	r.reporter_id,
+	r.target_type,
+	r.target_id,
+	r.reason,
+	COALESCE(r.note, '') AS note,
+	r.status,
+	r.resolved_by,
+	r.resolved_at,
+	r.created_at,
+	(
+	  SELECT COUNT(*)
+	  FROM reports rc
+	  WHERE rc.target_type = r.target_type
+		AND rc.target_id = r.target_id
+		AND rc.status = 'open'
+	) AS report_count,
+	COALESCE(u.username, '') AS username,
+	COALESCE(u.avatar, '') AS avatar
+`
+
+func (r *reportRepositoryImpl) FindByID(ctx context.Context, reportID uuid.UUID) (*entities.Report, error) {
+	var row reportRow
+
+	query := `
+		SELECT ` + reportColumns + `
+		FROM reports r
+		LEFT JOIN users u ON u.id = r.reporter_id
+		WHERE r.id = $1;
+	`
+
+	err := r.db.GetContext(ctx, &row, query, reportID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrReportNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return row.toEntity(), nil
+}
+
+// FindByStatus lists reports with the given status, newest first.
+func (r *reportRepositoryImpl) FindByStatus(ctx context.Context, status string, after *cursor.Cursor, limit int) ([]*entities.Report, error) {
+	args := []interface{}{status, limit}
+
+	query := `
+		SELECT ` + reportColumns + `
+		FROM reports r
+		LEFT JOIN users u ON u.id = r.reporter_id
+		WHERE r.status = $1
+	`
+
+	if after != nil {
+		query += ` AND (r.created_at, r.id) < ($3::timestamp, $4)`
+		args = append(args, after.CreatedAt, after.ID)
+	}
+
+	query += `
+		ORDER BY r.created_at DESC, r.id DESC
+		LIMIT $2
+	`
+
+	rows := make([]reportRow, 0)
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	reports := make([]*entities.Report, len(rows))
+
+	for i, row := range rows {
+		reports[i] = row.toEntity()
+	}
+
+	return reports, nil
+}
+
+// FindTargetOwner returns the author of a reported feed or comment, or the
+// reported user itself.
+func (r *reportRepositoryImpl) FindTargetOwner(ctx context.Context, targetType string, targetID uuid.UUID) (uuid.UUID, error) {
+	var query string
+
+	switch targetType {
+	case entities.ReportTargetFeed:
+		query = `SELECT user_id FROM feeds WHERE id = $1;`
+	case entities.ReportTargetComment:
+		query = `SELECT user_id FROM feed_comments WHERE id = $1;`
+	case entities.ReportTargetUser:
+		query = `SELECT id FROM users WHERE id = $1;`
+	default:
+		return uuid.Nil, ErrReportTargetNotFound
+	}
+
+	var ownerID uuid.UUID
+
+	err := r.db.QueryRowContext(ctx, query, targetID).Scan(&ownerID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, ErrReportTargetNotFound
+	}
+
+	return ownerID, err
+}
+
+func (r *reportRepositoryImpl) CountOpenReports(ctx context.Context, targetType string, targetID uuid.UUID) (int, error) {
+	var count int
+
+	query := `
+		SELECT COUNT(*)
+		FROM reports
+		WHERE target_type = $1 AND target_id = $2 AND status = 'open';
+	`
+
+	err := r.db.QueryRowContext(ctx, query, targetType, targetID).Scan(&count)
+	return count, err
+}
+
+// ResolveTarget closes every open report on the target with the given status.
+func (r *reportRepositoryImpl) ResolveTarget(ctx context.Context, targetType string, targetID uuid.UUID, status string, moderatorID uuid.UUID) error {
+	query := `
+		UPDATE reports
+		SET status = $3,
+			resolved_by = $4,
+			resolved_at = NOW()
+		WHERE target_type = $1 AND target_id = $2 AND status = 'open';
+	`
+	_, err := r.db.ExecContext(ctx, query, targetType, targetID, status, moderatorID)
+	return err
+}
+
+// HideContent hides a feed or comment until the given time, or for good when
+// until is nil. Content that is already hidden for good is left untouched and
+// false is returned.
+func (r *reportRepositoryImpl) HideContent(ctx context.Context, targetType string, targetID uuid.UUID, until *time.Time) (bool, error) {
+	table, ok := contentTables[targetType]
+
+	if !ok {
+		return false, nil
+	}
+
+	query := `
+		UPDATE ` + table + `
+		SET is_hidden = TRUE,
+			hidden_until = $2
+		WHERE id = $1
+			AND NOT (is_hidden AND hidden_until IS NULL);
+	`
+
+	result, err := r.db.ExecContext(ctx, query, targetID, until)
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+
+	return affected > 0, err
+}
+
+// RemoveContent hides a feed or comment for good on a moderator's decision.
+// Removed content is not restored when later reports on it are dismissed.
+func (r *reportRepositoryImpl) RemoveContent(ctx context.Context, targetType string, targetID uuid.UUID) error {
+	table, ok := contentTables[targetType]
+
+	if !ok {
+		return nil
+	}
+
+	query := `
+		UPDATE ` + table + `
+		SET is_hidden = TRUE,
+			hidden_until = NULL,
+			is_removed = TRUE
+		WHERE id = $1;
+	`
+	_, err := r.db.ExecContext(ctx, query, targetID)
+	return err
+}
+
+// UnhideContent lifts an automatic hide of a feed or comment. Content removed
+// by a moderator is left untouched and false is returned.
+func (r *reportRepositoryImpl) UnhideContent(ctx context.Context, targetType string, targetID uuid.UUID) (bool, error) {
+	table, ok := contentTables[targetType]
+
+	if !ok {
+		return false, nil
+	}
+
+	query := `
+		UPDATE ` + table + `
+		SET is_hidden = FALSE,
+			hidden_until = NULL
+		WHERE id = $1
+			AND is_hidden
+			AND NOT is_removed;
+	`
+
+	result, err := r.db.ExecContext(ctx, query, targetID)
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+
+	return affected > 0, err
+}
+
+func (r *reportRepositoryImpl) CreateAuditLog(ctx context.Context, log *entities.AuditLog) error {
+	query := `
+		INSERT INTO moderation_audit_logs (moderator_id, action, report_id, target_type, target_id, note)
+		VALUES ($1, $2, $3, $4, $5, $6);
+	`
+	_, err := r.db.ExecContext(ctx, query, log.ModeratorID, log.Action, log.ReportID, log.TargetType, log.TargetID, log.Note)
+	return err
+}
+
+func (r *reportRepositoryImpl) FindAuditLogs(ctx context.Context, limit int) ([]*entities.AuditLog, error) {
+	logs := make([]*entities.AuditLog, 0)
+
+	query := `
+		SELECT
+			id,
+			moderator_id,
+			action,
+			report_id,
+			target_type,
+			target_id,
+			COALESCE(note, '') AS note,
+			created_at
+		FROM moderation_audit_logs
+		ORDER BY created_at DESC
+		LIMIT $1;
+	`
+
+	if err := r.db.SelectContext(ctx, &logs, query, limit); err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (row reportRow) toEntity() *entities.Report {
+	report := &entities.Report{
+		ID:          row.ID,
+		TargetType:  row.TargetType,
+		TargetID:    row.TargetID,
+		Reason:      row.Reason,
+		Note:        row.Note,
+		Status:      row.Status,
+		ResolvedBy:  row.ResolvedBy,
+		ResolvedAt:  row.ResolvedAt,
+		CreatedAt:   row.CreatedAt,
+		ReportCount: row.ReportCount,
+	}
+
+	if row.ReporterID != nil {
+		report.ReporterID = *row.ReporterID
+		report.Reporter = &entities.User{
+			ID:       *row.ReporterID,
+			Username: row.ReporterUsername,
+			Avatar:   row.ReporterAvatar,
+		}
+	}
+
+	return report
+}
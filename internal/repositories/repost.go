@@ -0,0 +1,12 @@
+package repositories
+
+import "fmt"
+
+// repostColumns selects how many times the feed aliased as alias was reposted.
+func repostColumns(alias string) string {
+	return fmt.Sprintf(`(
+			  SELECT COUNT(*)
+			  FROM feed_reposts rp
+			  WHERE rp.feed_id = %[1]s.id
+			) AS reposts`, alias)
+}
@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+)
+
+type suggestionRow struct {
+	UserID         uuid.UUID `db:"user_id"`
+	Username       string    `db:"username"`
+	Avatar         string    `db:"avatar"`
+	MutualID       uuid.UUID `db:"mutual_id"`
+	MutualUsername string    `db:"mutual_username"`
+	MutualAvatar   string    `db:"mutual_avatar"`
+}
+
+type SuggestionRepository interface {
+	FindFriendsOfFriends(ctx context.Context, userID uuid.UUID, limit, mutualLimit int) ([]*entities.Suggestion, error)
+	FindExcludedIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	FindUserIDsWithFollowings(ctx context.Context, afterID uuid.UUID, limit int) ([]uuid.UUID, error)
+	GetCached(ctx context.Context, userID uuid.UUID) ([]*entities.Suggestion, bool, error)
+	SaveCached(ctx context.Context, userID uuid.UUID, suggestions []*entities.Suggestion, ttl time.Duration) error
+}
+
+type suggestionRepositoryImpl struct {
+	db  *sqlx.DB
+	rdb *redis.Client
+}
+
+func NewSuggestionRepository(db *sqlx.DB, rdb *redis.Client) SuggestionRepository {
+	return &suggestionRepositoryImpl{
+		db:  db,
+		rdb: rdb,
+	}
+}
+
+// FindFriendsOfFriends returns accounts followed by the people userID follows,
+// that userID does not follow yet and has no block with, ranked by how many of
+// those people follow them. Only the first mutualLimit mutual followers are kept per suggestion.
+func (r *suggestionRepositoryImpl) FindFriendsOfFriends(ctx context.Context, userID uuid.UUID, limit, mutualLimit int) ([]*entities.Suggestion, error) {
+	query := `
+		WITH followings AS (
+			SELECT following_id FROM user_folows WHERE follower_id = $1
+		),
+		candidates AS (
+			SELECT f2.following_id AS user_id, COUNT(*) AS mutual_count
+			FROM user_folows f2
+			WHERE f2.follower_id IN (SELECT following_id FROM followings)
+				AND f2.following_id <> $1
+				AND f2.following_id NOT IN (SELECT following_id FROM followings)
+				AND ` + notBlocked("f2.following_id", "$1") + `
+			GROUP BY f2.following_id
+			ORDER BY mutual_count DESC, f2.following_id
+			LIMIT $2
+		)
+		SELECT
+			u.id AS user_id,
+			u.username,
+			COALESCE(u.avatar, '') AS avatar,
+			m.id AS mutual_id,
+			m.username AS mutual_username,
+			COALESCE(m.avatar, '') AS mutual_avatar
+		FROM candidates c
+		JOIN users u ON u.id = c.user_id
+		JOIN user_folows f2 ON f2.following_id = c.user_id
+		JOIN users m ON m.id = f2.follower_id
+		WHERE f2.follower_id IN (SELECT following_id FROM followings)
+		ORDER BY c.mutual_count DESC, u.id, m.username;
+	`
+
+	rows := make([]suggestionRow, 0)
+
+	if err := r.db.SelectContext(ctx, &rows, query, userID, limit); err != nil {
+		return nil, err
+	}
+
+	suggestionMap := make(map[uuid.UUID]*entities.Suggestion)
+	suggestions := make([]*entities.Suggestion, 0)
+
+	for _, row := range rows {
+		suggestion, ok := suggestionMap[row.UserID]
+
+		if !ok {
+			suggestion = &entities.Suggestion{
+				User: &entities.User{
+					ID:       row.UserID,
+					Username: row.Username,
+					Avatar:   row.Avatar,
+				},
+				Mutuals: []*entities.User{},
+			}
+			suggestionMap[row.UserID] = suggestion
+			suggestions = append(suggestions, suggestion)
+		}
+
+		suggestion.MutualCount++
+
+		if len(suggestion.Mutuals) < mutualLimit {
+			suggestion.Mutuals = append(suggestion.Mutuals, &entities.User{
+				ID:       row.MutualID,
+				Username: row.MutualUsername,
+				Avatar:   row.MutualAvatar,
+			})
+		}
+	}
+
+	return suggestions, nil
+}
+
+// FindExcludedIDs returns the accounts that must not be suggested to userID:
+// the ones it follows and the ones it has a block with in either direction.
+func (r *suggestionRepositoryImpl) FindExcludedIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+
+	query := `
+		SELECT following_id FROM user_folows WHERE follower_id = $1
+		UNION
+		SELECT blocked_id FROM user_blocks WHERE blocker_id = $1
+		UNION
+		SELECT blocker_id FROM user_blocks WHERE blocked_id = $1;
+	`
+
+	if err := r.db.SelectContext(ctx, &ids, query, userID); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *suggestionRepositoryImpl) FindUserIDsWithFollowings(ctx context.Context, afterID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+
+	query := `
+		SELECT DISTINCT follower_id
+		FROM user_folows
+		WHERE follower_id > $1
+		ORDER BY follower_id
+		LIMIT $2;
+	`
+
+	if err := r.db.SelectContext(ctx, &ids, query, afterID, limit); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *suggestionRepositoryImpl) GetCached(ctx context.Context, userID uuid.UUID) ([]*entities.Suggestion, bool, error) {
+	data, err := r.rdb.Get(ctx, suggestionKey(userID)).Bytes()
+
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	suggestions := make([]*entities.Suggestion, 0)
+
+	if err := json.Unmarshal(data, &suggestions); err != nil {
+		return nil, false, err
+	}
+
+	return suggestions, true, nil
+}
+
+func (r *suggestionRepositoryImpl) SaveCached(ctx context.Context, userID uuid.UUID, suggestions []*entities.Suggestion, ttl time.Duration) error {
+	data, err := json.Marshal(suggestions)
+
+	if err != nil {
+		return err
+	}
+
+	return r.rdb.Set(ctx, suggestionKey(userID), data, ttl).Err()
+}
+
+func suggestionKey(userID uuid.UUID) string {
+	return "suggestions:" + userID.String()
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+)
+
+type TrendingRepository interface {
+	FindFeedEngagements(ctx context.Context, since time.Time) ([]*entities.Engagement, error)
+	FindHashtagEngagements(ctx context.Context, since time.Time) ([]*entities.Engagement, error)
+	SaveRanking(ctx context.Context, key string, items []*entities.RankedItem) error
+	GetRanking(ctx context.Context, key string, limit int) ([]*entities.RankedItem, error)
+}
+
+type trendingRepositoryImpl struct {
+	db  *sqlx.DB
+	rdb *redis.Client
+}
+
+func NewTrendingRepository(db *sqlx.DB, rdb *redis.Client) TrendingRepository {
+	return &trendingRepositoryImpl{
+		db:  db,
+		rdb: rdb,
+	}
+}
+
+func (r *trendingRepositoryImpl) FindFeedEngagements(ctx context.Context, since time.Time) ([]*entities.Engagement, error) {
+	engagements := make([]*entities.Engagement, 0)
+
+	query := `
+		SELECT f.id::text AS key, 'post' AS kind, f.published_at AS created_at
+		FROM feeds f
+		WHERE f.published_at >= $1
+			AND ` + published("f") + `
+		UNION ALL
+		SELECT fr.feed_id::text AS key, 'reaction' AS kind, fr.created_at
+		FROM feed_reactions fr
+		WHERE fr.created_at >= $1
+		UNION ALL
+		SELECT fc.feed_id::text AS key, 'comment' AS kind, fc.created_at
+		FROM feed_comments fc
+		WHERE fc.created_at >= $1;
+	`
+
+	if err := r.db.SelectContext(ctx, &engagements, query, since); err != nil {
+		return nil, err
+	}
+
+	return engagements, nil
+}
+
+func (r *trendingRepositoryImpl) FindHashtagEngagements(ctx context.Context, since time.Time) ([]*entities.Engagement, error) {
+	engagements := make([]*entities.Engagement, 0)
+
+	query := `
+		SELECT h.name AS key, 'post' AS kind, GREATEST(fh.created_at, f.published_at) AS created_at
+		FROM feed_hashtags fh
+		JOIN hashtags h ON h.id = fh.hashtag_id
+		JOIN feeds f ON f.id = fh.feed_id
+		WHERE GREATEST(fh.created_at, f.published_at) >= $1
+			AND ` + published("f") + `
+		UNION ALL
+		SELECT h.name AS key, 'reaction' AS kind, fr.created_at
+		FROM feed_reactions fr
+		JOIN feed_hashtags fh ON fh.feed_id = fr.feed_id
+		JOIN hashtags h ON h.id = fh.hashtag_id
+		WHERE fr.created_at >= $1
+		UNION ALL
+		SELECT h.name AS key, 'comment' AS kind, fc.created_at
+		FROM feed_comments fc
+		JOIN feed_hashtags fh ON fh.feed_id = fc.feed_id
+		JOIN hashtags h ON h.id = fh.hashtag_id
+		WHERE fc.created_at >= $1;
+	`
+
+	if err := r.db.SelectContext(ctx, &engagements, query, since); err != nil {
+		return nil, err
+	}
+
+	return engagements, nil
+}
+
+// SaveRanking replaces the sorted set at key. The new ranking is written to a
+// temporary key first and renamed, so readers never see a half-written set.
+func (r *trendingRepositoryImpl) SaveRanking(ctx context.Context, key string, items []*entities.RankedItem) error {
+	if len(items) == 0 {
+		return r.rdb.Del(ctx, key).Err()
+	}
+
+	tmpKey := key + ":tmp"
+	members := make([]redis.Z, len(items))
+
+	for i, item := range items {
+		members[i] = redis.Z{Score: item.Score, Member: item.Key}
+	}
+
+	pipe := r.rdb.TxPipeline()
+	pipe.Del(ctx, tmpKey)
+	pipe.ZAdd(ctx, tmpKey, members...)
+	pipe.Rename(ctx, tmpKey, key)
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *trendingRepositoryImpl) GetRanking(ctx context.Context, key string, limit int) ([]*entities.RankedItem, error) {
+	members, err := r.rdb.ZRevRangeWithScores(ctx, key, 0, int64(limit-1)).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*entities.RankedItem, len(members))
+
+	for i, m := range members {
+		items[i] = &entities.RankedItem{
+			Key:   m.Member.(string),
+			Score: m.Score,
+		}
+	}
+
+	return items, nil
+}
@@ -1,13 +1,28 @@
 package repositories
 
 import (
+	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/pkg/cursor"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
+type followUserRow struct {
+	FollowID         uuid.UUID `db:"follow_id"`
+	FollowedAt       time.Time `db:"followed_at"`
+	FollowedByViewer bool      `db:"followed_by_viewer"`
+	FollowsViewer    bool      `db:"follows_viewer"`
+
+	ID       uuid.UUID `db:"id"`
+	Username string    `db:"username"`
+	Avatar   string    `db:"avatar"`
+	Bio      string    `db:"bio"`
+}
+
 type UserRepository interface {
 	Find(search string) ([]*entities.User, error)
 	Create(user *entities.User) (*entities.User, error)
@@ -15,6 +30,31 @@ type UserRepository interface {
 	FindByUsername(username string) (*entities.User, error)
 	FindByID(id uuid.UUID) (*entities.User, error)
 	ToggleFollow(followerID, followingID uuid.UUID) (string, error)
+	IsFollowing(followerID, followingID uuid.UUID) (bool, error)
+	CanView(viewerID, ownerID uuid.UUID) (bool, error)
+	Unfollow(followerID, followingID uuid.UUID) error
+	Block(blockerID, blockedID uuid.UUID) error
+	Unblock(blockerID, blockedID uuid.UUID) error
+	IsBlockedBetween(userID, otherID uuid.UUID) (bool, error)
+	Mute(muterID, mutedID uuid.UUID) error
+	Unmute(muterID, mutedID uuid.UUID) error
+	FindFollowingIDs(userID uuid.UUID) ([]uuid.UUID, error)
+	FindBlockedIDs(userID uuid.UUID) ([]uuid.UUID, error)
+	FindMutedIDs(userID uuid.UUID) ([]uuid.UUID, error)
+	FindPage(search string, after *cursor.Cursor, limit int) ([]*entities.User, error)
+	UpdateRole(userID uuid.UUID, role string) error
+	Suspend(userID uuid.UUID) error
+	Unsuspend(userID uuid.UUID) error
+	FindAccountStatus(userID uuid.UUID) (string, bool, error)
+	ToggleFollowRequest(requesterID, targetID uuid.UUID) (string, error)
+	CancelFollowRequest(requesterID, targetID uuid.UUID) error
+	FindFollowRequests(targetID uuid.UUID) ([]*entities.FollowRequest, error)
+	ApproveFollowRequest(requestID, targetID uuid.UUID) error
+	RejectFollowRequest(requestID, targetID uuid.UUID) error
+	ApproveAllFollowRequests(targetID uuid.UUID) error
+	FindFollowers(userID, viewerID uuid.UUID, after *cursor.Cursor, limit int) ([]*entities.FollowUser, error)
+	FindFollowings(userID, viewerID uuid.UUID, after *cursor.Cursor, limit int) ([]*entities.FollowUser, error)
+	FindMutuals(userID, viewerID uuid.UUID, after *cursor.Cursor, limit int) ([]*entities.FollowUser, error)
 	Update(user *entities.User) (*entities.User, error)
 	Delete(userID uuid.UUID) error
 }
@@ -63,7 +103,7 @@ func (r *userRepositoryImpl) Create(user *entities.User) (*entities.User, error)
 func (r *userRepositoryImpl) FindByEmail(credential string) (*entities.User, error) {
 	user := new(entities.User)
 	query := `
-		SELECT id, username, email, password, created_at, updated_at
+		SELECT id, username, email, password, role, suspended_at, created_at, updated_at
 		FROM users
 		WHERE email = $1 or username = $1;
 	`
@@ -84,6 +124,7 @@ func (r *userRepositoryImpl) FindByUsername(username string) (*entities.User, er
 			u.email,
 			COALESCE(u.avatar, '') AS avatar,
     		COALESCE(u.bio, '') AS bio,
+			u.is_private,
 			u.created_at,
 			COUNT(DISTINCT f1.follower_id) as followers_count,
 			COUNT(DISTINCT f2.following_id) as followings_count
@@ -103,7 +144,7 @@ func (r *userRepositoryImpl) FindByUsername(username string) (*entities.User, er
 func (r *userRepositoryImpl) FindByID(id uuid.UUID) (*entities.User, error) {
 	user := new(entities.User)
 	query := `
-		SELECT id, username, email, bio, avatar, created_at, updated_at
+		SELECT id, username, email, COALESCE(bio, '') AS bio, avatar, is_private, role, suspended_at, created_at, updated_at
 		FROM users
 		WHERE id = $1;  
 	`
@@ -121,11 +162,12 @@ func (r *userRepositoryImpl) Update(user *entities.User) (*entities.User, error)
 		SET username = $1,
 			avatar = $2,
 			bio = $3,
+			is_private = $4,
 			updated_at = NOW()
-		WHERE id = $4
-		RETURNING id, username, email, avatar, bio, created_at, updated_at
+		WHERE id = $5
+		RETURNING id, username, email, avatar, bio, is_private, created_at, updated_at
 	`
-	if err := r.db.Get(updatedUser, query, user.Username, user.Avatar, user.Bio, user.ID); err != nil {
+	if err := r.db.Get(updatedUser, query, user.Username, user.Avatar, user.Bio, user.IsPrivate, user.ID); err != nil {
 		return nil, err
 	}
 
@@ -140,14 +182,14 @@ func (r *userRepositoryImpl) Delete(userID uuid.UUID) error {
 }
 
 func (r *userRepositoryImpl) ToggleFollow(followerID, followingID uuid.UUID) (string, error) {
-	isFollowing, err := r.isFollowing(followerID, followingID)
+	isFollowing, err := r.IsFollowing(followerID, followingID)
 
 	if err != nil {
 		return "", err
 	}
 
 	if isFollowing {
-		err = r.unfollowUser(followerID, followingID)
+		err = r.Unfollow(followerID, followingID)
 		if err != nil {
 			return "", err
 		}
@@ -163,7 +205,7 @@ func (r *userRepositoryImpl) ToggleFollow(followerID, followingID uuid.UUID) (st
 	return "followed", nil
 }
 
-func (r *userRepositoryImpl) isFollowing(followerID, followingID uuid.UUID) (bool, error) {
+func (r *userRepositoryImpl) IsFollowing(followerID, followingID uuid.UUID) (bool, error) {
 	var exits bool
 	query := `
 		SELECT EXISTS (
@@ -185,7 +227,7 @@ func (r *userRepositoryImpl) followUser(followerID, followingID uuid.UUID) error
 	return err
 }
 
-func (r *userRepositoryImpl) unfollowUser(followerID, followingID uuid.UUID) error {
+func (r *userRepositoryImpl) Unfollow(followerID, followingID uuid.UUID) error {
 	query := `
 		DELETE FROM user_folows 
 		WHERE follower_id = $1 AND following_id = $2;
@@ -193,3 +235,433 @@ func (r *userRepositoryImpl) unfollowUser(followerID, followingID uuid.UUID) err
 	_, err := r.db.Exec(query, followerID, followingID)
 	return err
 }
+
+// CanView reports whether the viewer may see the profile timeline, follower
+// lists and content of the owner.
+func (r *userRepositoryImpl) CanView(viewerID, ownerID uuid.UUID) (bool, error) {
+	var visible bool
+	query := `SELECT ` + visibleTo("$2::uuid", "$1::uuid") + `;`
+	err := r.db.Get(&visible, query, viewerID, ownerID)
+	return visible, err
+}
+
+func (r *userRepositoryImpl) Block(blockerID, blockedID uuid.UUID) error {
+	query := `
+		INSERT INTO user_blocks (blocker_id, blocked_id)
+		VALUES ($1, $2)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING;
+	`
+	_, err := r.db.Exec(query, blockerID, blockedID)
+	return err
+}
+
+func (r *userRepositoryImpl) Unblock(blockerID, blockedID uuid.UUID) error {
+	query := `
+		DELETE FROM user_blocks
+		WHERE blocker_id = $1 AND blocked_id = $2;
+	`
+	_, err := r.db.Exec(query, blockerID, blockedID)
+	return err
+}
+
+// IsBlockedBetween reports whether either user has blocked the other.
+func (r *userRepositoryImpl) IsBlockedBetween(userID, otherID uuid.UUID) (bool, error) {
+	var notBlockedBetween bool
+	query := `SELECT ` + notBlocked("$1::uuid", "$2::uuid") + `;`
+	err := r.db.Get(&notBlockedBetween, query, userID, otherID)
+	return !notBlockedBetween, err
+}
+
+func (r *userRepositoryImpl) Mute(muterID, mutedID uuid.UUID) error {
+	query := `
+		INSERT INTO user_mutes (muter_id, muted_id)
+		VALUES ($1, $2)
+		ON CONFLICT (muter_id, muted_id) DO NOTHING;
+	`
+	_, err := r.db.Exec(query, muterID, mutedID)
+	return err
+}
+
+func (r *userRepositoryImpl) Unmute(muterID, mutedID uuid.UUID) error {
+	query := `
+		DELETE FROM user_mutes
+		WHERE muter_id = $1 AND muted_id = $2;
+	`
+	_, err := r.db.Exec(query, muterID, mutedID)
+	return err
+}
+
+func (r *userRepositoryImpl) FindFollowingIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+	query := `
+		SELECT following_id
+		FROM user_folows
+		WHERE follower_id = $1;
+	`
+	err := r.db.Select(&ids, query, userID)
+	return ids, err
+}
+
+// FindBlockedIDs returns the users userID has blocked or been blocked by.
+func (r *userRepositoryImpl) FindBlockedIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+	query := `
+		SELECT blocked_id FROM user_blocks WHERE blocker_id = $1
+		UNION
+		SELECT blocker_id FROM user_blocks WHERE blocked_id = $1;
+	`
+	err := r.db.Select(&ids, query, userID)
+	return ids, err
+}
+
+func (r *userRepositoryImpl) FindMutedIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+	query := `
+		SELECT muted_id
+		FROM user_mutes
+		WHERE muter_id = $1;
+	`
+	err := r.db.Select(&ids, query, userID)
+	return ids, err
+}
+
+// FindPage lists users whose username or email matches search, newest first.
+func (r *userRepositoryImpl) FindPage(search string, after *cursor.Cursor, limit int) ([]*entities.User, error) {
+	args := []interface{}{fmt.Sprintf("%%%s%%", search), limit}
+
+	query := `
+		SELECT
+			id,
+			username,
+			email,
+			COALESCE(avatar, '') AS avatar,
+			is_private,
+			role,
+			suspended_at,
+			created_at
+		FROM users
+		WHERE (username ILIKE $1 OR email ILIKE $1)
+	`
+
+	if after != nil {
+		query += ` AND (created_at, id) < ($3::timestamp, $4)`
+		args = append(args, after.CreatedAt, after.ID)
+	}
+
+	query += `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	users := make([]*entities.User, 0)
+
+	if err := r.db.Select(&users, query, args...); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *userRepositoryImpl) UpdateRole(userID uuid.UUID, role string) error {
+	query := `
+		UPDATE users
+		SET role = $1,
+			updated_at = NOW()
+		WHERE id = $2;
+	`
+	_, err := r.db.Exec(query, role, userID)
+	return err
+}
+
+func (r *userRepositoryImpl) Suspend(userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET suspended_at = NOW()
+		WHERE id = $1 AND suspended_at IS NULL;
+	`
+	_, err := r.db.Exec(query, userID)
+	return err
+}
+
+func (r *userRepositoryImpl) Unsuspend(userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET suspended_at = NULL
+		WHERE id = $1;
+	`
+	_, err := r.db.Exec(query, userID)
+	return err
+}
+
+// FindAccountStatus returns the current role of the user and whether they are
+// suspended. It returns sql.ErrNoRows when the user does not exist.
+func (r *userRepositoryImpl) FindAccountStatus(userID uuid.UUID) (string, bool, error) {
+	var status struct {
+		Role      string `db:"role"`
+		Suspended bool   `db:"suspended"`
+	}
+	query := `
+		SELECT role, suspended_at IS NOT NULL AS suspended
+		FROM users
+		WHERE id = $1;
+	`
+	err := r.db.Get(&status, query, userID)
+	return status.Role, status.Suspended, err
+}
+
+func (r *userRepositoryImpl) ToggleFollowRequest(requesterID, targetID uuid.UUID) (string, error) {
+	query := `
+		DELETE FROM follow_requests
+		WHERE requester_id = $1 AND target_id = $2;
+	`
+	result, err := r.db.Exec(query, requesterID, targetID)
+
+	if err != nil {
+		return "", err
+	}
+
+	if deleted, _ := result.RowsAffected(); deleted > 0 {
+		return "request_cancelled", nil
+	}
+
+	query = `
+		INSERT INTO follow_requests (requester_id, target_id)
+		VALUES ($1, $2)
+		ON CONFLICT (requester_id, target_id) DO NOTHING;
+	`
+
+	if _, err := r.db.Exec(query, requesterID, targetID); err != nil {
+		return "", err
+	}
+
+	return "requested", nil
+}
+
+func (r *userRepositoryImpl) CancelFollowRequest(requesterID, targetID uuid.UUID) error {
+	query := `
+		DELETE FROM follow_requests
+		WHERE requester_id = $1 AND target_id = $2;
+	`
+	_, err := r.db.Exec(query, requesterID, targetID)
+	return err
+}
+
+func (r *userRepositoryImpl) FindFollowRequests(targetID uuid.UUID) ([]*entities.FollowRequest, error) {
+	query := `
+		SELECT
+			fr.id,
+			fr.requester_id,
+			fr.target_id,
+			fr.created_at,
+			u.username,
+			COALESCE(u.avatar, '') AS avatar
+		FROM follow_requests fr
+		JOIN users u ON u.id = fr.requester_id
+		WHERE fr.target_id = $1
+		ORDER BY fr.created_at DESC;
+	`
+
+	rows, err := r.db.Queryx(query, targetID)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := make([]*entities.FollowRequest, 0)
+
+	for rows.Next() {
+		req := &entities.FollowRequest{Requester: &entities.User{}}
+
+		if err := rows.Scan(&req.ID, &req.RequesterID, &req.TargetID, &req.CreatedAt, &req.Requester.Username, &req.Requester.Avatar); err != nil {
+			return nil, err
+		}
+
+		req.Requester.ID = req.RequesterID
+		requests = append(requests, req)
+	}
+
+	return requests, rows.Err()
+}
+
+// ApproveFollowRequest turns a pending request addressed to targetID into a
+// follow. It returns sql.ErrNoRows when there is no such request.
+func (r *userRepositoryImpl) ApproveFollowRequest(requestID, targetID uuid.UUID) (err error) {
+	tx, err := r.db.Beginx()
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var requesterID uuid.UUID
+
+	query := `
+		DELETE FROM follow_requests
+		WHERE id = $1 AND target_id = $2
+		RETURNING requester_id;
+	`
+
+	if err = tx.QueryRow(query, requestID, targetID).Scan(&requesterID); err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO user_folows (follower_id, following_id)
+		VALUES ($1, $2)
+		ON CONFLICT (follower_id, following_id) DO NOTHING;
+	`
+
+	if _, err = tx.Exec(query, requesterID, targetID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RejectFollowRequest drops a pending request addressed to targetID. It
+// returns sql.ErrNoRows when there is no such request.
+func (r *userRepositoryImpl) RejectFollowRequest(requestID, targetID uuid.UUID) error {
+	query := `
+		DELETE FROM follow_requests
+		WHERE id = $1 AND target_id = $2;
+	`
+	result, err := r.db.Exec(query, requestID, targetID)
+
+	if err != nil {
+		return err
+	}
+
+	if deleted, _ := result.RowsAffected(); deleted == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *userRepositoryImpl) ApproveAllFollowRequests(targetID uuid.UUID) (err error) {
+	tx, err := r.db.Beginx()
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	query := `
+		INSERT INTO user_folows (follower_id, following_id)
+		SELECT requester_id, target_id
+		FROM follow_requests
+		WHERE target_id = $1
+		ON CONFLICT (follower_id, following_id) DO NOTHING;
+	`
+
+	if _, err = tx.Exec(query, targetID); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(`DELETE FROM follow_requests WHERE target_id = $1`, targetID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FindFollowers lists the users following userID, newest first.
+func (r *userRepositoryImpl) FindFollowers(userID, viewerID uuid.UUID, after *cursor.Cursor, limit int) ([]*entities.FollowUser, error) {
+	return r.findFollowList(`
+		FROM user_folows uf
+		JOIN users u ON u.id = uf.follower_id
+		WHERE uf.following_id = $1
+	`, userID, viewerID, after, limit)
+}
+
+// FindFollowings lists the users userID follows, newest first.
+func (r *userRepositoryImpl) FindFollowings(userID, viewerID uuid.UUID, after *cursor.Cursor, limit int) ([]*entities.FollowUser, error) {
+	return r.findFollowList(`
+		FROM user_folows uf
+		JOIN users u ON u.id = uf.following_id
+		WHERE uf.follower_id = $1
+	`, userID, viewerID, after, limit)
+}
+
+// FindMutuals lists the followers of userID that viewerID also follows.
+func (r *userRepositoryImpl) FindMutuals(userID, viewerID uuid.UUID, after *cursor.Cursor, limit int) ([]*entities.FollowUser, error) {
+	return r.findFollowList(`
+		FROM user_folows uf
+		JOIN users u ON u.id = uf.follower_id
+		WHERE uf.following_id = $1
+			AND EXISTS (
+				SELECT 1 FROM user_folows vf
+				WHERE vf.follower_id = $2 AND vf.following_id = u.id
+			)
+	`, userID, viewerID, after, limit)
+}
+
+// findFollowList runs a follow list query. from must join user_folows as uf
+// with the listed users as u and may use $1 (userID) and $2 (viewerID).
+func (r *userRepositoryImpl) findFollowList(from string, userID, viewerID uuid.UUID, after *cursor.Cursor, limit int) ([]*entities.FollowUser, error) {
+	args := []interface{}{userID, viewerID, limit}
+
+	query := `
+		SELECT
+			uf.id AS follow_id,
+			uf.created_at AS followed_at,
+			u.id,
+			u.username,
+			COALESCE(u.avatar, '') AS avatar,
+			COALESCE(u.bio, '') AS bio,
+			EXISTS (
+				SELECT 1 FROM user_folows v
+				WHERE v.follower_id = $2 AND v.following_id = u.id
+			) AS followed_by_viewer,
+			EXISTS (
+				SELECT 1 FROM user_folows v
+				WHERE v.follower_id = u.id AND v.following_id = $2
+			) AS follows_viewer
+	` + from
+
+	if after != nil {
+		query += ` AND (uf.created_at, uf.id) < ($4::timestamp, $5)`
+		args = append(args, after.CreatedAt, after.ID)
+	}
+
+	query += `
+		ORDER BY uf.created_at DESC, uf.id DESC
+		LIMIT $3
+	`
+
+	rows := make([]followUserRow, 0)
+
+	if err := r.db.Select(&rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	users := make([]*entities.FollowUser, len(rows))
+
+	for i, row := range rows {
+		users[i] = &entities.FollowUser{
+			FollowID:         row.FollowID,
+			FollowedAt:       row.FollowedAt,
+			FollowedByViewer: row.FollowedByViewer,
+			FollowsViewer:    row.FollowsViewer,
+			User: &entities.User{
+				ID:       row.ID,
+				Username: row.Username,
+				Avatar:   row.Avatar,
+				Bio:      row.Bio,
+			},
+		}
+	}
+
+	return users, nil
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import "fmt"
+
+// visibleTo returns a SQL condition that holds when the content written by
+// authorColumn can be seen by the viewer bound at viewerParam: neither of them
+// blocked the other, and the viewer is the author, the author is public, or
+// the viewer follows the author.
+func visibleTo(authorColumn, viewerParam string) string {
+	return fmt.Sprintf(`(
+		(
+			%[1]s = %[2]s
+			OR NOT EXISTS (
+				SELECT 1 FROM users pu
+				WHERE pu.id = %[1]s AND pu.is_private
+			)
+			OR EXISTS (
+				SELECT 1 FROM user_folows pf
+				WHERE pf.follower_id = %[2]s AND pf.following_id = %[1]s
+			)
+		)
+		AND %[3]s
+	)`, authorColumn, viewerParam, notBlocked(authorColumn, viewerParam))
+}
+
+// notBlocked returns a SQL condition that holds when neither user has blocked
+// the other.
+func notBlocked(userColumn, otherColumn string) string {
+	return fmt.Sprintf(`NOT EXISTS (
+		SELECT 1 FROM user_blocks ub
+		WHERE (ub.blocker_id = %[1]s AND ub.blocked_id = %[2]s)
+			OR (ub.blocker_id = %[2]s AND ub.blocked_id = %[1]s)
+	)`, userColumn, otherColumn)
+}
+
+// notMuted returns a SQL condition that holds when the viewer has not muted
+// the user in userColumn.
+func notMuted(userColumn, viewerParam string) string {
+	return fmt.Sprintf(`NOT EXISTS (
+		SELECT 1 FROM user_mutes um
+		WHERE um.muter_id = %[2]s AND um.muted_id = %[1]s
+	)`, userColumn, viewerParam)
+}
+
+// published returns a SQL condition that holds when the feed aliased as alias
+// has been published. Drafts and scheduled feeds are only listed to their
+// author through the drafts queries.
+func published(alias string) string {
+	return fmt.Sprintf(`%s.status = 'published'`, alias)
+}
+
+// notHidden returns a SQL condition that holds when the feed or comment
+// aliased as alias is not hidden by moderation. Temporary hides lapse once
+// hidden_until has passed.
+func notHidden(alias string) string {
+	return fmt.Sprintf(`NOT (
+		%[1]s.is_hidden
+		AND (%[1]s.hidden_until IS NULL OR %[1]s.hidden_until > NOW())
+	)`, alias)
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/davidafdal/post-app/pkg/cursor"
+	"github.com/davidafdal/post-app/pkg/rbac"
+	"github.com/google/uuid"
+)
+
+type AdminService interface {
+	GetUsers(ctx context.Context, search, after string, limit int) (*dto.UserListResponse, error)
+	ChangeRole(ctx context.Context, req *dto.ChangeRoleRequest) error
+	SuspendUser(ctx context.Context, actorID uuid.UUID, actorRole string, userID uuid.UUID) error
+	UnsuspendUser(ctx context.Context, actorID uuid.UUID, actorRole string, userID uuid.UUID) error
+}
+
+type adminServiceImpl struct {
+	userRepo   repositories.UserRepository
+	reportRepo repositories.ReportRepository
+}
+
+func NewAdminService(userRepo repositories.UserRepository, reportRepo repositories.ReportRepository) AdminService {
+	return &adminServiceImpl{
+		userRepo:   userRepo,
+		reportRepo: reportRepo,
+	}
+}
+
+func (s *adminServiceImpl) GetUsers(ctx context.Context, search, after string, limit int) (*dto.UserListResponse, error) {
+	afterCursor, err := cursor.Decode(after)
+
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.FindPage(search, afterCursor, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	listResponse := &dto.UserListResponse{
+		Items: make([]*dto.UserResponse, len(users)),
+	}
+
+	for i, v := range users {
+		listResponse.Items[i] = &dto.UserResponse{
+			ID:          v.ID.String(),
+			Username:    v.Username,
+			Email:       v.Email,
+			Avatar:      v.Avatar,
+			IsPrivate:   v.IsPrivate,
+			Role:        v.Role,
+			SuspendedAt: v.SuspendedAt,
+			CreatedAt:   v.CreatedAt,
+		}
+	}
+
+	if len(users) == limit {
+		last := users[len(users)-1]
+		listResponse.NextCursor = cursor.Encode(cursor.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	return listResponse, nil
+}
+
+// ChangeRole sets the role of a user. Like suspensions, it only applies to
+// accounts below the actor's role, and no one can grant a role above their
+// own.
+func (s *adminServiceImpl) ChangeRole(ctx context.Context, req *dto.ChangeRoleRequest) error {
+	if !rbac.IsValidRole(req.Role) {
+		return ErrInvalidRole
+	}
+
+	if err := s.checkOutranks(req.ActorID, req.ActorRole, req.UserID); err != nil {
+		return err
+	}
+
+	if rbac.Outranks(req.Role, req.ActorRole) {
+		return ErrForbidden
+	}
+
+	if err := s.userRepo.UpdateRole(req.UserID, req.Role); err != nil {
+		return err
+	}
+
+	return s.audit(ctx, req.ActorID, entities.ModerationActionChangeRole, req.UserID, req.Role)
+}
+
+func (s *adminServiceImpl) SuspendUser(ctx context.Context, actorID uuid.UUID, actorRole string, userID uuid.UUID) error {
+	if err := s.checkOutranks(actorID, actorRole, userID); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Suspend(userID); err != nil {
+		return err
+	}
+
+	return s.audit(ctx, actorID, entities.ModerationActionSuspendUser, userID, "")
+}
+
+func (s *adminServiceImpl) UnsuspendUser(ctx context.Context, actorID uuid.UUID, actorRole string, userID uuid.UUID) error {
+	if err := s.checkOutranks(actorID, actorRole, userID); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Unsuspend(userID); err != nil {
+		return err
+	}
+
+	return s.audit(ctx, actorID, entities.ModerationActionUnsuspend, userID, "")
+}
+
+// checkOutranks makes sure staff can only act on accounts below their own
+// role, so moderators cannot suspend each other or an admin.
+func (s *adminServiceImpl) checkOutranks(actorID uuid.UUID, actorRole string, userID uuid.UUID) error {
+	if actorID == userID {
+		return ErrSelfAction
+	}
+
+	user, err := findUser(s.userRepo, userID)
+
+	if err != nil {
+		return err
+	}
+
+	if !rbac.Outranks(actorRole, user.Role) {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+func (s *adminServiceImpl) audit(ctx context.Context, actorID uuid.UUID, action string, userID uuid.UUID, note string) error {
+	return s.reportRepo.CreateAuditLog(ctx, &entities.AuditLog{
+		ModeratorID: &actorID,
+		Action:      action,
+		TargetType:  entities.ReportTargetUser,
+		TargetID:    userID,
+		Note:        note,
+	})
+}
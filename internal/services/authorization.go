@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// authorizeFeedView makes sure the feed exists and the viewer may see it and
+// its comments.
+func authorizeFeedView(ctx context.Context, feedRepo repositories.FeedRepository, feedID, viewerID uuid.UUID) error {
+	visible, err := feedRepo.IsVisible(ctx, feedID, viewerID)
+
+	if errors.Is(err, repositories.ErrFeedNotFound) {
+		return ErrFeedNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !visible {
+		return ErrPrivateAccount
+	}
+
+	return nil
+}
+
+// authorizeFeedOwner makes sure the feed exists and was written by userID.
+func authorizeFeedOwner(ctx context.Context, feedRepo repositories.FeedRepository, feedID, userID uuid.UUID) error {
+	ownerID, err := feedRepo.FindOwnerID(ctx, feedID)
+
+	if errors.Is(err, repositories.ErrFeedNotFound) {
+		return ErrFeedNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if ownerID != userID {
+		return ErrNotOwner
+	}
+
+	return nil
+}
+
+// authorizeCommentView returns the comment once the viewer is allowed to see
+// the feed it belongs to.
+func authorizeCommentView(ctx context.Context, commentRepo repositories.CommentRepository, feedRepo repositories.FeedRepository, commentID, viewerID uuid.UUID) (*entities.Comment, error) {
+	comment, err := commentRepo.FindByID(ctx, commentID)
+
+	if errors.Is(err, repositories.ErrCommentNotFound) {
+		return nil, ErrCommentNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeFeedView(ctx, feedRepo, comment.FeedID, viewerID); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+func findUser(userRepo repositories.UserRepository, userID uuid.UUID) (*entities.User, error) {
+	user, err := userRepo.FindByID(userID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+
+	return user, err
+}
+
+func findUserByUsername(userRepo repositories.UserRepository, username string) (*entities.User, error) {
+	user, err := userRepo.FindByUsername(username)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+
+	return user, err
+}
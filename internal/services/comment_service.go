@@ -2,98 +2,431 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/davidafdal/post-app/internal/dto"
 	"github.com/davidafdal/post-app/internal/entities"
 	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/davidafdal/post-app/pkg/contentfilter"
+	"github.com/davidafdal/post-app/pkg/cursor"
+	"github.com/davidafdal/post-app/pkg/textparser"
 	"github.com/google/uuid"
 )
 
 type CommentService interface {
-	CreateCommentReplies(ctx context.Context, req *dto.CreateReplyCommentRequest) error
-	GetTopLevelComment(ctx context.Context, feedID uuid.UUID) ([]*dto.CommentResponse, error)
-	GetRepliedComment(ctx context.Context, commentID uuid.UUID) ([]*dto.CommentResponse, error)
-	CreateComment(ctx context.Context, req *dto.CreateCommentRequest) error
+	CreateCommentReplies(ctx context.Context, req *dto.CreateReplyCommentRequest) (*dto.CommentResponse, error)
+	GetTopLevelComment(ctx context.Context, feedID, viewerID uuid.UUID, sort, after string, limit int) (*dto.CommentListResponse, error)
+	GetRepliedComment(ctx context.Context, commentID, viewerID uuid.UUID, after string, limit int) (*dto.CommentListResponse, error)
+	CreateComment(ctx context.Context, req *dto.CreateCommentRequest) (*dto.CommentResponse, error)
+	UpdateComment(ctx context.Context, req *dto.UpdateCommentRequest) (*dto.CommentResponse, error)
+	DeleteComment(ctx context.Context, commentID, userID uuid.UUID) error
+	PinComment(ctx context.Context, feedID, commentID, userID uuid.UUID) (string, error)
+	LikeComment(ctx context.Context, commentID, userID uuid.UUID) (string, error)
 }
 
+// deletedCommentText replaces the text of deleted comments that are still
+// shown because they have replies.
+const deletedCommentText = "[deleted]"
+
+// replyPreviewLimit is how many of the first replies are embedded under each
+// top-level comment.
+const replyPreviewLimit = 3
+
 type commentServiceImpl struct {
-	commentRepo repositories.CommentRepository
+	commentRepo      repositories.CommentRepository
+	feedRepo         repositories.FeedRepository
+	notificationRepo repositories.NotificationRepository
+	moderator        ContentModerator
 }
 
-func NewCommentService(commentRepo repositories.CommentRepository) CommentService {
+func NewCommentService(commentRepo repositories.CommentRepository, feedRepo repositories.FeedRepository, notificationRepo repositories.NotificationRepository, moderator ContentModerator) CommentService {
 	return &commentServiceImpl{
-		commentRepo: commentRepo,
+		commentRepo:      commentRepo,
+		feedRepo:         feedRepo,
+		notificationRepo: notificationRepo,
+		moderator:        moderator,
 	}
 }
 
-func (s *commentServiceImpl) CreateComment(ctx context.Context, req *dto.CreateCommentRequest) error {
+func (s *commentServiceImpl) CreateComment(ctx context.Context, req *dto.CreateCommentRequest) (*dto.CommentResponse, error) {
+	if err := authorizeFeedView(ctx, s.feedRepo, req.FeedID, req.SenderID); err != nil {
+		return nil, err
+	}
+
+	verdict, err := s.moderator.Screen(req.Comment)
+
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := textparser.Parse(req.Comment)
 
 	comment := &entities.Comment{
-		FeedID:  req.FeedID,
-		UserID:  req.SenderID,
-		Comment: req.Comment,
+		FeedID:   req.FeedID,
+		UserID:   req.SenderID,
+		Comment:  req.Comment,
+		IsHidden: verdict.Action == contentfilter.Review,
+		Hashtags: textparser.Hashtags(parsed),
+		Mentions: textparser.Mentions(parsed),
 	}
 
-	_, err := s.commentRepo.Create(ctx, comment)
+	createdComment, err := s.commentRepo.Create(ctx, comment)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	if err := s.afterWrite(ctx, createdComment, verdict); err != nil {
+		return nil, err
+	}
+
+	return s.toCommentResponse(createdComment), nil
 }
 
-func (s *commentServiceImpl) CreateCommentReplies(ctx context.Context, req *dto.CreateReplyCommentRequest) error {
+// CreateCommentReplies replies to a comment. The reply always belongs to the
+// feed of its parent; a feed_id sent by the client must match it.
+func (s *commentServiceImpl) CreateCommentReplies(ctx context.Context, req *dto.CreateReplyCommentRequest) (*dto.CommentResponse, error) {
+	parent, err := authorizeCommentView(ctx, s.commentRepo, s.feedRepo, req.CommentID, req.SenderID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if parent.DeletedAt != nil || (req.FeedID != uuid.Nil && req.FeedID != parent.FeedID) {
+		return nil, ErrCommentNotFound
+	}
+
+	verdict, err := s.moderator.Screen(req.Comment)
+
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := textparser.Parse(req.Comment)
+
 	commentData := &entities.Comment{
-		FeedID:   req.FeedID,
+		FeedID:   parent.FeedID,
 		ParentID: req.CommentID,
 		UserID:   req.SenderID,
 		Comment:  req.Comment,
+		IsHidden: verdict.Action == contentfilter.Review,
+		Hashtags: textparser.Hashtags(parsed),
+		Mentions: textparser.Mentions(parsed),
+	}
+	createdComment, err := s.commentRepo.CreateReply(ctx, commentData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.afterWrite(ctx, createdComment, verdict); err != nil {
+		return nil, err
+	}
+
+	return s.toCommentResponse(createdComment), nil
+}
+
+// afterWrite queues held comments for review, and notifies mentions of the
+// ones that were published.
+func (s *commentServiceImpl) afterWrite(ctx context.Context, comment *entities.Comment, verdict contentfilter.Verdict) error {
+	if comment.IsHidden {
+		return s.moderator.Hold(ctx, entities.ReportTargetComment, comment.ID, verdict)
+	}
+
+	if len(comment.MentionedUsers) == 0 {
+		return nil
+	}
+
+	return notifyMentions(ctx, s.notificationRepo, entities.NotificationCommentMention, comment.UserID, comment.MentionedUsers, &comment.FeedID, &comment.ID)
+}
+
+// UpdateComment lets the author rewrite their comment. Edited text goes
+// through the content filter again.
+func (s *commentServiceImpl) UpdateComment(ctx context.Context, req *dto.UpdateCommentRequest) (*dto.CommentResponse, error) {
+	comment, err := authorizeCommentView(ctx, s.commentRepo, s.feedRepo, req.CommentID, req.UserID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.DeletedAt != nil {
+		return nil, ErrCommentNotFound
+	}
+
+	if comment.UserID != req.UserID {
+		return nil, ErrNotOwner
+	}
+
+	verdict, err := s.moderator.Screen(req.Comment)
+
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := textparser.Parse(req.Comment)
+
+	comment.Comment = req.Comment
+	comment.Hashtags = textparser.Hashtags(parsed)
+	comment.Mentions = textparser.Mentions(parsed)
+
+	updatedComment, err := s.commentRepo.Update(ctx, comment)
+
+	if errors.Is(err, repositories.ErrCommentNotFound) {
+		return nil, ErrCommentNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	updatedComment.IsHidden = verdict.Action == contentfilter.Review
+
+	if err := s.afterWrite(ctx, updatedComment, verdict); err != nil {
+		return nil, err
+	}
+
+	return s.toCommentResponse(updatedComment), nil
+}
+
+// DeleteComment soft-deletes a comment. Both its author and the owner of the
+// feed it was left on may delete it.
+func (s *commentServiceImpl) DeleteComment(ctx context.Context, commentID, userID uuid.UUID) error {
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+
+	if errors.Is(err, repositories.ErrCommentNotFound) {
+		return ErrCommentNotFound
 	}
-	_, err := s.commentRepo.CreateReply(ctx, commentData)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if comment.DeletedAt != nil {
+		return ErrCommentNotFound
+	}
+
+	if comment.UserID != userID {
+		if err := authorizeFeedOwner(ctx, s.feedRepo, comment.FeedID, userID); err != nil {
+			return err
+		}
+	}
+
+	err = s.commentRepo.SoftDelete(ctx, commentID)
+
+	if errors.Is(err, repositories.ErrCommentNotFound) {
+		return ErrCommentNotFound
+	}
+
+	return err
+}
+
+// PinComment pins a top-level comment to the top of its feed, or unpins it
+// when it is already pinned. Only the feed owner can pin, and a feed has at
+// most one pinned comment.
+func (s *commentServiceImpl) PinComment(ctx context.Context, feedID, commentID, userID uuid.UUID) (string, error) {
+	if err := authorizeFeedOwner(ctx, s.feedRepo, feedID, userID); err != nil {
+		return "", err
+	}
+
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+
+	if errors.Is(err, repositories.ErrCommentNotFound) {
+		return "", ErrCommentNotFound
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if comment.FeedID != feedID || comment.ParentID != uuid.Nil || comment.DeletedAt != nil {
+		return "", ErrCommentNotFound
+	}
+
+	return s.commentRepo.TogglePin(ctx, feedID, commentID)
+}
+
+// LikeComment likes the comment, or removes the like when the user already
+// liked it.
+func (s *commentServiceImpl) LikeComment(ctx context.Context, commentID, userID uuid.UUID) (string, error) {
+	comment, err := authorizeCommentView(ctx, s.commentRepo, s.feedRepo, commentID, userID)
+
+	if err != nil {
+		return "", err
+	}
+
+	if comment.DeletedAt != nil {
+		return "", ErrCommentNotFound
+	}
+
+	return s.commentRepo.ToggleLiked(ctx, commentID, userID)
 }
 
-func (s *commentServiceImpl) GetTopLevelComment(ctx context.Context, feedID uuid.UUID) ([]*dto.CommentResponse, error) {
-	comments, err := s.commentRepo.FindTopComment(ctx, feedID)
+// GetTopLevelComment lists a page of the top-level comments of a feed, each
+// with a preview of its first replies. sort is one of newest (the default),
+// oldest or top. The pinned comment leads the first page.
+func (s *commentServiceImpl) GetTopLevelComment(ctx context.Context, feedID, viewerID uuid.UUID, sort, after string, limit int) (*dto.CommentListResponse, error) {
+	switch sort {
+	case "":
+		sort = entities.CommentSortNewest
+	case entities.CommentSortNewest, entities.CommentSortOldest, entities.CommentSortTop:
+	default:
+		return nil, ErrInvalidCommentSort
+	}
+
+	afterCursor, err := cursor.Decode(after)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeFeedView(ctx, s.feedRepo, feedID, viewerID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.FindTopComment(ctx, feedID, viewerID, sort, afterCursor, limit)
 
 	if err != nil {
 		return nil, err
 	}
 
-	commentsResponse := make([]*dto.CommentResponse, len(comments))
+	listResponse := &dto.CommentListResponse{}
+
+	if len(comments) == limit {
+		last := comments[len(comments)-1]
+		next := cursor.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		if sort == entities.CommentSortTop {
+			next.Score = int64(last.LikeCount)
+		}
+		listResponse.NextCursor = cursor.Encode(next)
+	}
+
+	if afterCursor == nil {
+		pinned, err := s.commentRepo.FindPinnedComment(ctx, feedID, viewerID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if pinned != nil {
+			comments = append([]*entities.Comment{pinned}, comments...)
+		}
+	}
+
+	previews, err := s.replyPreviews(ctx, comments, viewerID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	listResponse.Items = make([]*dto.CommentResponse, len(comments))
 
 	for i, v := range comments {
-		commentsResponse[i] = s.toCommentResponse(v)
+		listResponse.Items[i] = s.toCommentResponse(v)
+		listResponse.Items[i].ReplyPreview = previews[v.ID]
 	}
 
-	return commentsResponse, nil
+	return listResponse, nil
 }
 
-func (s *commentServiceImpl) GetRepliedComment(ctx context.Context, commentID uuid.UUID) ([]*dto.CommentResponse, error) {
-	replycomments, err := s.commentRepo.FindRepliesComment(ctx, commentID)
+// replyPreviews loads the first replies of the comments that have any, keyed
+// by their parent.
+func (s *commentServiceImpl) replyPreviews(ctx context.Context, comments []*entities.Comment, viewerID uuid.UUID) (map[uuid.UUID][]*dto.CommentResponse, error) {
+	parentIDs := make([]uuid.UUID, 0, len(comments))
+
+	for _, v := range comments {
+		if v.ReplyCout > 0 {
+			parentIDs = append(parentIDs, v.ID)
+		}
+	}
+
+	previews := make(map[uuid.UUID][]*dto.CommentResponse, len(parentIDs))
+
+	if len(parentIDs) == 0 {
+		return previews, nil
+	}
+
+	replies, err := s.commentRepo.FindReplyPreviews(ctx, parentIDs, viewerID, replyPreviewLimit)
 
 	if err != nil {
 		return nil, err
 	}
 
-	responseComment := make([]*dto.CommentResponse, len(replycomments))
+	for _, v := range replies {
+		previews[v.ParentID] = append(previews[v.ParentID], s.toCommentResponse(v))
+	}
+
+	return previews, nil
+}
 
-	for i, v := range replycomments {
-		responseComment[i] = s.toCommentResponse(v)
+// GetRepliedComment lists a page of the replies to a comment, oldest first.
+func (s *commentServiceImpl) GetRepliedComment(ctx context.Context, commentID, viewerID uuid.UUID, after string, limit int) (*dto.CommentListResponse, error) {
+	afterCursor, err := cursor.Decode(after)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := authorizeCommentView(ctx, s.commentRepo, s.feedRepo, commentID, viewerID); err != nil {
+		return nil, err
+	}
+
+	replies, err := s.commentRepo.FindRepliesComment(ctx, commentID, viewerID, afterCursor, limit)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return responseComment, nil
+	listResponse := &dto.CommentListResponse{
+		Items: make([]*dto.CommentResponse, len(replies)),
+	}
+
+	for i, v := range replies {
+		listResponse.Items[i] = s.toCommentResponse(v)
+	}
+
+	if len(replies) == limit {
+		last := replies[len(replies)-1]
+		listResponse.NextCursor = cursor.Encode(cursor.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	return listResponse, nil
 }
 
 func (r *commentServiceImpl) toCommentResponse(comment *entities.Comment) *dto.CommentResponse {
+	if comment.DeletedAt != nil {
+		return &dto.CommentResponse{
+			ID:         comment.ID,
+			Comment:    deletedCommentText,
+			Entities:   []*dto.TextEntityResponse{},
+			ReplyCount: comment.ReplyCout,
+			LikeCount:  comment.LikeCount,
+			IsDeleted:  true,
+			CreatedAt:  comment.CreatedAt,
+		}
+	}
+
+	var userResponse *dto.UserResponse
+
+	if comment.User != nil {
+		userResponse = &dto.UserResponse{
+			ID:       comment.User.ID.String(),
+			Username: comment.User.Username,
+			Avatar:   comment.User.Avatar,
+		}
+	}
 
 	return &dto.CommentResponse{
-		ID: comment.ID,
+		ID:            comment.ID,
+		Comment:       comment.Comment,
+		User:          userResponse,
+		Entities:      toTextEntitiesResponse(comment.Comment),
+		ReplyCount:    comment.ReplyCout,
+		LikeCount:     comment.LikeCount,
+		LikedByMe:     comment.LikedByMe,
+		HeldForReview: comment.IsHidden,
+		IsPinned:      comment.IsPinned,
+		IsEdited:      comment.EditedAt != nil,
+		EditedAt:      comment.EditedAt,
+		CreatedAt:     comment.CreatedAt,
 	}
 }
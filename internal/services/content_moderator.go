@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/davidafdal/post-app/pkg/contentfilter"
+	"github.com/davidafdal/post-app/pkg/imageproc"
+	"github.com/davidafdal/post-app/pkg/mediacheck"
+	"github.com/google/uuid"
+)
+
+// ContentModerator screens captions, comments and media before they are
+// stored.
+type ContentModerator interface {
+	// Screen returns ErrContentRejected when the text must not be posted. A
+	// verdict with the Review action means the content has to be held.
+	Screen(text string) (contentfilter.Verdict, error)
+	// ScreenMedia returns ErrMediaBlocked when a file is content moderators
+	// removed before: the same bytes or, for JPEG and PNG images, a picture
+	// whose perceptual hash is close to a removed one.
+	ScreenMedia(ctx context.Context, files []mediacheck.File) error
+	// Hold hides the content and queues it for moderators.
+	Hold(ctx context.Context, targetType string, targetID uuid.UUID, verdict contentfilter.Verdict) error
+}
+
+type contentModeratorImpl struct {
+	filter          contentfilter.Filter
+	reportRepo      repositories.ReportRepository
+	mediaRepo       repositories.MediaRepository
+	maxHashDistance int
+}
+
+// NewContentModerator creates a ContentModerator. Images whose perceptual
+// hash differs from a removed one in at most maxHashDistance bits are
+// blocked as copies of it.
+func NewContentModerator(filter contentfilter.Filter, reportRepo repositories.ReportRepository, mediaRepo repositories.MediaRepository, maxHashDistance int) ContentModerator {
+	return &contentModeratorImpl{
+		filter:          filter,
+		reportRepo:      reportRepo,
+		mediaRepo:       mediaRepo,
+		maxHashDistance: maxHashDistance,
+	}
+}
+
+func (m *contentModeratorImpl) Screen(text string) (contentfilter.Verdict, error) {
+	verdict := m.filter.Check(text)
+
+	if verdict.Action == contentfilter.Reject {
+		return verdict, ErrContentRejected
+	}
+
+	return verdict, nil
+}
+
+func (m *contentModeratorImpl) ScreenMedia(ctx context.Context, files []mediacheck.File) error {
+	for _, file := range files {
+		blocked, err := m.isBlocked(ctx, file)
+
+		if err != nil {
+			return err
+		}
+
+		if blocked {
+			return ErrMediaBlocked
+		}
+	}
+
+	return nil
+}
+
+func (m *contentModeratorImpl) isBlocked(ctx context.Context, f mediacheck.File) (bool, error) {
+	file, err := f.Open()
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	digest := sha256.New()
+	if _, err := io.Copy(digest, file); err != nil {
+		return false, err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	contentType, err := mediacheck.DetectType(file)
+	if err != nil {
+		return false, err
+	}
+
+	var phash *uint64
+
+	if imageproc.CanProcess(contentType) {
+		// An image that cannot be decoded is still matched by its digest.
+		if hash, err := imageproc.HashImage(file); err == nil {
+			phash = &hash
+		}
+	}
+
+	return m.mediaRepo.IsBlocked(ctx, hex.EncodeToString(digest.Sum(nil)), phash, m.maxHashDistance)
+}
+
+func (m *contentModeratorImpl) Hold(ctx context.Context, targetType string, targetID uuid.UUID, verdict contentfilter.Verdict) error {
+	if _, err := m.reportRepo.HideContent(ctx, targetType, targetID, nil); err != nil {
+		return err
+	}
+
+	rules := make([]string, len(verdict.Matches))
+
+	for i, match := range verdict.Matches {
+		rules[i] = match.Rule
+	}
+
+	report, err := m.reportRepo.CreateSystemReport(ctx, &entities.Report{
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     entities.ReportReasonContentFilter,
+		Note:       strings.Join(rules, ", "),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return m.reportRepo.CreateAuditLog(ctx, &entities.AuditLog{
+		Action:     entities.ModerationActionFilterHold,
+		ReportID:   &report.ID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Note:       report.Note,
+	})
+}
@@ -0,0 +1,59 @@
+package services
+
+import "errors"
+
+// NotFoundError is returned when a resource does not exist, or exists but must
+// not be revealed to the caller.
+type NotFoundError struct {
+	Resource string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Resource + " not found"
+}
+
+// ForbiddenError is returned when the caller may not access or change a
+// resource.
+type ForbiddenError struct {
+	Reason string
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Reason
+}
+
+var (
+	ErrFeedNotFound          = &NotFoundError{Resource: "feed"}
+	ErrCommentNotFound       = &NotFoundError{Resource: "comment"}
+	ErrUserNotFound          = &NotFoundError{Resource: "user"}
+	ErrFollowRequestNotFound = &NotFoundError{Resource: "follow request"}
+	ErrUploadNotFound        = &NotFoundError{Resource: "upload"}
+	ErrMediaNotFound         = &NotFoundError{Resource: "media"}
+
+	ErrPrivateAccount   = &ForbiddenError{Reason: "this account is private"}
+	ErrUserBlocked      = &ForbiddenError{Reason: "you cannot interact with this user"}
+	ErrAccountSuspended = &ForbiddenError{Reason: "this account has been suspended"}
+	ErrForbidden        = &ForbiddenError{Reason: "you are not allowed to do this"}
+	ErrNotOwner         = &ForbiddenError{Reason: "you are not the owner of this resource"}
+)
+
+var (
+	ErrSelfAction              = errors.New("you cannot do this to yourself")
+	ErrInvalidReportStatus     = errors.New("invalid report status")
+	ErrReportResolved          = errors.New("report has already been resolved")
+	ErrInvalidModerationAction = errors.New("this action cannot be applied to the reported content")
+	ErrInvalidRole             = errors.New("invalid role")
+	ErrInvalidCommentSort      = errors.New("invalid comment sort")
+	ErrInvalidReaction         = errors.New("invalid reaction type")
+	ErrContentRejected         = errors.New("content contains words or links that are not allowed")
+	ErrMediaBlocked            = errors.New("media was removed by moderators and cannot be posted again")
+	ErrInvalidUploadLength     = errors.New("invalid upload length")
+	ErrUploadTooLarge          = errors.New("upload is too large")
+	ErrUploadOffsetMismatch    = errors.New("upload offset does not match")
+	ErrUploadIncomplete        = errors.New("upload is not complete")
+	ErrTooManyMediaDetails     = errors.New("there are more alt texts or captions than media")
+	ErrInvalidMediaOrder       = errors.New("media ids must list every media of the feed once")
+	ErrInvalidPublishAt        = errors.New("scheduled feeds need a publish_at in the future")
+	ErrFeedPublished           = errors.New("feed is already published")
+	ErrInvalidSeenFeeds        = errors.New("feed_ids must hold between 1 and 100 feeds")
+)
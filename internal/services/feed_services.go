@@ -3,72 +3,245 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"mime/multipart"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/davidafdal/post-app/internal/dto"
 	"github.com/davidafdal/post-app/internal/entities"
 	"github.com/davidafdal/post-app/internal/events"
 	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/davidafdal/post-app/pkg/contentfilter"
+	"github.com/davidafdal/post-app/pkg/cursor"
+	"github.com/davidafdal/post-app/pkg/mediacheck"
 	"github.com/davidafdal/post-app/pkg/rabbitmq"
+	"github.com/davidafdal/post-app/pkg/textparser"
 	"github.com/davidafdal/post-app/pkg/upload"
 	"github.com/google/uuid"
 )
 
+// maxSeenFeeds is how many feeds MarkFeedsSeen takes at once.
+const maxSeenFeeds = 100
+
 type FeedService interface {
 	CreateFeed(ctx context.Context, req *dto.CreateFeedRequest, files []*multipart.FileHeader) (*dto.FeedResponse, error)
+	UpdateFeedCaption(ctx context.Context, req *dto.UpdateFeedRequest) (*dto.FeedResponse, error)
+	GetDrafts(ctx context.Context, userID uuid.UUID) ([]*dto.FeedResponse, error)
+	UpdateDraft(ctx context.Context, req *dto.UpdateDraftRequest) (*dto.FeedResponse, error)
 	GetFeeds(ctx context.Context, userID uuid.UUID) ([]*dto.FeedResponse, error)
-	LikeFeed(feedID, userID uuid.UUID) (string, error)
+	GetFeedsByHashtag(ctx context.Context, tag string, viewerID uuid.UUID) ([]*dto.FeedResponse, error)
+	GetUserFeeds(ctx context.Context, username string, viewerID uuid.UUID) ([]*dto.FeedResponse, error)
+	GetExploreFeeds(ctx context.Context, userID uuid.UUID, limit int) ([]*dto.FeedResponse, error)
+	MarkFeedsSeen(ctx context.Context, req *dto.MarkFeedsSeenRequest) error
+	LikeFeed(ctx context.Context, feedID, userID uuid.UUID) (string, error)
+	RepostFeed(ctx context.Context, feedID, userID uuid.UUID) (string, error)
+	ReactToFeed(ctx context.Context, req *dto.ReactFeedRequest) (string, error)
+	RemoveReaction(ctx context.Context, feedID, userID uuid.UUID) error
+	GetFeedReactions(ctx context.Context, feedID, viewerID uuid.UUID, reactionType, after string, limit int) (*dto.ReactionListResponse, error)
+	GetFeedMedias(ctx context.Context, feedID, viewerID uuid.UUID) ([]*dto.MediaResponse, error)
+	UpdateMedia(ctx context.Context, req *dto.UpdateMediaRequest) (*dto.MediaResponse, error)
+	ReorderMedias(ctx context.Context, req *dto.ReorderMediasRequest) ([]*dto.MediaResponse, error)
 }
 
 type feedServicesImpl struct {
-	feedRepo      repositories.FeedRepository
-	uploadUseCase upload.UploadUseCase
-	msgBroker     rabbitmq.MessageBroker
+	feedRepo         repositories.FeedRepository
+	userRepo         repositories.UserRepository
+	notificationRepo repositories.NotificationRepository
+	moderator        ContentModerator
+	reactionTypes    map[string]bool
+	mediaValidator   *mediacheck.Validator
+	uploadUseCase    upload.UploadUseCase
+	resumable        upload.ResumableStore
+	msgBroker        rabbitmq.MessageBroker
 }
 
-func NewFeedService(feedRepo repositories.FeedRepository, uploadUseCase upload.UploadUseCase, msgBroker rabbitmq.MessageBroker) FeedService {
+func NewFeedService(feedRepo repositories.FeedRepository, userRepo repositories.UserRepository, notificationRepo repositories.NotificationRepository, moderator ContentModerator, reactionTypes []string, mediaValidator *mediacheck.Validator, uploadUseCase upload.UploadUseCase, resumable upload.ResumableStore, msgBroker rabbitmq.MessageBroker) FeedService {
+	allowedReactions := make(map[string]bool, len(reactionTypes))
+	for _, reactionType := range reactionTypes {
+		allowedReactions[strings.ToLower(strings.TrimSpace(reactionType))] = true
+	}
+
 	return &feedServicesImpl{
-		feedRepo:      feedRepo,
-		uploadUseCase: uploadUseCase,
-		msgBroker:     msgBroker,
+		feedRepo:         feedRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		moderator:        moderator,
+		reactionTypes:    allowedReactions,
+		mediaValidator:   mediaValidator,
+		uploadUseCase:    uploadUseCase,
+		resumable:        resumable,
+		msgBroker:        msgBroker,
 	}
 }
 
+// CreateFeed stores a feed and queues its media for upload. The media are the
+// files sent with the request followed by the completed resumable uploads in
+// req.UploadIDs. They are checked against the upload limits before anything
+// is stored, and each gets a pending media the worker fills in once it is
+// processed. Uploads are claimed before the feed is stored and put back if it
+// cannot be; an upload listed twice is used once. Captions caught by the content filter are either rejected or
+// stored hidden until a moderator reviews them; mentions in held feeds are not
+// notified. Drafts and scheduled feeds notify their mentions once published.
+// A quote post needs the quoted feed to be visible to its author.
 func (s *feedServicesImpl) CreateFeed(ctx context.Context, req *dto.CreateFeedRequest, files []*multipart.FileHeader) (*dto.FeedResponse, error) {
+	sources := mediacheck.Multipart(files)
+	uploads := make([]*upload.Upload, 0, len(req.UploadIDs))
+	seen := make(map[string]bool, len(req.UploadIDs))
+
+	for _, uploadID := range req.UploadIDs {
+		if seen[uploadID] {
+			continue
+		}
+		seen[uploadID] = true
+
+		found, err := findOwnUpload(s.resumable, uploadID, req.UserID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !found.Complete() {
+			return nil, ErrUploadIncomplete
+		}
+
+		sources = append(sources, found)
+		uploads = append(uploads, found)
+	}
+
+	medias, err := s.mediaValidator.CheckFiles(sources, mediacheck.KindImage, mediacheck.KindVideo)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.moderator.ScreenMedia(ctx, sources); err != nil {
+		return nil, err
+	}
+
+	if len(req.AltTexts) > len(sources) || len(req.MediaCaptions) > len(sources) {
+		return nil, ErrTooManyMediaDetails
+	}
+
+	status, publishAt, err := resolveSchedule(req.Status, req.PublishAt, entities.FeedPublished)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if req.QuotedFeedID != nil {
+		if err := authorizeFeedView(ctx, s.feedRepo, *req.QuotedFeedID, req.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Media captions are shown with the feed, so they are screened with its
+	// caption.
+	verdict, err := s.moderator.Screen(strings.Join(append([]string{req.Caption}, req.MediaCaptions...), "\n"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := textparser.Parse(req.Caption)
 
 	feed := &entities.Feed{
-		Caption: req.Caption,
-		UserID:  req.UserID,
+		Caption:      req.Caption,
+		UserID:       req.UserID,
+		IsHidden:     verdict.Action == contentfilter.Review,
+		Status:       status,
+		PublishAt:    publishAt,
+		QuotedFeedID: req.QuotedFeedID,
+		Hashtags:     textparser.Hashtags(parsed),
+		Mentions:     textparser.Mentions(parsed),
 	}
 
+	tempPaths, release, err := s.claimMedia(files, uploads)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Once published, the temp files belong to the upload worker.
+	published := false
+	defer func() {
+		if !published {
+			release()
+		}
+	}()
+
 	createdFeed, err := s.feedRepo.Create(ctx, feed)
 
 	if err != nil {
 		return nil, err
 	}
 
-	contentData := make([]events.ContentData, len(files))
+	if createdFeed.IsHidden {
+		if err := s.moderator.Hold(ctx, entities.ReportTargetFeed, createdFeed.ID, verdict); err != nil {
+			return nil, err
+		}
+	} else if createdFeed.Status == entities.FeedPublished {
+		if len(createdFeed.MentionedUsers) > 0 {
+			if err := notifyMentions(ctx, s.notificationRepo, entities.NotificationFeedMention, req.UserID, createdFeed.MentionedUsers, &createdFeed.ID, nil); err != nil {
+				return nil, err
+			}
+		}
 
-	for i, fileHeader := range files {
-		tempPath, err := s.uploadUseCase.SaveTempFile(fileHeader, "/app/uploads")
-		if err != nil {
+		if err := notifyQuoted(ctx, s.feedRepo, s.notificationRepo, createdFeed); err != nil {
 			return nil, err
 		}
+	}
+
+	contentData := make([]events.ContentData, len(sources))
+
+	for i := range sources {
+		media := &entities.FeedMedia{
+			FeedId:     createdFeed.ID,
+			Type:       string(medias[i].Kind),
+			Status:     entities.MediaPending,
+			DurationMs: int(medias[i].Duration.Milliseconds()),
+			Width:      medias[i].Width,
+			Height:     medias[i].Height,
+			Position:   i,
+		}
+
+		if i < len(req.AltTexts) {
+			media.AltText = req.AltTexts[i]
+		}
+		if i < len(req.MediaCaptions) {
+			media.Caption = req.MediaCaptions[i]
+		}
+
+		if err := s.feedRepo.AddMedia(ctx, media); err != nil {
+			return nil, err
+		}
+
 		contentData[i] = events.ContentData{
-			FilePath: tempPath,
-			FileType: "Image",
+			MediaID:  media.ID.String(),
+			FilePath: tempPaths[i],
+			FileType: media.Type,
 		}
+		createdFeed.Medias = append(createdFeed.Medias, media)
 	}
 
-	payload := events.UploadPayload{
-		FeedID:  createdFeed.ID.String(),
-		Content: contentData,
+	if len(contentData) > 0 {
+		payload := events.UploadPayload{
+			FeedID:  createdFeed.ID.String(),
+			Content: contentData,
+		}
+
+		body, _ := json.Marshal(payload)
+
+		if err := s.msgBroker.Publish("", events.Queue, string(events.UploadFeedMedias), body); err != nil {
+			return nil, err
+		}
 	}
 
-	body, _ := json.Marshal(payload)
+	published = true
 
-	if err := s.msgBroker.Publish("", "events", string(events.UploadFeedMedias), body); err != nil {
+	if err := loadQuotedFeeds(ctx, s.feedRepo, req.UserID, []*entities.Feed{createdFeed}); err != nil {
 		return nil, err
 	}
 
@@ -78,7 +251,219 @@ func (s *feedServicesImpl) CreateFeed(ctx context.Context, req *dto.CreateFeedRe
 		}
 	}()
 
-	return s.toFeedResponse(createdFeed), nil
+	return toFeedResponse(createdFeed), nil
+}
+
+// claimMedia moves the files and uploads of a new feed to temp files of their
+// own, in that order. release deletes the saved files and puts the uploads
+// back; it is called already when claiming fails.
+func (s *feedServicesImpl) claimMedia(files []*multipart.FileHeader, uploads []*upload.Upload) (tempPaths []string, release func(), err error) {
+	claimed := make([]*upload.Upload, 0, len(uploads))
+
+	release = func() {
+		for i, tempPath := range tempPaths {
+			if i < len(files) {
+				os.Remove(tempPath)
+			} else {
+				s.resumable.Release(claimed[i-len(files)], tempPath)
+			}
+		}
+	}
+
+	for _, file := range files {
+		tempPath, err := s.uploadUseCase.SaveTempFile(file, upload.TempDir)
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		tempPaths = append(tempPaths, tempPath)
+	}
+
+	for _, found := range uploads {
+		tempPath, err := s.resumable.Claim(found.ID)
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		tempPaths = append(tempPaths, tempPath)
+		claimed = append(claimed, found)
+	}
+
+	return tempPaths, release, nil
+}
+
+func (s *feedServicesImpl) UpdateFeedCaption(ctx context.Context, req *dto.UpdateFeedRequest) (*dto.FeedResponse, error) {
+	if err := authorizeFeedOwner(ctx, s.feedRepo, req.FeedID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	verdict, err := s.moderator.Screen(req.Caption)
+
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := textparser.Parse(req.Caption)
+
+	feed := &entities.Feed{
+		ID:       req.FeedID,
+		UserID:   req.UserID,
+		Caption:  req.Caption,
+		Hashtags: textparser.Hashtags(parsed),
+		Mentions: textparser.Mentions(parsed),
+	}
+
+	updatedFeed, err := s.feedRepo.UpdateCaption(ctx, feed)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if verdict.Action == contentfilter.Review {
+		if err := s.moderator.Hold(ctx, entities.ReportTargetFeed, updatedFeed.ID, verdict); err != nil {
+			return nil, err
+		}
+		updatedFeed.IsHidden = true
+	} else if updatedFeed.Status == entities.FeedPublished && len(updatedFeed.MentionedUsers) > 0 {
+		if err := notifyMentions(ctx, s.notificationRepo, entities.NotificationFeedMention, req.UserID, updatedFeed.MentionedUsers, &updatedFeed.ID, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return toFeedResponse(updatedFeed), nil
+}
+
+// GetDrafts lists the drafts and scheduled feeds of the user, newest first.
+func (s *feedServicesImpl) GetDrafts(ctx context.Context, userID uuid.UUID) ([]*dto.FeedResponse, error) {
+	feeds, err := s.feedRepo.GetDrafts(ctx, userID, 100)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadQuotedFeeds(ctx, s.feedRepo, userID, feeds); err != nil {
+		return nil, err
+	}
+
+	feedResponse := make([]*dto.FeedResponse, 0, len(feeds))
+
+	for _, feed := range feeds {
+		if feed.Medias, err = s.feedRepo.FindMedias(ctx, feed.ID); err != nil {
+			return nil, err
+		}
+		feedResponse = append(feedResponse, toFeedResponse(feed))
+	}
+
+	return feedResponse, nil
+}
+
+// UpdateDraft rewrites a draft or scheduled feed, possibly publishing it. A
+// feed published by it notifies everyone it mentions, as it was never shown
+// before.
+func (s *feedServicesImpl) UpdateDraft(ctx context.Context, req *dto.UpdateDraftRequest) (*dto.FeedResponse, error) {
+	if err := authorizeFeedOwner(ctx, s.feedRepo, req.FeedID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	status, publishAt, err := resolveSchedule(req.Status, req.PublishAt, entities.FeedDraft)
+
+	if err != nil {
+		return nil, err
+	}
+
+	verdict, err := s.moderator.Screen(req.Caption)
+
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := textparser.Parse(req.Caption)
+
+	feed := &entities.Feed{
+		ID:        req.FeedID,
+		UserID:    req.UserID,
+		Caption:   req.Caption,
+		Status:    status,
+		PublishAt: publishAt,
+		Hashtags:  textparser.Hashtags(parsed),
+		Mentions:  textparser.Mentions(parsed),
+	}
+
+	updatedFeed, err := s.feedRepo.UpdateDraft(ctx, feed)
+
+	if errors.Is(err, repositories.ErrFeedNotFound) {
+		return nil, ErrFeedPublished
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if verdict.Action == contentfilter.Review {
+		if err := s.moderator.Hold(ctx, entities.ReportTargetFeed, updatedFeed.ID, verdict); err != nil {
+			return nil, err
+		}
+		updatedFeed.IsHidden = true
+	} else if updatedFeed.Status == entities.FeedPublished && !updatedFeed.IsHidden {
+		if err := notifyFeedPublished(ctx, s.feedRepo, s.notificationRepo, updatedFeed); err != nil {
+			return nil, err
+		}
+	}
+
+	if updatedFeed.Medias, err = s.feedRepo.FindMedias(ctx, updatedFeed.ID); err != nil {
+		return nil, err
+	}
+
+	if err := loadQuotedFeeds(ctx, s.feedRepo, req.UserID, []*entities.Feed{updatedFeed}); err != nil {
+		return nil, err
+	}
+
+	return toFeedResponse(updatedFeed), nil
+}
+
+// resolveSchedule checks the status and publish time asked for a feed.
+// Without a status, a feed with a publish time is scheduled and one without
+// gets fallback. Only scheduled feeds keep their publish time, which must be
+// in the future. It is stored in UTC, as publish_at has no time zone.
+func resolveSchedule(status string, publishAt *time.Time, fallback string) (string, *time.Time, error) {
+	if status == "" {
+		status = fallback
+		if publishAt != nil {
+			status = entities.FeedScheduled
+		}
+	}
+
+	if status != entities.FeedScheduled {
+		return status, nil, nil
+	}
+
+	if publishAt == nil || !publishAt.After(time.Now()) {
+		return "", nil, ErrInvalidPublishAt
+	}
+
+	utc := publishAt.UTC()
+
+	return status, &utc, nil
+}
+
+func (s *feedServicesImpl) GetFeedsByHashtag(ctx context.Context, tag string, viewerID uuid.UUID) ([]*dto.FeedResponse, error) {
+	feeds, err := s.feedRepo.GetFeedsByHashtag(ctx, strings.ToLower(strings.TrimPrefix(tag, "#")), viewerID, 100)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadQuotedFeeds(ctx, s.feedRepo, viewerID, feeds); err != nil {
+		return nil, err
+	}
+
+	feedResponse := make([]*dto.FeedResponse, 0, len(feeds))
+
+	for _, feed := range feeds {
+		feedResponse = append(feedResponse, toFeedResponse(feed))
+	}
+
+	return feedResponse, nil
 }
 
 func (s *feedServicesImpl) GetFeeds(ctx context.Context, userID uuid.UUID) ([]*dto.FeedResponse, error) {
@@ -88,49 +473,483 @@ func (s *feedServicesImpl) GetFeeds(ctx context.Context, userID uuid.UUID) ([]*d
 		return nil, err
 	}
 
+	if err := loadQuotedFeeds(ctx, s.feedRepo, userID, feeds); err != nil {
+		return nil, err
+	}
+
 	feedResponse := make([]*dto.FeedResponse, 0, len(feeds))
 
 	for _, feed := range feeds {
-		feedResponse = append(feedResponse, s.toFeedResponse(feed))
+		feedResponse = append(feedResponse, toFeedResponse(feed))
 	}
 
 	return feedResponse, nil
 }
 
+func (s *feedServicesImpl) GetUserFeeds(ctx context.Context, username string, viewerID uuid.UUID) ([]*dto.FeedResponse, error) {
+	user, err := findUserByUsername(s.userRepo, username)
+
+	if err != nil {
+		return nil, err
+	}
+
+	canView, err := s.userRepo.CanView(viewerID, user.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !canView {
+		return nil, ErrPrivateAccount
+	}
+
+	feeds, err := s.feedRepo.GetFeedsByUser(ctx, user.ID, 100)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadQuotedFeeds(ctx, s.feedRepo, viewerID, feeds); err != nil {
+		return nil, err
+	}
+
+	feedResponse := make([]*dto.FeedResponse, 0, len(feeds))
+
+	for _, feed := range feeds {
+		feedResponse = append(feedResponse, toFeedResponse(feed))
+	}
+
+	return feedResponse, nil
+}
+
+// GetExploreFeeds returns up to limit feeds in ranking order, leaving out the
+// user's own feeds and those of accounts the user follows, has a block with or
+// has muted. Clients page through explore by marking the feeds they showed as
+// seen, which drops them from later calls.
+func (s *feedServicesImpl) GetExploreFeeds(ctx context.Context, userID uuid.UUID, limit int) ([]*dto.FeedResponse, error) {
+	excludedAuthorIDs := []uuid.UUID{userID}
+
+	for _, find := range []func(uuid.UUID) ([]uuid.UUID, error){s.userRepo.FindFollowingIDs, s.userRepo.FindBlockedIDs, s.userRepo.FindMutedIDs} {
+		ids, err := find(userID)
+		if err != nil {
+			return nil, err
+		}
+		excludedAuthorIDs = append(excludedAuthorIDs, ids...)
+	}
+
+	feedIDs, err := s.feedRepo.GetExploreFeedIDs(ctx, userID, excludedAuthorIDs, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	feeds, err := s.feedRepo.GetFeedsByIDs(ctx, feedIDs, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadQuotedFeeds(ctx, s.feedRepo, userID, feeds); err != nil {
+		return nil, err
+	}
+
+	feedResponse := make([]*dto.FeedResponse, 0, len(feeds))
+
+	for _, feed := range feeds {
+		feedResponse = append(feedResponse, toFeedResponse(feed))
+	}
+
+	return feedResponse, nil
+}
+
+// MarkFeedsSeen records the feeds the user was shown in explore. It takes at
+// most maxSeenFeeds distinct feeds at once.
+func (s *feedServicesImpl) MarkFeedsSeen(ctx context.Context, req *dto.MarkFeedsSeenRequest) error {
+	feedIDs := make([]uuid.UUID, 0, len(req.FeedIDs))
+	seen := make(map[uuid.UUID]bool, len(req.FeedIDs))
+
+	for _, feedID := range req.FeedIDs {
+		if feedID == uuid.Nil || seen[feedID] {
+			continue
+		}
+		seen[feedID] = true
+		feedIDs = append(feedIDs, feedID)
+	}
+
+	if len(feedIDs) == 0 || len(feedIDs) > maxSeenFeeds {
+		return ErrInvalidSeenFeeds
+	}
+
+	return s.feedRepo.MarkSeen(ctx, req.UserID, feedIDs)
+}
+
 // func (s *feedServicesImpl) GetFeedByID(ctx context.Context, feedId uuid.UUID) (*dto.FeedResponse, error) {
 
 // }
 
-func (s *feedServicesImpl) LikeFeed(feedID, userID uuid.UUID) (string, error) {
-	status, err := s.feedRepo.ToggleLiked(feedID, userID)
+// LikeFeed toggles the like reaction. Any other reaction the user left is
+// replaced by a like.
+func (s *feedServicesImpl) LikeFeed(ctx context.Context, feedID, userID uuid.UUID) (string, error) {
+	status, err := s.ReactToFeed(ctx, &dto.ReactFeedRequest{
+		FeedID: feedID,
+		Type:   entities.ReactionLike,
+		UserID: userID,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if status == "removed" {
+		return "unliked", nil
+	}
+
+	return "liked", nil
+}
+
+// RepostFeed toggles the user's repost of a feed, shown in the home feed of
+// their followers. The author is notified of new reposts.
+func (s *feedServicesImpl) RepostFeed(ctx context.Context, feedID, userID uuid.UUID) (string, error) {
+	if err := authorizeFeedView(ctx, s.feedRepo, feedID, userID); err != nil {
+		return "", err
+	}
+
+	removed, err := s.feedRepo.RemoveRepost(ctx, feedID, userID)
+
+	if err != nil {
+		return "", err
+	}
+
+	if removed {
+		return "unreposted", nil
+	}
+
+	added, err := s.feedRepo.AddRepost(ctx, feedID, userID)
+
 	if err != nil {
 		return "", err
 	}
 
-	return status, nil
+	if added {
+		ownerID, err := s.feedRepo.FindOwnerID(ctx, feedID)
+
+		if err != nil {
+			return "", err
+		}
+
+		if err := notifyMentions(ctx, s.notificationRepo, entities.NotificationRepost, userID, []uuid.UUID{ownerID}, &feedID, nil); err != nil {
+			return "", err
+		}
+	}
+
+	return "reposted", nil
+}
+
+// ReactToFeed sets the user's reaction to the feed. A user has at most one
+// reaction per feed: sending a different type changes it and sending the same
+// type again removes it.
+func (s *feedServicesImpl) ReactToFeed(ctx context.Context, req *dto.ReactFeedRequest) (string, error) {
+	reactionType := strings.ToLower(req.Type)
+
+	if !s.reactionTypes[reactionType] {
+		return "", ErrInvalidReaction
+	}
+
+	if err := authorizeFeedView(ctx, s.feedRepo, req.FeedID, req.UserID); err != nil {
+		return "", err
+	}
+
+	current, err := s.feedRepo.FindReaction(ctx, req.FeedID, req.UserID)
+
+	if err != nil {
+		return "", err
+	}
+
+	if current == reactionType {
+		if err := s.feedRepo.RemoveReaction(ctx, req.FeedID, req.UserID); err != nil {
+			return "", err
+		}
+		return "removed", nil
+	}
+
+	if err := s.feedRepo.SetReaction(ctx, req.FeedID, req.UserID, reactionType); err != nil {
+		return "", err
+	}
+
+	if current != "" {
+		return "changed", nil
+	}
+
+	return "reacted", nil
+}
+
+func (s *feedServicesImpl) RemoveReaction(ctx context.Context, feedID, userID uuid.UUID) error {
+	if err := authorizeFeedView(ctx, s.feedRepo, feedID, userID); err != nil {
+		return err
+	}
+
+	return s.feedRepo.RemoveReaction(ctx, feedID, userID)
+}
+
+// GetFeedReactions lists who reacted to a feed, optionally only with one
+// reaction type.
+func (s *feedServicesImpl) GetFeedReactions(ctx context.Context, feedID, viewerID uuid.UUID, reactionType, after string, limit int) (*dto.ReactionListResponse, error) {
+	reactionType = strings.ToLower(reactionType)
+
+	if reactionType != "" && !s.reactionTypes[reactionType] {
+		return nil, ErrInvalidReaction
+	}
+
+	afterCursor, err := cursor.Decode(after)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeFeedView(ctx, s.feedRepo, feedID, viewerID); err != nil {
+		return nil, err
+	}
+
+	reactions, err := s.feedRepo.FindReactions(ctx, feedID, viewerID, reactionType, afterCursor, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	listResponse := &dto.ReactionListResponse{
+		Items: make([]*dto.ReactionResponse, len(reactions)),
+	}
+
+	for i, v := range reactions {
+		listResponse.Items[i] = &dto.ReactionResponse{
+			User: &dto.UserResponse{
+				ID:       v.User.ID.String(),
+				Username: v.User.Username,
+				Avatar:   v.User.Avatar,
+			},
+			Type:      v.Type,
+			CreatedAt: v.CreatedAt,
+		}
+	}
+
+	if len(reactions) == limit {
+		last := reactions[len(reactions)-1]
+		listResponse.NextCursor = cursor.Encode(cursor.Cursor{CreatedAt: last.CreatedAt, ID: last.UserID})
+	}
+
+	return listResponse, nil
+}
+
+// GetFeedMedias lists the media of a feed with their processing status, so
+// clients can poll until they are ready.
+func (s *feedServicesImpl) GetFeedMedias(ctx context.Context, feedID, viewerID uuid.UUID) ([]*dto.MediaResponse, error) {
+	if err := authorizeFeedView(ctx, s.feedRepo, feedID, viewerID); err != nil {
+		return nil, err
+	}
+
+	medias, err := s.feedRepo.FindMedias(ctx, feedID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mediasResponse := make([]*dto.MediaResponse, len(medias))
+
+	for i, media := range medias {
+		mediasResponse[i] = toMediaResponse(media)
+	}
+
+	return mediasResponse, nil
+}
+
+// UpdateMedia changes the alt text and caption of a media. A caption held by
+// the content filter hides the whole feed, as it is shown with it.
+func (s *feedServicesImpl) UpdateMedia(ctx context.Context, req *dto.UpdateMediaRequest) (*dto.MediaResponse, error) {
+	if err := authorizeFeedOwner(ctx, s.feedRepo, req.FeedID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	verdict, err := s.moderator.Screen(req.Caption)
+
+	if err != nil {
+		return nil, err
+	}
+
+	media, err := s.feedRepo.UpdateMediaDetails(ctx, &entities.FeedMedia{
+		ID:      req.MediaID,
+		FeedId:  req.FeedID,
+		AltText: req.AltText,
+		Caption: req.Caption,
+	})
+
+	if errors.Is(err, repositories.ErrMediaNotFound) {
+		return nil, ErrMediaNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if verdict.Action == contentfilter.Review {
+		if err := s.moderator.Hold(ctx, entities.ReportTargetFeed, req.FeedID, verdict); err != nil {
+			return nil, err
+		}
+	}
+
+	return toMediaResponse(media), nil
+}
+
+// ReorderMedias sets the order of the media of a feed. req.MediaIDs must hold
+// every media of the feed exactly once.
+func (s *feedServicesImpl) ReorderMedias(ctx context.Context, req *dto.ReorderMediasRequest) ([]*dto.MediaResponse, error) {
+	if err := authorizeFeedOwner(ctx, s.feedRepo, req.FeedID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	medias, err := s.feedRepo.FindMedias(ctx, req.FeedID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.MediaIDs) != len(medias) {
+		return nil, ErrInvalidMediaOrder
+	}
+
+	byID := make(map[uuid.UUID]*entities.FeedMedia, len(medias))
+
+	for _, media := range medias {
+		byID[media.ID] = media
+	}
+
+	mediasResponse := make([]*dto.MediaResponse, len(req.MediaIDs))
+
+	for i, mediaID := range req.MediaIDs {
+		media, ok := byID[mediaID]
+
+		if !ok {
+			return nil, ErrInvalidMediaOrder
+		}
+
+		delete(byID, mediaID)
+		media.Position = i
+		mediasResponse[i] = toMediaResponse(media)
+	}
+
+	if err := s.feedRepo.ReorderMedias(ctx, req.FeedID, req.MediaIDs); err != nil {
+		return nil, err
+	}
+
+	return mediasResponse, nil
 }
 
-func (s *feedServicesImpl) toFeedResponse(feed *entities.Feed) *dto.FeedResponse {
+// loadQuotedFeeds fills in the feeds quoted by feeds, as the viewer sees them.
+// Quoted feeds that were deleted or that the viewer may not see are left out.
+func loadQuotedFeeds(ctx context.Context, feedRepo repositories.FeedRepository, viewerID uuid.UUID, feeds []*entities.Feed) error {
+	quotedIDs := make([]uuid.UUID, 0)
+
+	for _, feed := range feeds {
+		if feed.QuotedFeedID != nil {
+			quotedIDs = append(quotedIDs, *feed.QuotedFeedID)
+		}
+	}
+
+	if len(quotedIDs) == 0 {
+		return nil
+	}
+
+	quoted, err := feedRepo.GetFeedsByIDs(ctx, quotedIDs, viewerID)
+
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[uuid.UUID]*entities.Feed, len(quoted))
+
+	for _, feed := range quoted {
+		byID[feed.ID] = feed
+	}
+
+	for _, feed := range feeds {
+		if feed.QuotedFeedID != nil {
+			feed.QuotedFeed = byID[*feed.QuotedFeedID]
+		}
+	}
+
+	return nil
+}
+
+func toMediaResponse(media *entities.FeedMedia) *dto.MediaResponse {
+	return &dto.MediaResponse{
+		ID:         media.ID,
+		Url:        media.Url,
+		Type:       media.Type,
+		Status:     media.Status,
+		Position:   media.Position,
+		AltText:    media.AltText,
+		Caption:    media.Caption,
+		Variants:   media.Variants,
+		Blurhash:   media.Blurhash,
+		PosterUrl:  media.PosterUrl,
+		DurationMs: media.DurationMs,
+		Width:      media.Width,
+		Height:     media.Height,
+	}
+}
+
+func toFeedResponse(feed *entities.Feed) *dto.FeedResponse {
 	mediasResponse := make([]*dto.MediaResponse, 0, len(feed.Medias))
 
 	for _, media := range feed.Medias {
-		mediasResponse = append(mediasResponse, &dto.MediaResponse{
-			Url:  media.Url,
-			Type: media.Type,
-		})
+		mediasResponse = append(mediasResponse, toMediaResponse(media))
+	}
+
+	var userResponse *dto.UserResponse
+
+	if feed.User != nil {
+		userResponse = &dto.UserResponse{
+			Username: feed.User.Username,
+			Avatar:   feed.User.Avatar,
+		}
 	}
 
-	userResponse := &dto.UserResponse{
-		Username: feed.User.Username,
-		Avatar:   feed.User.Avatar,
+	reactions := feed.Reactions
+	if reactions == nil {
+		reactions = map[string]int{}
 	}
 
-	return &dto.FeedResponse{
-		ID:       feed.ID,
-		Caption:  feed.Caption,
-		Medias:   mediasResponse,
-		User:     userResponse,
-		Likes:    feed.Likes,
-		Comments: feed.Comments,
+	feedResponse := &dto.FeedResponse{
+		ID:            feed.ID,
+		Caption:       feed.Caption,
+		Entities:      toTextEntitiesResponse(feed.Caption),
+		Medias:        mediasResponse,
+		User:          userResponse,
+		ReactionCount: feed.ReactionCount,
+		Reactions:     reactions,
+		Reposts:       feed.Reposts,
+		Comments:      feed.Comments,
+		HeldForReview: feed.IsHidden,
+		Status:        feed.Status,
+		PublishAt:     feed.PublishAt,
+		QuotedFeedID:  feed.QuotedFeedID,
 	}
+
+	if feed.RepostedBy != nil {
+		feedResponse.RepostedBy = &dto.UserResponse{
+			Username: feed.RepostedBy.Username,
+			Avatar:   feed.RepostedBy.Avatar,
+		}
+	}
+
+	if feed.QuotedFeed != nil {
+		// Quotes are embedded one level deep; a quoted quote only carries
+		// the id of the feed it quotes.
+		quoted := toFeedResponse(feed.QuotedFeed)
+		quoted.QuoteUnavailable = false
+		feedResponse.QuotedFeed = quoted
+	} else if feed.QuotedFeedID != nil {
+		feedResponse.QuoteUnavailable = true
+	}
+
+	return feedResponse
 }
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/davidafdal/post-app/pkg/storage"
+	"github.com/davidafdal/post-app/pkg/upload"
+)
+
+// storedPrefixes are the storage folders the janitor reconciles. Everything
+// stored under them is referenced from feed_media or users.avatar.
+var storedPrefixes = []string{"feeds/", avatarPrefix + "/"}
+
+// JanitorOptions bounds what the janitor may remove. Objects younger than
+// OrphanMinAge are kept, as the worker stores files before it records them.
+type JanitorOptions struct {
+	TempDir        string
+	TempFileMaxAge time.Duration
+	OrphanMinAge   time.Duration
+	DryRun         bool
+}
+
+// JanitorService removes leftovers: temp files nothing picked up, media blobs
+// no feed media uses any more, and stored objects no feed media, blob or
+// avatar points to.
+type JanitorService interface {
+	Clean(ctx context.Context) (*dto.CleanupReport, error)
+}
+
+type janitorServiceImpl struct {
+	mediaRepo repositories.MediaRepository
+	store     storage.Storage
+	options   JanitorOptions
+	now       func() time.Time
+}
+
+func NewJanitorService(mediaRepo repositories.MediaRepository, store storage.Storage, options JanitorOptions) JanitorService {
+	return &janitorServiceImpl{
+		mediaRepo: mediaRepo,
+		store:     store,
+		options:   options,
+		now:       time.Now,
+	}
+}
+
+// Clean removes stale temp files and orphaned objects and logs each one. In
+// dry-run mode nothing is removed and the report lists what would have been.
+func (s *janitorServiceImpl) Clean(ctx context.Context) (*dto.CleanupReport, error) {
+	report := &dto.CleanupReport{DryRun: s.options.DryRun, TempFiles: []string{}, Objects: []string{}}
+
+	tempErr := s.cleanTempFiles(report)
+	blobErr := s.cleanBlobs(ctx, report)
+	objectErr := s.cleanOrphans(ctx, report)
+
+	return report, errors.Join(tempErr, blobErr, objectErr)
+}
+
+func (s *janitorServiceImpl) cleanTempFiles(report *dto.CleanupReport) error {
+	stale, err := upload.StaleFiles(s.options.TempDir, s.now().Add(-s.options.TempFileMaxAge))
+
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, tempPath := range stale {
+		if !s.options.DryRun {
+			if err := os.Remove(tempPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		s.logRemoval("temp file", tempPath)
+		report.TempFiles = append(report.TempFiles, tempPath)
+	}
+
+	return errors.Join(errs...)
+}
+
+// cleanBlobs deletes the blobs whose last media went away at least
+// OrphanMinAge ago, along with their objects.
+func (s *janitorServiceImpl) cleanBlobs(ctx context.Context, report *dto.CleanupReport) error {
+	cutoff := s.now().Add(-s.options.OrphanMinAge)
+
+	blobs, err := s.mediaRepo.FindUnreferencedBlobs(ctx, cutoff)
+
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, blob := range blobs {
+		if !s.options.DryRun {
+			err := s.mediaRepo.DeleteBlob(ctx, blob.ID, cutoff)
+
+			if errors.Is(err, repositories.ErrBlobNotFound) {
+				continue
+			}
+
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		// Objects left behind by a failed delete are no longer referenced, so
+		// the orphan pass retries them.
+		for _, key := range blob.Keys {
+			if !s.options.DryRun {
+				if err := s.store.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+					errs = append(errs, err)
+					continue
+				}
+			}
+
+			s.logRemoval("object", key)
+			report.Objects = append(report.Objects, key)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *janitorServiceImpl) cleanOrphans(ctx context.Context, report *dto.CleanupReport) error {
+	// Nothing is deleted unless the references were loaded, or every object
+	// would look orphaned.
+	urls, err := s.mediaRepo.FindReferencedURLs(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	referenced := make(map[string]bool, len(urls))
+
+	for _, url := range urls {
+		// URLs of another storage, such as ones saved before a migration,
+		// cannot match anything listed here.
+		if key, err := s.store.KeyFromURL(url); err == nil {
+			referenced[key] = true
+		}
+	}
+
+	cutoff := s.now().Add(-s.options.OrphanMinAge)
+
+	var orphans []string
+
+	for _, prefix := range storedPrefixes {
+		err := s.store.List(ctx, prefix, func(object *storage.Object) error {
+			if !referenced[object.Key] && object.ModTime.Before(cutoff) {
+				orphans = append(orphans, object.Key)
+			}
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+
+	for _, key := range orphans {
+		if !s.options.DryRun {
+			if err := s.store.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		s.logRemoval("object", key)
+		report.Objects = append(report.Objects, key)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *janitorServiceImpl) logRemoval(kind, name string) {
+	if s.options.DryRun {
+		log.Printf("janitor: would remove %s %s", kind, name)
+		return
+	}
+	log.Printf("janitor: removed %s %s", kind, name)
+}
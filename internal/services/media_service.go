@@ -0,0 +1,402 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/events"
+	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/davidafdal/post-app/pkg/imageproc"
+	"github.com/davidafdal/post-app/pkg/mediacheck"
+	"github.com/davidafdal/post-app/pkg/socket"
+	"github.com/davidafdal/post-app/pkg/storage"
+	"github.com/davidafdal/post-app/pkg/videoproc"
+	"github.com/google/uuid"
+)
+
+// EventMediaProcessed is pushed to the feed owner when a media is ready or
+// failed.
+const EventMediaProcessed = "media.processed"
+
+// maxPosterOffset is how far into a video the poster frame is taken from.
+const maxPosterOffset = time.Second
+
+// MediaService moves uploaded feed media from the temp files CreateFeed
+// writes into storage. JPEG and PNG images are processed into sized variants
+// without their metadata, videos are transcoded and get a poster frame, and
+// other files are stored as uploaded. Content identical to an earlier upload
+// is not stored again: the media shares the blob of the first one. It runs in
+// the worker, fed by the events queue.
+type MediaService interface {
+	StoreFeedMedias(ctx context.Context, payload *events.UploadPayload) error
+	DeleteFeedMedias(ctx context.Context, payload *events.DeletePayload) error
+}
+
+type mediaServiceImpl struct {
+	feedRepo  repositories.FeedRepository
+	mediaRepo repositories.MediaRepository
+	store     storage.Storage
+	images    *imageproc.Processor
+	videos    videoproc.Processor
+	publisher socket.Publisher
+}
+
+func NewMediaService(feedRepo repositories.FeedRepository, mediaRepo repositories.MediaRepository, store storage.Storage, images *imageproc.Processor, videos videoproc.Processor, publisher socket.Publisher) MediaService {
+	return &mediaServiceImpl{
+		feedRepo:  feedRepo,
+		mediaRepo: mediaRepo,
+		store:     store,
+		images:    images,
+		videos:    videos,
+		publisher: publisher,
+	}
+}
+
+// StoreFeedMedias processes every file of the payload and records the result
+// on its media. A failing file does not stop the others; its media is marked
+// failed and its temp file is kept. The feed owner is told about each media
+// as it finishes.
+func (s *mediaServiceImpl) StoreFeedMedias(ctx context.Context, payload *events.UploadPayload) error {
+	feedID, err := uuid.Parse(payload.FeedID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, content := range payload.Content {
+		media := &entities.FeedMedia{FeedId: feedID, Type: strings.ToLower(content.FileType)}
+
+		if content.MediaID != "" {
+			if media.ID, err = uuid.Parse(content.MediaID); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		if err := s.storeFeedMedia(ctx, media, content.FilePath); err != nil {
+			errs = append(errs, err)
+		}
+
+		s.notifyOwner(ctx, media)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *mediaServiceImpl) storeFeedMedia(ctx context.Context, media *entities.FeedMedia, filePath string) error {
+	if media.ID != uuid.Nil {
+		if err := s.feedRepo.SetMediaStatus(ctx, media.ID, entities.MediaProcessing); err != nil {
+			return err
+		}
+	}
+
+	// Once stored, a blob is only removed by the janitor after no media points
+	// to it, so its objects are kept even if the media cannot be saved.
+	err := s.attachBlob(ctx, media, filePath)
+
+	if err == nil {
+		media.Status = entities.MediaReady
+
+		if media.ID == uuid.Nil {
+			err = s.feedRepo.AddMedia(ctx, media)
+		} else {
+			err = s.feedRepo.UpdateMedia(ctx, media)
+		}
+	}
+
+	if err != nil {
+		media.Status = entities.MediaFailed
+
+		if media.ID != uuid.Nil {
+			if statusErr := s.feedRepo.SetMediaStatus(ctx, media.ID, entities.MediaFailed); statusErr != nil {
+				return errors.Join(err, statusErr)
+			}
+		}
+		return err
+	}
+
+	return os.Remove(filePath)
+}
+
+// attachBlob points media at the blob of the content at filePath. The content
+// is processed and stored only when no identical upload was stored before.
+func (s *mediaServiceImpl) attachBlob(ctx context.Context, media *entities.FeedMedia, filePath string) error {
+	digest, err := fileDigest(filePath)
+	if err != nil {
+		return err
+	}
+
+	blob, err := s.mediaRepo.FindBlob(ctx, digest)
+
+	if errors.Is(err, repositories.ErrBlobNotFound) {
+		blob, err = s.storeBlob(ctx, media, filePath, digest)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	media.BlobID = &blob.ID
+	media.Type = blob.Type
+	media.Url = blob.Url
+	media.Variants = blob.Variants
+	media.Blurhash = blob.Blurhash
+	media.PosterUrl = blob.PosterUrl
+	media.DurationMs = blob.DurationMs
+	media.Width = blob.Width
+	media.Height = blob.Height
+
+	return nil
+}
+
+// storeBlob processes and stores the content at filePath as a new blob.
+func (s *mediaServiceImpl) storeBlob(ctx context.Context, media *entities.FeedMedia, filePath, digest string) (*entities.MediaBlob, error) {
+	blob := &entities.MediaBlob{SHA256: digest, Type: media.Type}
+
+	if err := s.process(ctx, mediaDir(media), blob, filePath); err != nil {
+		return nil, err
+	}
+
+	err := s.mediaRepo.CreateBlob(ctx, blob)
+
+	if errors.Is(err, repositories.ErrBlobExists) {
+		// Another worker stored the same content meanwhile; its blob is used
+		// and this copy dropped.
+		s.deleteKeys(ctx, blob.Keys)
+		return s.mediaRepo.FindBlob(ctx, digest)
+	}
+
+	if err != nil {
+		s.deleteKeys(ctx, blob.Keys)
+		return nil, err
+	}
+
+	return blob, nil
+}
+
+// process stores the file at filePath under dir and fills in blob.
+func (s *mediaServiceImpl) process(ctx context.Context, dir string, blob *entities.MediaBlob, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	contentType, err := mediacheck.DetectType(file)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case imageproc.CanProcess(contentType):
+		return s.storeImage(ctx, dir, blob, file)
+	case strings.HasPrefix(contentType, "video/"):
+		return s.storeVideo(ctx, dir, blob, filePath)
+	case imageproc.CanStripMetadata(contentType):
+		return s.storeOriginal(ctx, dir, blob, file, filepath.Base(filePath), contentType)
+	default:
+		return fmt.Errorf("%w: %s", mediacheck.ErrUnsupportedFormat, contentType)
+	}
+}
+
+// storeImage stores every variant of an image under its own folder, so the
+// variants of one upload share a prefix. The largest variant is the media URL.
+func (s *mediaServiceImpl) storeImage(ctx context.Context, dir string, blob *entities.MediaBlob, file io.Reader) error {
+	result, err := s.images.Process(file)
+	if err != nil {
+		return err
+	}
+
+	blob.Type = entities.MediaImage
+	blob.Variants = make(map[string]string, len(result.Variants))
+	blob.Blurhash = result.Blurhash
+	blob.PHash = &result.Hash
+	blob.Width = result.Width
+	blob.Height = result.Height
+
+	for _, variant := range result.Variants {
+		object, err := s.store.Put(ctx, path.Join(dir, variant.Name+variant.Extension), bytes.NewReader(variant.Data), int64(len(variant.Data)), variant.ContentType)
+		if err != nil {
+			s.deleteKeys(ctx, blob.Keys)
+			return err
+		}
+
+		blob.Keys = append(blob.Keys, object.Key)
+		blob.Variants[variant.Name] = object.URL
+		blob.Url = object.URL
+	}
+
+	return nil
+}
+
+// storeVideo transcodes a video to MP4 and stores it with a poster frame taken
+// from the transcoded file. Duration and size are read back from the output,
+// as transcoding may scale the video. The perceptual hash is the poster's.
+func (s *mediaServiceImpl) storeVideo(ctx context.Context, dir string, blob *entities.MediaBlob, filePath string) error {
+	workDir, err := os.MkdirTemp("", "video-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+
+	videoPath := filepath.Join(workDir, "video.mp4")
+	posterPath := filepath.Join(workDir, "poster.jpg")
+
+	if err := s.videos.Transcode(ctx, filePath, videoPath); err != nil {
+		return err
+	}
+
+	video, err := os.Open(videoPath)
+	if err != nil {
+		return err
+	}
+	defer video.Close()
+
+	stat, err := video.Stat()
+	if err != nil {
+		return err
+	}
+
+	info, err := mediacheck.Inspect(video, stat.Size())
+	if err != nil {
+		return err
+	}
+
+	if err := s.videos.Poster(ctx, videoPath, posterPath, min(maxPosterOffset, info.Duration/2)); err != nil {
+		return err
+	}
+
+	poster, err := os.ReadFile(posterPath)
+	if err != nil {
+		return err
+	}
+
+	// The blurhash of the poster stands in for the video while it loads.
+	if placeholder, err := s.images.Process(bytes.NewReader(poster)); err == nil {
+		blob.Blurhash = placeholder.Blurhash
+		blob.PHash = &placeholder.Hash
+	}
+
+	videoObject, err := s.store.Put(ctx, path.Join(dir, "video.mp4"), video, stat.Size(), "video/mp4")
+	if err != nil {
+		return err
+	}
+
+	posterObject, err := s.store.Put(ctx, path.Join(dir, "poster.jpg"), bytes.NewReader(poster), int64(len(poster)), "image/jpeg")
+	if err != nil {
+		s.deleteKeys(ctx, []string{videoObject.Key})
+		return err
+	}
+
+	blob.Type = entities.MediaVideo
+	blob.Url = videoObject.URL
+	blob.PosterUrl = posterObject.URL
+	blob.DurationMs = int(info.Duration.Milliseconds())
+	blob.Width = info.Width
+	blob.Height = info.Height
+	blob.Keys = []string{videoObject.Key, posterObject.Key}
+
+	return nil
+}
+
+// storeOriginal stores an image that cannot be re-encoded, such as an animated
+// GIF, as uploaded but for its metadata.
+func (s *mediaServiceImpl) storeOriginal(ctx context.Context, dir string, blob *entities.MediaBlob, file io.Reader, filename string, contentType string) error {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+
+	data, err = imageproc.StripMetadata(contentType, data)
+	if err != nil {
+		return err
+	}
+
+	object, err := s.store.Put(ctx, storage.NewKey(dir, filename), bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		return err
+	}
+
+	blob.Url = object.URL
+	blob.Type = strings.SplitN(contentType, "/", 2)[0]
+	blob.Keys = []string{object.Key}
+
+	return nil
+}
+
+// notifyOwner pushes the outcome of processing media to the feed owner.
+// Clients that miss it can still poll the feed media.
+func (s *mediaServiceImpl) notifyOwner(ctx context.Context, media *entities.FeedMedia) {
+	ownerID, err := s.feedRepo.FindOwnerID(ctx, media.FeedId)
+	if err != nil {
+		log.Printf("media %s: find feed owner: %v", media.ID, err)
+		return
+	}
+
+	event := socket.Event{
+		Type: EventMediaProcessed,
+		Data: &dto.MediaProcessedEvent{FeedID: media.FeedId, Media: toMediaResponse(media)},
+	}
+
+	if err := s.publisher.Publish(ctx, ownerID.String(), event); err != nil {
+		log.Printf("media %s: publish %s: %v", media.ID, EventMediaProcessed, err)
+	}
+}
+
+// deleteKeys removes objects stored for a blob that could not be saved.
+func (s *mediaServiceImpl) deleteKeys(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		_ = s.store.Delete(ctx, key)
+	}
+}
+
+func (s *mediaServiceImpl) DeleteFeedMedias(ctx context.Context, payload *events.DeletePayload) error {
+	var errs []error
+
+	for _, key := range payload.Keys {
+		if err := s.store.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// mediaDir is the folder the files of one media are stored under.
+func mediaDir(media *entities.FeedMedia) string {
+	id := media.ID
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
+	return path.Join("feeds", media.FeedId.String(), id.String())
+}
+
+// fileDigest is the hex SHA-256 of the file at filePath.
+func fileDigest(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/google/uuid"
+)
+
+type NotificationService interface {
+	GetNotifications(ctx context.Context, userID uuid.UUID) ([]*dto.NotificationResponse, error)
+	ReadAllNotifications(ctx context.Context, userID uuid.UUID) error
+}
+
+type notificationServiceImpl struct {
+	notificationRepo repositories.NotificationRepository
+	userRepo         repositories.UserRepository
+}
+
+func NewNotificationService(notificationRepo repositories.NotificationRepository, userRepo repositories.UserRepository) NotificationService {
+	return &notificationServiceImpl{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// GetNotifications returns the latest notifications of the user, leaving out
+// those from actors the user has a block with or has muted. They show again
+// once the block or mute is lifted.
+func (s *notificationServiceImpl) GetNotifications(ctx context.Context, userID uuid.UUID) ([]*dto.NotificationResponse, error) {
+	blockedIDs, err := s.userRepo.FindBlockedIDs(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mutedIDs, err := s.userRepo.FindMutedIDs(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	excludedActorIDs := make([]uuid.UUID, 0, len(blockedIDs)+len(mutedIDs))
+	excludedActorIDs = append(excludedActorIDs, blockedIDs...)
+	excludedActorIDs = append(excludedActorIDs, mutedIDs...)
+
+	notifications, err := s.notificationRepo.FindByUser(ctx, userID, excludedActorIDs, 100)
+
+	if err != nil {
+		return nil, err
+	}
+
+	notificationsResponse := make([]*dto.NotificationResponse, len(notifications))
+
+	for i, v := range notifications {
+		notificationsResponse[i] = s.toNotificationResponse(v)
+	}
+
+	return notificationsResponse, nil
+}
+
+func (s *notificationServiceImpl) ReadAllNotifications(ctx context.Context, userID uuid.UUID) error {
+	return s.notificationRepo.MarkAllRead(ctx, userID)
+}
+
+func (s *notificationServiceImpl) toNotificationResponse(notification *entities.Notification) *dto.NotificationResponse {
+	return &dto.NotificationResponse{
+		ID:   notification.ID,
+		Type: notification.Type,
+		Actor: &dto.UserResponse{
+			ID:       notification.Actor.ID.String(),
+			Username: notification.Actor.Username,
+			Avatar:   notification.Actor.Avatar,
+		},
+		FeedID:    notification.FeedID,
+		CommentID: notification.CommentID,
+		IsRead:    notification.IsRead,
+		CreatedAt: notification.CreatedAt,
+	}
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/davidafdal/post-app/pkg/cursor"
+	"github.com/davidafdal/post-app/pkg/rbac"
+)
+
+type ReportService interface {
+	CreateReport(ctx context.Context, req *dto.CreateReportRequest) (*dto.ReportResponse, error)
+	GetReports(ctx context.Context, status, after string, limit int) (*dto.ReportListResponse, error)
+	TakeAction(ctx context.Context, req *dto.ModerationActionRequest) error
+	GetAuditLogs(ctx context.Context, limit int) ([]*dto.AuditLogResponse, error)
+}
+
+type reportServiceImpl struct {
+	reportRepo   repositories.ReportRepository
+	userRepo     repositories.UserRepository
+	mediaRepo    repositories.MediaRepository
+	threshold    int
+	hideDuration time.Duration
+}
+
+// NewReportService creates a ReportService. Content reported by threshold
+// different users is hidden for hideDuration until a moderator reviews it; a
+// threshold of zero disables automatic hiding. The media of feeds moderators
+// hide are blocked from being posted again.
+func NewReportService(reportRepo repositories.ReportRepository, userRepo repositories.UserRepository, mediaRepo repositories.MediaRepository, threshold int, hideDuration time.Duration) ReportService {
+	return &reportServiceImpl{
+		reportRepo:   reportRepo,
+		userRepo:     userRepo,
+		mediaRepo:    mediaRepo,
+		threshold:    threshold,
+		hideDuration: hideDuration,
+	}
+}
+
+func (s *reportServiceImpl) CreateReport(ctx context.Context, req *dto.CreateReportRequest) (*dto.ReportResponse, error) {
+	ownerID, err := s.reportRepo.FindTargetOwner(ctx, req.TargetType, req.TargetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerID == req.ReporterID {
+		return nil, ErrSelfAction
+	}
+
+	report, err := s.reportRepo.Create(ctx, &entities.Report{
+		ReporterID: req.ReporterID,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Reason:     req.Reason,
+		Note:       req.Note,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.autoHide(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return toReportResponse(report), nil
+}
+
+// autoHide temporarily hides the reported content once enough users have
+// reported it.
+func (s *reportServiceImpl) autoHide(ctx context.Context, report *entities.Report) error {
+	if s.threshold <= 0 || report.TargetType == entities.ReportTargetUser {
+		return nil
+	}
+
+	count, err := s.reportRepo.CountOpenReports(ctx, report.TargetType, report.TargetID)
+
+	if err != nil || count < s.threshold {
+		return err
+	}
+
+	until := time.Now().Add(s.hideDuration)
+
+	hidden, err := s.reportRepo.HideContent(ctx, report.TargetType, report.TargetID, &until)
+
+	if err != nil || !hidden {
+		return err
+	}
+
+	return s.reportRepo.CreateAuditLog(ctx, &entities.AuditLog{
+		Action:     entities.ModerationActionAutoHide,
+		ReportID:   &report.ID,
+		TargetType: report.TargetType,
+		TargetID:   report.TargetID,
+	})
+}
+
+func (s *reportServiceImpl) GetReports(ctx context.Context, status, after string, limit int) (*dto.ReportListResponse, error) {
+	if status == "" {
+		status = entities.ReportStatusOpen
+	}
+
+	switch status {
+	case entities.ReportStatusOpen, entities.ReportStatusDismissed, entities.ReportStatusActioned:
+	default:
+		return nil, ErrInvalidReportStatus
+	}
+
+	afterCursor, err := cursor.Decode(after)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := s.reportRepo.FindByStatus(ctx, status, afterCursor, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	listResponse := &dto.ReportListResponse{
+		Items: make([]*dto.ReportResponse, len(reports)),
+	}
+
+	for i, v := range reports {
+		listResponse.Items[i] = toReportResponse(v)
+	}
+
+	if len(reports) == limit {
+		last := reports[len(reports)-1]
+		listResponse.NextCursor = cursor.Encode(cursor.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	return listResponse, nil
+}
+
+// TakeAction applies a moderator decision to an open report. The decision
+// covers every open report on the same target, and is recorded in the audit
+// log. Dismissing only lifts automatic hides, never a moderator's removal.
+func (s *reportServiceImpl) TakeAction(ctx context.Context, req *dto.ModerationActionRequest) error {
+	report, err := s.reportRepo.FindByID(ctx, req.ReportID)
+
+	if err != nil {
+		return err
+	}
+
+	if report.Status != entities.ReportStatusOpen {
+		return ErrReportResolved
+	}
+
+	status := entities.ReportStatusActioned
+
+	switch req.Action {
+	case entities.ModerationActionDismiss:
+		status = entities.ReportStatusDismissed
+		var restored bool
+		restored, err = s.reportRepo.UnhideContent(ctx, report.TargetType, report.TargetID)
+		if err == nil && restored && report.TargetType == entities.ReportTargetFeed {
+			err = s.mediaRepo.UnblockFeedMedia(ctx, report.TargetID)
+		}
+	case entities.ModerationActionHideContent:
+		if report.TargetType == entities.ReportTargetUser {
+			return ErrInvalidModerationAction
+		}
+		err = s.reportRepo.RemoveContent(ctx, report.TargetType, report.TargetID)
+		if err == nil && report.TargetType == entities.ReportTargetFeed {
+			err = s.mediaRepo.BlockFeedMedia(ctx, report.TargetID)
+		}
+	case entities.ModerationActionSuspendUser:
+		err = s.suspendOwner(ctx, report, req.ModeratorRole)
+	default:
+		return ErrInvalidModerationAction
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.reportRepo.ResolveTarget(ctx, report.TargetType, report.TargetID, status, req.ModeratorID); err != nil {
+		return err
+	}
+
+	return s.reportRepo.CreateAuditLog(ctx, &entities.AuditLog{
+		ModeratorID: &req.ModeratorID,
+		Action:      req.Action,
+		ReportID:    &report.ID,
+		TargetType:  report.TargetType,
+		TargetID:    report.TargetID,
+		Note:        req.Note,
+	})
+}
+
+// suspendOwner suspends the author of the reported content, unless the author
+// holds a role at least as high as the moderator's.
+func (s *reportServiceImpl) suspendOwner(ctx context.Context, report *entities.Report, moderatorRole string) error {
+	ownerID, err := s.reportRepo.FindTargetOwner(ctx, report.TargetType, report.TargetID)
+
+	if err != nil {
+		return err
+	}
+
+	owner, err := findUser(s.userRepo, ownerID)
+
+	if err != nil {
+		return err
+	}
+
+	if !rbac.Outranks(moderatorRole, owner.Role) {
+		return ErrForbidden
+	}
+
+	return s.userRepo.Suspend(ownerID)
+}
+
+func (s *reportServiceImpl) GetAuditLogs(ctx context.Context, limit int) ([]*dto.AuditLogResponse, error) {
+	logs, err := s.reportRepo.FindAuditLogs(ctx, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	logsResponse := make([]*dto.AuditLogResponse, len(logs))
+
+	for i, v := range logs {
+		logsResponse[i] = &dto.AuditLogResponse{
+			ID:          v.ID,
+			ModeratorID: v.ModeratorID,
+			Action:      v.Action,
+			ReportID:    v.ReportID,
+			TargetType:  v.TargetType,
+			TargetID:    v.TargetID,
+			Note:        v.Note,
+			CreatedAt:   v.CreatedAt,
+		}
+	}
+
+	return logsResponse, nil
+}
+
+func toReportResponse(report *entities.Report) *dto.ReportResponse {
+	reportResponse := &dto.ReportResponse{
+		ID:          report.ID,
+		TargetType:  report.TargetType,
+		TargetID:    report.TargetID,
+		Reason:      report.Reason,
+		Note:        report.Note,
+		Status:      report.Status,
+		ReportCount: report.ReportCount,
+		ResolvedAt:  report.ResolvedAt,
+		CreatedAt:   report.CreatedAt,
+	}
+
+	if report.Reporter != nil {
+		reportResponse.Reporter = &dto.UserResponse{
+			ID:       report.Reporter.ID.String(),
+			Username: report.Reporter.Username,
+			Avatar:   report.Reporter.Avatar,
+		}
+	}
+
+	return reportResponse
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/google/uuid"
+)
+
+type ScheduleService interface {
+	PublishDueFeeds(ctx context.Context) error
+}
+
+type scheduleServiceImpl struct {
+	feedRepo         repositories.FeedRepository
+	notificationRepo repositories.NotificationRepository
+}
+
+func NewScheduleService(feedRepo repositories.FeedRepository, notificationRepo repositories.NotificationRepository) ScheduleService {
+	return &scheduleServiceImpl{
+		feedRepo:         feedRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// PublishDueFeeds publishes the scheduled feeds whose publish time has passed
+// and notifies their mentions, like a feed posted right away. Feeds held for
+// review are published hidden and stay silent. The feeds are published before
+// anyone is notified and are never due again, so a failed notification does
+// not stop the others.
+func (s *scheduleServiceImpl) PublishDueFeeds(ctx context.Context) error {
+	feeds, err := s.feedRepo.PublishDueFeeds(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, feed := range feeds {
+		if feed.IsHidden {
+			continue
+		}
+
+		if err := notifyFeedPublished(ctx, s.feedRepo, s.notificationRepo, feed); err != nil {
+			errs = append(errs, fmt.Errorf("notify published feed %s: %w", feed.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// notifyFeedPublished notifies everyone mentioned in a feed that has just been
+// published, and the author of the feed it quotes.
+func notifyFeedPublished(ctx context.Context, feedRepo repositories.FeedRepository, notificationRepo repositories.NotificationRepository, feed *entities.Feed) error {
+	mentioned, err := feedRepo.FindMentionedUsers(ctx, feed.ID)
+
+	if err != nil {
+		return err
+	}
+
+	if len(mentioned) > 0 {
+		if err := notifyMentions(ctx, notificationRepo, entities.NotificationFeedMention, feed.UserID, mentioned, &feed.ID, nil); err != nil {
+			return err
+		}
+	}
+
+	return notifyQuoted(ctx, feedRepo, notificationRepo, feed)
+}
+
+// notifyQuoted notifies the author of the feed quoted by feed, if it still
+// exists.
+func notifyQuoted(ctx context.Context, feedRepo repositories.FeedRepository, notificationRepo repositories.NotificationRepository, feed *entities.Feed) error {
+	if feed.QuotedFeedID == nil {
+		return nil
+	}
+
+	ownerID, err := feedRepo.FindOwnerID(ctx, *feed.QuotedFeedID)
+
+	if errors.Is(err, repositories.ErrFeedNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return notifyMentions(ctx, notificationRepo, entities.NotificationQuote, feed.UserID, []uuid.UUID{ownerID}, &feed.ID, nil)
+}
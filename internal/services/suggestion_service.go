@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/google/uuid"
+)
+
+const (
+	suggestionLimit       = 20
+	suggestionMutualLimit = 3
+	suggestionBatchSize   = 500
+)
+
+type SuggestionService interface {
+	GetSuggestions(ctx context.Context, userID uuid.UUID) ([]*dto.SuggestionResponse, error)
+	ComputeSuggestions(ctx context.Context) error
+}
+
+type suggestionServiceImpl struct {
+	suggestionRepo repositories.SuggestionRepository
+	cacheTTL       time.Duration
+}
+
+func NewSuggestionService(suggestionRepo repositories.SuggestionRepository, cacheTTL time.Duration) SuggestionService {
+	return &suggestionServiceImpl{
+		suggestionRepo: suggestionRepo,
+		cacheTTL:       cacheTTL,
+	}
+}
+
+// GetSuggestions serves the list computed by ComputeSuggestions when it is
+// cached, and computes it on the spot otherwise. The user itself and accounts
+// followed or blocked since the list was cached are dropped before responding.
+func (s *suggestionServiceImpl) GetSuggestions(ctx context.Context, userID uuid.UUID) ([]*dto.SuggestionResponse, error) {
+	suggestions, ok, err := s.suggestionRepo.GetCached(ctx, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		suggestions, err = s.computeFor(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	excludedIDs, err := s.suggestionRepo.FindExcludedIDs(ctx, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[uuid.UUID]bool, len(excludedIDs)+1)
+	excluded[userID] = true
+	for _, id := range excludedIDs {
+		excluded[id] = true
+	}
+
+	suggestionsResponse := make([]*dto.SuggestionResponse, 0, len(suggestions))
+
+	for _, suggestion := range suggestions {
+		if excluded[suggestion.User.ID] {
+			continue
+		}
+		suggestionsResponse = append(suggestionsResponse, s.toSuggestionResponse(suggestion))
+	}
+
+	return suggestionsResponse, nil
+}
+
+// ComputeSuggestions refreshes the cached suggestions of every user that
+// follows someone, walking the users in batches.
+func (s *suggestionServiceImpl) ComputeSuggestions(ctx context.Context) error {
+	afterID := uuid.Nil
+
+	for {
+		userIDs, err := s.suggestionRepo.FindUserIDsWithFollowings(ctx, afterID, suggestionBatchSize)
+
+		if err != nil {
+			return err
+		}
+
+		for _, userID := range userIDs {
+			if _, err := s.computeFor(ctx, userID); err != nil {
+				return err
+			}
+		}
+
+		if len(userIDs) < suggestionBatchSize {
+			return nil
+		}
+
+		afterID = userIDs[len(userIDs)-1]
+	}
+}
+
+func (s *suggestionServiceImpl) computeFor(ctx context.Context, userID uuid.UUID) ([]*entities.Suggestion, error) {
+	suggestions, err := s.suggestionRepo.FindFriendsOfFriends(ctx, userID, suggestionLimit, suggestionMutualLimit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.suggestionRepo.SaveCached(ctx, userID, suggestions, s.cacheTTL); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+func (s *suggestionServiceImpl) toSuggestionResponse(suggestion *entities.Suggestion) *dto.SuggestionResponse {
+	mutualsResponse := make([]*dto.UserResponse, len(suggestion.Mutuals))
+
+	for i, mutual := range suggestion.Mutuals {
+		mutualsResponse[i] = &dto.UserResponse{
+			ID:       mutual.ID.String(),
+			Username: mutual.Username,
+			Avatar:   mutual.Avatar,
+		}
+	}
+
+	return &dto.SuggestionResponse{
+		User: &dto.UserResponse{
+			ID:       suggestion.User.ID.String(),
+			Username: suggestion.User.Username,
+			Avatar:   suggestion.User.Avatar,
+		},
+		MutualCount:     suggestion.MutualCount,
+		MutualFollowers: mutualsResponse,
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/davidafdal/post-app/pkg/textparser"
+	"github.com/google/uuid"
+)
+
+func toTextEntitiesResponse(text string) []*dto.TextEntityResponse {
+	parsed := textparser.Parse(text)
+	entitiesResponse := make([]*dto.TextEntityResponse, len(parsed))
+
+	for i, e := range parsed {
+		entitiesResponse[i] = &dto.TextEntityResponse{
+			Type:  string(e.Type),
+			Value: e.Value,
+			Start: e.Start,
+			End:   e.End,
+		}
+	}
+
+	return entitiesResponse
+}
+
+func notifyMentions(ctx context.Context, notificationRepo repositories.NotificationRepository, notificationType string, actorID uuid.UUID, userIDs []uuid.UUID, feedID, commentID *uuid.UUID) error {
+	notifications := make([]*entities.Notification, 0, len(userIDs))
+
+	for _, userID := range userIDs {
+		if userID == actorID {
+			continue
+		}
+
+		notifications = append(notifications, &entities.Notification{
+			UserID:    userID,
+			ActorID:   actorID,
+			Type:      notificationType,
+			FeedID:    feedID,
+			CommentID: commentID,
+		})
+	}
+
+	return notificationRepo.CreateMany(ctx, notifications)
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/google/uuid"
+)
+
+type trendingWindow struct {
+	duration time.Duration
+	halfLife time.Duration
+}
+
+// trendingWindows are the sliding windows trending is computed over. The half
+// life controls how fast an engagement loses weight inside its window.
+var trendingWindows = map[string]trendingWindow{
+	"day":  {duration: 24 * time.Hour, halfLife: 6 * time.Hour},
+	"week": {duration: 7 * 24 * time.Hour, halfLife: 36 * time.Hour},
+}
+
+var engagementWeights = map[string]float64{
+	entities.EngagementPost:     1,
+	entities.EngagementReaction: 1,
+	entities.EngagementComment:  2,
+}
+
+const DefaultTrendingWindow = "day"
+
+var ErrInvalidTrendingWindow = errors.New("invalid trending window")
+
+type TrendingService interface {
+	ComputeTrending(ctx context.Context) error
+	GetTrendingTags(ctx context.Context, window string, limit int) ([]*dto.TrendingTagResponse, error)
+	GetTrendingFeeds(ctx context.Context, viewerID uuid.UUID, window string, limit int) ([]*dto.FeedResponse, error)
+}
+
+type trendingServiceImpl struct {
+	trendingRepo repositories.TrendingRepository
+	feedRepo     repositories.FeedRepository
+	limit        int
+	now          func() time.Time
+}
+
+func NewTrendingService(trendingRepo repositories.TrendingRepository, feedRepo repositories.FeedRepository, limit int) TrendingService {
+	return &trendingServiceImpl{
+		trendingRepo: trendingRepo,
+		feedRepo:     feedRepo,
+		limit:        limit,
+		now:          time.Now,
+	}
+}
+
+func (s *trendingServiceImpl) ComputeTrending(ctx context.Context) error {
+	now := s.now()
+
+	for name, window := range trendingWindows {
+		since := now.Add(-window.duration)
+
+		feedEngagements, err := s.trendingRepo.FindFeedEngagements(ctx, since)
+		if err != nil {
+			return err
+		}
+
+		if err := s.trendingRepo.SaveRanking(ctx, trendingFeedsKey(name), rankEngagements(feedEngagements, now, window.halfLife, s.limit)); err != nil {
+			return err
+		}
+
+		tagEngagements, err := s.trendingRepo.FindHashtagEngagements(ctx, since)
+		if err != nil {
+			return err
+		}
+
+		if err := s.trendingRepo.SaveRanking(ctx, trendingTagsKey(name), rankEngagements(tagEngagements, now, window.halfLife, s.limit)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *trendingServiceImpl) GetTrendingTags(ctx context.Context, window string, limit int) ([]*dto.TrendingTagResponse, error) {
+	if _, ok := trendingWindows[window]; !ok {
+		return nil, ErrInvalidTrendingWindow
+	}
+
+	items, err := s.trendingRepo.GetRanking(ctx, trendingTagsKey(window), limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tagsResponse := make([]*dto.TrendingTagResponse, len(items))
+
+	for i, item := range items {
+		tagsResponse[i] = &dto.TrendingTagResponse{
+			Tag:   item.Key,
+			Score: item.Score,
+		}
+	}
+
+	return tagsResponse, nil
+}
+
+func (s *trendingServiceImpl) GetTrendingFeeds(ctx context.Context, viewerID uuid.UUID, window string, limit int) ([]*dto.FeedResponse, error) {
+	if _, ok := trendingWindows[window]; !ok {
+		return nil, ErrInvalidTrendingWindow
+	}
+
+	items, err := s.trendingRepo.GetRanking(ctx, trendingFeedsKey(window), limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	feedIDs := make([]uuid.UUID, 0, len(items))
+
+	for _, item := range items {
+		id, err := uuid.Parse(item.Key)
+		if err != nil {
+			continue
+		}
+		feedIDs = append(feedIDs, id)
+	}
+
+	feeds, err := s.feedRepo.GetFeedsByIDs(ctx, feedIDs, viewerID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadQuotedFeeds(ctx, s.feedRepo, viewerID, feeds); err != nil {
+		return nil, err
+	}
+
+	feedResponse := make([]*dto.FeedResponse, 0, len(feeds))
+
+	for _, feed := range feeds {
+		feedResponse = append(feedResponse, toFeedResponse(feed))
+	}
+
+	return feedResponse, nil
+}
+
+// rankEngagements sums the weight of every engagement per key, decayed
+// exponentially by its age, and returns the top limit keys by score.
+func rankEngagements(engagements []*entities.Engagement, now time.Time, halfLife time.Duration, limit int) []*entities.RankedItem {
+	scores := make(map[string]float64)
+
+	for _, e := range engagements {
+		age := now.Sub(e.CreatedAt)
+		if age < 0 {
+			age = 0
+		}
+
+		decay := math.Exp(-math.Ln2 * age.Hours() / halfLife.Hours())
+		scores[e.Key] += engagementWeights[e.Kind] * decay
+	}
+
+	items := make([]*entities.RankedItem, 0, len(scores))
+
+	for key, score := range scores {
+		items = append(items, &entities.RankedItem{Key: key, Score: score})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Score == items[j].Score {
+			return items[i].Key < items[j].Key
+		}
+		return items[i].Score > items[j].Score
+	})
+
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+func trendingFeedsKey(window string) string {
+	return "trending:feeds:" + window
+}
+
+func trendingTagsKey(window string) string {
+	return "trending:tags:" + window
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/pkg/upload"
+	"github.com/google/uuid"
+)
+
+// UploadService lets large media be sent in chunks and resumed after a
+// dropped connection. A complete upload is attached to a feed by passing its
+// id to CreateFeed. Uploads are only visible to the user who started them.
+type UploadService interface {
+	CreateUpload(ctx context.Context, req *dto.CreateUploadRequest) (*dto.UploadResponse, error)
+	GetUpload(ctx context.Context, uploadID string, userID uuid.UUID) (*dto.UploadResponse, error)
+	AppendUpload(ctx context.Context, req *dto.AppendUploadRequest, chunk io.Reader) (*dto.UploadResponse, error)
+	DeleteUpload(ctx context.Context, uploadID string, userID uuid.UUID) error
+	MaxSize() int64
+}
+
+type uploadServiceImpl struct {
+	resumable upload.ResumableStore
+	maxSize   int64
+}
+
+func NewUploadService(resumable upload.ResumableStore, maxSize int64) UploadService {
+	return &uploadServiceImpl{
+		resumable: resumable,
+		maxSize:   maxSize,
+	}
+}
+
+func (s *uploadServiceImpl) CreateUpload(ctx context.Context, req *dto.CreateUploadRequest) (*dto.UploadResponse, error) {
+	if req.Length <= 0 {
+		return nil, ErrInvalidUploadLength
+	}
+
+	if s.maxSize > 0 && req.Length > s.maxSize {
+		return nil, ErrUploadTooLarge
+	}
+
+	created, err := s.resumable.Create(req.UserID.String(), req.Length, req.Metadata)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return toUploadResponse(created), nil
+}
+
+func (s *uploadServiceImpl) GetUpload(ctx context.Context, uploadID string, userID uuid.UUID) (*dto.UploadResponse, error) {
+	found, err := findOwnUpload(s.resumable, uploadID, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return toUploadResponse(found), nil
+}
+
+// AppendUpload writes chunk at the offset the client believes the upload is
+// at. On a mismatch nothing is written and the client is expected to ask for
+// the current offset and resume from there.
+func (s *uploadServiceImpl) AppendUpload(ctx context.Context, req *dto.AppendUploadRequest, chunk io.Reader) (*dto.UploadResponse, error) {
+	if _, err := findOwnUpload(s.resumable, req.UploadID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	appended, err := s.resumable.Append(req.UploadID, req.Offset, chunk)
+
+	switch {
+	case errors.Is(err, upload.ErrOffsetMismatch), errors.Is(err, upload.ErrUploadLocked):
+		return nil, ErrUploadOffsetMismatch
+	case errors.Is(err, upload.ErrUploadTooLarge):
+		return nil, ErrUploadTooLarge
+	case errors.Is(err, upload.ErrUploadNotFound):
+		return nil, ErrUploadNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return toUploadResponse(appended), nil
+}
+
+func (s *uploadServiceImpl) DeleteUpload(ctx context.Context, uploadID string, userID uuid.UUID) error {
+	if _, err := findOwnUpload(s.resumable, uploadID, userID); err != nil {
+		return err
+	}
+
+	err := s.resumable.Delete(uploadID)
+
+	switch {
+	case errors.Is(err, upload.ErrUploadNotFound):
+		return ErrUploadNotFound
+	case errors.Is(err, upload.ErrUploadLocked):
+		return ErrUploadOffsetMismatch
+	default:
+		return err
+	}
+}
+
+func (s *uploadServiceImpl) MaxSize() int64 {
+	return s.maxSize
+}
+
+// findOwnUpload looks up an upload of userID. Uploads of other users are
+// reported as missing.
+func findOwnUpload(resumable upload.ResumableStore, uploadID string, userID uuid.UUID) (*upload.Upload, error) {
+	found, err := resumable.Get(uploadID)
+
+	if errors.Is(err, upload.ErrUploadNotFound) {
+		return nil, ErrUploadNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if found.OwnerID != userID.String() {
+		return nil, ErrUploadNotFound
+	}
+
+	return found, nil
+}
+
+func toUploadResponse(u *upload.Upload) *dto.UploadResponse {
+	return &dto.UploadResponse{
+		ID:       u.ID,
+		Length:   u.Length,
+		Offset:   u.Offset,
+		Complete: u.Complete(),
+	}
+}
@@ -2,12 +2,16 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"mime/multipart"
 
 	"github.com/davidafdal/post-app/internal/dto"
 	"github.com/davidafdal/post-app/internal/entities"
 	"github.com/davidafdal/post-app/internal/repositories"
-	"github.com/davidafdal/post-app/pkg/cloudinary"
+	"github.com/davidafdal/post-app/pkg/cursor"
+	"github.com/davidafdal/post-app/pkg/mediacheck"
+	"github.com/davidafdal/post-app/pkg/storage"
 	"github.com/davidafdal/post-app/pkg/token"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -25,23 +29,38 @@ type UserService interface {
 	Register(req *dto.CreateUserRequest, file *multipart.FileHeader) (*dto.UserResponse, error)
 	UpdateUser(req *dto.UpdatedUserRequest, file *multipart.FileHeader, userID uuid.UUID) (*dto.UserResponse, error)
 	FollowUser(followerID, followingID uuid.UUID) (string, error)
+	GetFollowRequests(userID uuid.UUID) ([]*dto.FollowRequestResponse, error)
+	BlockUser(blockerID, blockedID uuid.UUID) error
+	UnblockUser(blockerID, blockedID uuid.UUID) error
+	MuteUser(muterID, mutedID uuid.UUID) error
+	UnmuteUser(muterID, mutedID uuid.UUID) error
+	ApproveFollowRequest(requestID, userID uuid.UUID) error
+	RejectFollowRequest(requestID, userID uuid.UUID) error
+	GetFollowers(username string, viewerID uuid.UUID, after string, limit int) (*dto.FollowListResponse, error)
+	GetFollowings(username string, viewerID uuid.UUID, after string, limit int) (*dto.FollowListResponse, error)
+	GetMutuals(username string, viewerID uuid.UUID, after string, limit int) (*dto.FollowListResponse, error)
 	DeleteUser(userID uuid.UUID) error
 }
 
 type userServiceImpl struct {
-	userRepo          repositories.UserRepository
-	cloudinaryUseCase cloudinary.CloudinaryUseCase
-	tokenUseCase      token.TokenUseCase
+	userRepo        repositories.UserRepository
+	store           storage.Storage
+	avatarValidator *mediacheck.Validator
+	tokenUseCase    token.TokenUseCase
 }
 
-func NewUserService(userRepo repositories.UserRepository, cloudinaryUseCase cloudinary.CloudinaryUseCase, token token.TokenUseCase) UserService {
+func NewUserService(userRepo repositories.UserRepository, store storage.Storage, avatarValidator *mediacheck.Validator, token token.TokenUseCase) UserService {
 	return &userServiceImpl{
-		userRepo:          userRepo,
-		cloudinaryUseCase: cloudinaryUseCase,
-		tokenUseCase:      token,
+		userRepo:        userRepo,
+		store:           store,
+		avatarValidator: avatarValidator,
+		tokenUseCase:    token,
 	}
 }
 
+// avatarPrefix is the storage prefix avatars are uploaded under.
+const avatarPrefix = "avatars"
+
 func (s *userServiceImpl) GetUsers(search string) ([]*dto.UserResponse, error) {
 	users, err := s.userRepo.Find(search)
 
@@ -58,7 +77,7 @@ func (s *userServiceImpl) GetUsers(search string) ([]*dto.UserResponse, error) {
 }
 
 func (s *userServiceImpl) GetUserByUsername(username string) (*dto.UserResponse, error) {
-	user, err := s.userRepo.FindByUsername(username)
+	user, err := findUserByUsername(s.userRepo, username)
 
 	if err != nil {
 		return nil, err
@@ -72,11 +91,11 @@ func (s *userServiceImpl) Register(req *dto.CreateUserRequest, file *multipart.F
 	req.Avatar = ""
 
 	if file != nil {
-		filePath, err := s.cloudinaryUseCase.UploadFile(context.Background(), file)
+		url, err := s.uploadAvatar(file)
 		if err != nil {
 			return nil, err
 		}
-		req.Avatar = filePath
+		req.Avatar = url
 	}
 
 	hashPassowrd, err := bcrypt.GenerateFromPassword([]byte(req.Password), 14)
@@ -96,7 +115,7 @@ func (s *userServiceImpl) Register(req *dto.CreateUserRequest, file *multipart.F
 
 	if err != nil {
 		if req.Avatar != "" {
-			_ = s.cloudinaryUseCase.DeleteFile(context.Background(), req.Avatar)
+			_ = s.deleteAvatar(req.Avatar)
 		}
 		return nil, err
 	}
@@ -119,7 +138,11 @@ func (s *userServiceImpl) Login(req *dto.LoginRequest) (*jwtResponse, error) {
 		return nil, err
 	}
 
-	claims := s.tokenUseCase.CreateClaims(existedUser.ID.String(), existedUser.Email)
+	if existedUser.SuspendedAt != nil {
+		return nil, ErrAccountSuspended
+	}
+
+	claims := s.tokenUseCase.CreateClaims(existedUser.ID.String(), existedUser.Email, existedUser.Role)
 
 	accessToken, expiredAt, err := s.tokenUseCase.GenerateAccessToken(claims)
 
@@ -141,18 +164,16 @@ func (s *userServiceImpl) UpdateUser(req *dto.UpdatedUserRequest, file *multipar
 	}
 
 	if file != nil {
-		if exits.Avatar != "" {
-			if err := s.cloudinaryUseCase.DeleteFile(context.Background(), exits.Avatar); err != nil {
-				return nil, err
-			}
-		}
-
-		secureUrl, err := s.cloudinaryUseCase.UploadFile(context.Background(), file)
+		url, err := s.uploadAvatar(file)
 
 		if err != nil {
 			return nil, err
 		}
-		exits.Avatar = secureUrl
+
+		if err := s.deleteAvatar(exits.Avatar); err != nil {
+			return nil, err
+		}
+		exits.Avatar = url
 	}
 
 	if req.Username != "" {
@@ -163,12 +184,24 @@ func (s *userServiceImpl) UpdateUser(req *dto.UpdatedUserRequest, file *multipar
 		exits.Bio = req.Bio
 	}
 
+	wasPrivate := exits.IsPrivate
+
+	if req.IsPrivate != nil {
+		exits.IsPrivate = *req.IsPrivate
+	}
+
 	user, err := s.userRepo.Update(exits)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if wasPrivate && !user.IsPrivate {
+		if err := s.userRepo.ApproveAllFollowRequests(user.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	return s.toUserResponse(user), nil
 }
 
@@ -179,16 +212,75 @@ func (s *userServiceImpl) DeleteUser(userID uuid.UUID) error {
 		return err
 	}
 
-	if exits.Avatar != "" {
-		if err := s.cloudinaryUseCase.DeleteFile(context.Background(), exits.Avatar); err != nil {
-			return err
-		}
+	if err := s.deleteAvatar(exits.Avatar); err != nil {
+		return err
 	}
 
 	return s.userRepo.Delete(userID)
 }
 
+// uploadAvatar checks that file is an image within the avatar limits and
+// stores it.
+func (s *userServiceImpl) uploadAvatar(file *multipart.FileHeader) (string, error) {
+	medias, err := s.avatarValidator.Check([]*multipart.FileHeader{file}, mediacheck.KindImage)
+	if err != nil {
+		return "", err
+	}
+
+	object, err := storage.PutFile(context.Background(), s.store, avatarPrefix, file, medias[0].ContentType)
+	if err != nil {
+		return "", err
+	}
+
+	return object.URL, nil
+}
+
+// deleteAvatar removes a stored avatar. Avatars that are already gone, or that
+// were stored by another backend before the storage driver changed, are left
+// alone.
+func (s *userServiceImpl) deleteAvatar(url string) error {
+	if url == "" {
+		return nil
+	}
+
+	err := storage.DeleteURL(context.Background(), s.store, url)
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidURL) {
+		return nil
+	}
+	return err
+}
+
+// FollowUser toggles a follow. Following a private account creates a follow
+// request instead, and calling it again while pending cancels the request.
 func (s *userServiceImpl) FollowUser(followerID, followingID uuid.UUID) (string, error) {
+	target, err := findUser(s.userRepo, followingID)
+
+	if err != nil {
+		return "", err
+	}
+
+	blocked, err := s.userRepo.IsBlockedBetween(followerID, followingID)
+
+	if err != nil {
+		return "", err
+	}
+
+	if blocked {
+		return "", ErrUserBlocked
+	}
+
+	if target.IsPrivate {
+		isFollowing, err := s.userRepo.IsFollowing(followerID, followingID)
+
+		if err != nil {
+			return "", err
+		}
+
+		if !isFollowing {
+			return s.userRepo.ToggleFollowRequest(followerID, followingID)
+		}
+	}
+
 	status, err := s.userRepo.ToggleFollow(followerID, followingID)
 
 	if err != nil {
@@ -198,6 +290,169 @@ func (s *userServiceImpl) FollowUser(followerID, followingID uuid.UUID) (string,
 	return status, nil
 }
 
+// BlockUser blocks the user and removes follows and pending follow requests
+// between both users, in both directions.
+func (s *userServiceImpl) BlockUser(blockerID, blockedID uuid.UUID) error {
+	if blockerID == blockedID {
+		return ErrSelfAction
+	}
+
+	if _, err := findUser(s.userRepo, blockedID); err != nil {
+		return err
+	}
+
+	// The block is recorded first so that FollowUser refuses new follows
+	// while the existing ones are removed.
+	if err := s.userRepo.Block(blockerID, blockedID); err != nil {
+		return err
+	}
+
+	for _, pair := range [][2]uuid.UUID{{blockerID, blockedID}, {blockedID, blockerID}} {
+		if err := s.userRepo.Unfollow(pair[0], pair[1]); err != nil {
+			return err
+		}
+
+		if err := s.userRepo.CancelFollowRequest(pair[0], pair[1]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *userServiceImpl) UnblockUser(blockerID, blockedID uuid.UUID) error {
+	return s.userRepo.Unblock(blockerID, blockedID)
+}
+
+func (s *userServiceImpl) MuteUser(muterID, mutedID uuid.UUID) error {
+	if muterID == mutedID {
+		return ErrSelfAction
+	}
+
+	if _, err := findUser(s.userRepo, mutedID); err != nil {
+		return err
+	}
+
+	return s.userRepo.Mute(muterID, mutedID)
+}
+
+func (s *userServiceImpl) UnmuteUser(muterID, mutedID uuid.UUID) error {
+	return s.userRepo.Unmute(muterID, mutedID)
+}
+
+func (s *userServiceImpl) GetFollowRequests(userID uuid.UUID) ([]*dto.FollowRequestResponse, error) {
+	requests, err := s.userRepo.FindFollowRequests(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	requestsResponse := make([]*dto.FollowRequestResponse, len(requests))
+
+	for i, v := range requests {
+		requestsResponse[i] = &dto.FollowRequestResponse{
+			ID: v.ID,
+			Requester: &dto.UserResponse{
+				ID:       v.Requester.ID.String(),
+				Username: v.Requester.Username,
+				Avatar:   v.Requester.Avatar,
+			},
+			CreatedAt: v.CreatedAt,
+		}
+	}
+
+	return requestsResponse, nil
+}
+
+func (s *userServiceImpl) ApproveFollowRequest(requestID, userID uuid.UUID) error {
+	err := s.userRepo.ApproveFollowRequest(requestID, userID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrFollowRequestNotFound
+	}
+
+	return err
+}
+
+func (s *userServiceImpl) RejectFollowRequest(requestID, userID uuid.UUID) error {
+	err := s.userRepo.RejectFollowRequest(requestID, userID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrFollowRequestNotFound
+	}
+
+	return err
+}
+
+func (s *userServiceImpl) GetFollowers(username string, viewerID uuid.UUID, after string, limit int) (*dto.FollowListResponse, error) {
+	return s.getFollowList(s.userRepo.FindFollowers, username, viewerID, after, limit)
+}
+
+func (s *userServiceImpl) GetFollowings(username string, viewerID uuid.UUID, after string, limit int) (*dto.FollowListResponse, error) {
+	return s.getFollowList(s.userRepo.FindFollowings, username, viewerID, after, limit)
+}
+
+func (s *userServiceImpl) GetMutuals(username string, viewerID uuid.UUID, after string, limit int) (*dto.FollowListResponse, error) {
+	return s.getFollowList(s.userRepo.FindMutuals, username, viewerID, after, limit)
+}
+
+type followListFinder func(userID, viewerID uuid.UUID, after *cursor.Cursor, limit int) ([]*entities.FollowUser, error)
+
+func (s *userServiceImpl) getFollowList(find followListFinder, username string, viewerID uuid.UUID, after string, limit int) (*dto.FollowListResponse, error) {
+	afterCursor, err := cursor.Decode(after)
+
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := findUserByUsername(s.userRepo, username)
+
+	if err != nil {
+		return nil, err
+	}
+
+	canView, err := s.userRepo.CanView(viewerID, user.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !canView {
+		return nil, ErrPrivateAccount
+	}
+
+	followUsers, err := find(user.ID, viewerID, afterCursor, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	listResponse := &dto.FollowListResponse{
+		Items: make([]*dto.FollowUserResponse, len(followUsers)),
+	}
+
+	for i, v := range followUsers {
+		listResponse.Items[i] = &dto.FollowUserResponse{
+			User: &dto.UserResponse{
+				ID:       v.User.ID.String(),
+				Username: v.User.Username,
+				Avatar:   v.User.Avatar,
+				Bio:      v.User.Bio,
+			},
+			FollowedAt:       v.FollowedAt,
+			FollowedByViewer: v.FollowedByViewer,
+			FollowsViewer:    v.FollowsViewer,
+		}
+	}
+
+	if len(followUsers) == limit {
+		last := followUsers[len(followUsers)-1]
+		listResponse.NextCursor = cursor.Encode(cursor.Cursor{CreatedAt: last.FollowedAt, ID: last.FollowID})
+	}
+
+	return listResponse, nil
+}
+
 func (s *userServiceImpl) toUserResponse(user *entities.User) *dto.UserResponse {
 	dataResponse := &dto.UserResponse{
 		ID:        user.ID.String(),
@@ -207,6 +462,7 @@ func (s *userServiceImpl) toUserResponse(user *entities.User) *dto.UserResponse
 		Bio:       user.Bio,
 		Followers: user.Followers,
 		Following: user.Followings,
+		IsPrivate: user.IsPrivate,
 		CreatedAt: user.CreatedAt,
 	}
 
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: C:\Users\gamin\OneDrive\Desktop\sosmed-app\sosmed-golang\pkg\videoproc\videoproc.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockProcessor is a mock of Processor interface.
+type MockProcessor struct {
+	ctrl     *gomock.Controller
+	recorder *MockProcessorMockRecorder
+}
+
+// MockProcessorMockRecorder is the mock recorder for MockProcessor.
+type MockProcessorMockRecorder struct {
+	mock *MockProcessor
+}
+
+// NewMockProcessor creates a new mock instance.
+func NewMockProcessor(ctrl *gomock.Controller) *MockProcessor {
+	mock := &MockProcessor{ctrl: ctrl}
+	mock.recorder = &MockProcessorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProcessor) EXPECT() *MockProcessorMockRecorder {
+	return m.recorder
+}
+
+// Poster mocks base method.
+func (m *MockProcessor) Poster(ctx context.Context, src, dst string, at time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Poster", ctx, src, dst, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Poster indicates an expected call of Poster.
+func (mr *MockProcessorMockRecorder) Poster(ctx, src, dst, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Poster", reflect.TypeOf((*MockProcessor)(nil).Poster), ctx, src, dst, at)
+}
+
+// Transcode mocks base method.
+func (m *MockProcessor) Transcode(ctx context.Context, src, dst string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transcode", ctx, src, dst)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transcode indicates an expected call of Transcode.
+func (mr *MockProcessorMockRecorder) Transcode(ctx, src, dst interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transcode", reflect.TypeOf((*MockProcessor)(nil).Transcode), ctx, src, dst)
+}
@@ -0,0 +1,202 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: C:\Users\gamin\OneDrive\Desktop\sosmed-app\sosmed-golang\internal\repositories\commnet_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entities "github.com/davidafdal/post-app/internal/entities"
+	cursor "github.com/davidafdal/post-app/pkg/cursor"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockCommentRepository is a mock of CommentRepository interface.
+type MockCommentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentRepositoryMockRecorder
+}
+
+// MockCommentRepositoryMockRecorder is the mock recorder for MockCommentRepository.
+type MockCommentRepositoryMockRecorder struct {
+	mock *MockCommentRepository
+}
+
+// NewMockCommentRepository creates a new mock instance.
+func NewMockCommentRepository(ctrl *gomock.Controller) *MockCommentRepository {
+	mock := &MockCommentRepository{ctrl: ctrl}
+	mock.recorder = &MockCommentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommentRepository) EXPECT() *MockCommentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCommentRepository) Create(ctx context.Context, comment *entities.Comment) (*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, comment)
+	ret0, _ := ret[0].(*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCommentRepositoryMockRecorder) Create(ctx, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommentRepository)(nil).Create), ctx, comment)
+}
+
+// CreateReply mocks base method.
+func (m *MockCommentRepository) CreateReply(ctx context.Context, comment *entities.Comment) (*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReply", ctx, comment)
+	ret0, _ := ret[0].(*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReply indicates an expected call of CreateReply.
+func (mr *MockCommentRepositoryMockRecorder) CreateReply(ctx, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReply", reflect.TypeOf((*MockCommentRepository)(nil).CreateReply), ctx, comment)
+}
+
+// FindByID mocks base method.
+func (m *MockCommentRepository) FindByID(ctx context.Context, commentID uuid.UUID) (*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, commentID)
+	ret0, _ := ret[0].(*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockCommentRepositoryMockRecorder) FindByID(ctx, commentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCommentRepository)(nil).FindByID), ctx, commentID)
+}
+
+// FindPinnedComment mocks base method.
+func (m *MockCommentRepository) FindPinnedComment(ctx context.Context, feedID, viewerID uuid.UUID) (*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPinnedComment", ctx, feedID, viewerID)
+	ret0, _ := ret[0].(*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPinnedComment indicates an expected call of FindPinnedComment.
+func (mr *MockCommentRepositoryMockRecorder) FindPinnedComment(ctx, feedID, viewerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPinnedComment", reflect.TypeOf((*MockCommentRepository)(nil).FindPinnedComment), ctx, feedID, viewerID)
+}
+
+// FindRepliesComment mocks base method.
+func (m *MockCommentRepository) FindRepliesComment(ctx context.Context, commentID, viewerID uuid.UUID, after *cursor.Cursor, limit int) ([]*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRepliesComment", ctx, commentID, viewerID, after, limit)
+	ret0, _ := ret[0].([]*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRepliesComment indicates an expected call of FindRepliesComment.
+func (mr *MockCommentRepositoryMockRecorder) FindRepliesComment(ctx, commentID, viewerID, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRepliesComment", reflect.TypeOf((*MockCommentRepository)(nil).FindRepliesComment), ctx, commentID, viewerID, after, limit)
+}
+
+// FindReplyPreviews mocks base method.
+func (m *MockCommentRepository) FindReplyPreviews(ctx context.Context, parentIDs []uuid.UUID, viewerID uuid.UUID, perParent int) ([]*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReplyPreviews", ctx, parentIDs, viewerID, perParent)
+	ret0, _ := ret[0].([]*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReplyPreviews indicates an expected call of FindReplyPreviews.
+func (mr *MockCommentRepositoryMockRecorder) FindReplyPreviews(ctx, parentIDs, viewerID, perParent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReplyPreviews", reflect.TypeOf((*MockCommentRepository)(nil).FindReplyPreviews), ctx, parentIDs, viewerID, perParent)
+}
+
+// FindTopComment mocks base method.
+func (m *MockCommentRepository) FindTopComment(ctx context.Context, feedID, viewerID uuid.UUID, sort string, after *cursor.Cursor, limit int) ([]*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTopComment", ctx, feedID, viewerID, sort, after, limit)
+	ret0, _ := ret[0].([]*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTopComment indicates an expected call of FindTopComment.
+func (mr *MockCommentRepositoryMockRecorder) FindTopComment(ctx, feedID, viewerID, sort, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTopComment", reflect.TypeOf((*MockCommentRepository)(nil).FindTopComment), ctx, feedID, viewerID, sort, after, limit)
+}
+
+// SoftDelete mocks base method.
+func (m *MockCommentRepository) SoftDelete(ctx context.Context, commentID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDelete", ctx, commentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SoftDelete indicates an expected call of SoftDelete.
+func (mr *MockCommentRepositoryMockRecorder) SoftDelete(ctx, commentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockCommentRepository)(nil).SoftDelete), ctx, commentID)
+}
+
+// ToggleLiked mocks base method.
+func (m *MockCommentRepository) ToggleLiked(ctx context.Context, commentID, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ToggleLiked", ctx, commentID, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ToggleLiked indicates an expected call of ToggleLiked.
+func (mr *MockCommentRepositoryMockRecorder) ToggleLiked(ctx, commentID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToggleLiked", reflect.TypeOf((*MockCommentRepository)(nil).ToggleLiked), ctx, commentID, userID)
+}
+
+// TogglePin mocks base method.
+func (m *MockCommentRepository) TogglePin(ctx context.Context, feedID, commentID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TogglePin", ctx, feedID, commentID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TogglePin indicates an expected call of TogglePin.
+func (mr *MockCommentRepositoryMockRecorder) TogglePin(ctx, feedID, commentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TogglePin", reflect.TypeOf((*MockCommentRepository)(nil).TogglePin), ctx, feedID, commentID)
+}
+
+// Update mocks base method.
+func (m *MockCommentRepository) Update(ctx context.Context, comment *entities.Comment) (*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, comment)
+	ret0, _ := ret[0].(*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCommentRepositoryMockRecorder) Update(ctx, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCommentRepository)(nil).Update), ctx, comment)
+}
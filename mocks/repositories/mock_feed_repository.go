@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	entities "github.com/davidafdal/post-app/internal/entities"
+	cursor "github.com/davidafdal/post-app/pkg/cursor"
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
 )
@@ -36,6 +37,35 @@ func (m *MockFeedRepository) EXPECT() *MockFeedRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddMedia mocks base method.
+func (m *MockFeedRepository) AddMedia(ctx context.Context, media *entities.FeedMedia) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMedia", ctx, media)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMedia indicates an expected call of AddMedia.
+func (mr *MockFeedRepositoryMockRecorder) AddMedia(ctx, media interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMedia", reflect.TypeOf((*MockFeedRepository)(nil).AddMedia), ctx, media)
+}
+
+// AddRepost mocks base method.
+func (m *MockFeedRepository) AddRepost(ctx context.Context, feedID, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRepost", ctx, feedID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddRepost indicates an expected call of AddRepost.
+func (mr *MockFeedRepositoryMockRecorder) AddRepost(ctx, feedID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRepost", reflect.TypeOf((*MockFeedRepository)(nil).AddRepost), ctx, feedID, userID)
+}
+
 // Create mocks base method.
 func (m *MockFeedRepository) Create(ctx context.Context, feed *entities.Feed) (*entities.Feed, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +81,111 @@ func (mr *MockFeedRepositoryMockRecorder) Create(ctx, feed interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFeedRepository)(nil).Create), ctx, feed)
 }
 
+// FindMedias mocks base method.
+func (m *MockFeedRepository) FindMedias(ctx context.Context, feedID uuid.UUID) ([]*entities.FeedMedia, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMedias", ctx, feedID)
+	ret0, _ := ret[0].([]*entities.FeedMedia)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMedias indicates an expected call of FindMedias.
+func (mr *MockFeedRepositoryMockRecorder) FindMedias(ctx, feedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMedias", reflect.TypeOf((*MockFeedRepository)(nil).FindMedias), ctx, feedID)
+}
+
+// FindMentionedUsers mocks base method.
+func (m *MockFeedRepository) FindMentionedUsers(ctx context.Context, feedID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMentionedUsers", ctx, feedID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMentionedUsers indicates an expected call of FindMentionedUsers.
+func (mr *MockFeedRepositoryMockRecorder) FindMentionedUsers(ctx, feedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMentionedUsers", reflect.TypeOf((*MockFeedRepository)(nil).FindMentionedUsers), ctx, feedID)
+}
+
+// FindOwnerID mocks base method.
+func (m *MockFeedRepository) FindOwnerID(ctx context.Context, feedID uuid.UUID) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOwnerID", ctx, feedID)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOwnerID indicates an expected call of FindOwnerID.
+func (mr *MockFeedRepositoryMockRecorder) FindOwnerID(ctx, feedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOwnerID", reflect.TypeOf((*MockFeedRepository)(nil).FindOwnerID), ctx, feedID)
+}
+
+// FindReaction mocks base method.
+func (m *MockFeedRepository) FindReaction(ctx context.Context, feedID, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReaction", ctx, feedID, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReaction indicates an expected call of FindReaction.
+func (mr *MockFeedRepositoryMockRecorder) FindReaction(ctx, feedID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReaction", reflect.TypeOf((*MockFeedRepository)(nil).FindReaction), ctx, feedID, userID)
+}
+
+// FindReactions mocks base method.
+func (m *MockFeedRepository) FindReactions(ctx context.Context, feedID, viewerID uuid.UUID, reactionType string, after *cursor.Cursor, limit int) ([]*entities.Reaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReactions", ctx, feedID, viewerID, reactionType, after, limit)
+	ret0, _ := ret[0].([]*entities.Reaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReactions indicates an expected call of FindReactions.
+func (mr *MockFeedRepositoryMockRecorder) FindReactions(ctx, feedID, viewerID, reactionType, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReactions", reflect.TypeOf((*MockFeedRepository)(nil).FindReactions), ctx, feedID, viewerID, reactionType, after, limit)
+}
+
+// GetDrafts mocks base method.
+func (m *MockFeedRepository) GetDrafts(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDrafts", ctx, userID, limit)
+	ret0, _ := ret[0].([]*entities.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDrafts indicates an expected call of GetDrafts.
+func (mr *MockFeedRepositoryMockRecorder) GetDrafts(ctx, userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDrafts", reflect.TypeOf((*MockFeedRepository)(nil).GetDrafts), ctx, userID, limit)
+}
+
+// GetExploreFeedIDs mocks base method.
+func (m *MockFeedRepository) GetExploreFeedIDs(ctx context.Context, userID uuid.UUID, excludedAuthorIDs []uuid.UUID, limit int) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExploreFeedIDs", ctx, userID, excludedAuthorIDs, limit)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExploreFeedIDs indicates an expected call of GetExploreFeedIDs.
+func (mr *MockFeedRepositoryMockRecorder) GetExploreFeedIDs(ctx, userID, excludedAuthorIDs, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExploreFeedIDs", reflect.TypeOf((*MockFeedRepository)(nil).GetExploreFeedIDs), ctx, userID, excludedAuthorIDs, limit)
+}
+
 // GetFeeds mocks base method.
 func (m *MockFeedRepository) GetFeeds(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.Feed, error) {
 	m.ctrl.T.Helper()
@@ -66,17 +201,221 @@ func (mr *MockFeedRepositoryMockRecorder) GetFeeds(ctx, userID, limit interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeeds", reflect.TypeOf((*MockFeedRepository)(nil).GetFeeds), ctx, userID, limit)
 }
 
-// ToggleLiked mocks base method.
-func (m *MockFeedRepository) ToggleLiked(feedID, userID uuid.UUID) (string, error) {
+// GetFeedsByHashtag mocks base method.
+func (m *MockFeedRepository) GetFeedsByHashtag(ctx context.Context, tag string, viewerID uuid.UUID, limit int) ([]*entities.Feed, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ToggleLiked", feedID, userID)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetFeedsByHashtag", ctx, tag, viewerID, limit)
+	ret0, _ := ret[0].([]*entities.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeedsByHashtag indicates an expected call of GetFeedsByHashtag.
+func (mr *MockFeedRepositoryMockRecorder) GetFeedsByHashtag(ctx, tag, viewerID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedsByHashtag", reflect.TypeOf((*MockFeedRepository)(nil).GetFeedsByHashtag), ctx, tag, viewerID, limit)
+}
+
+// GetFeedsByIDs mocks base method.
+func (m *MockFeedRepository) GetFeedsByIDs(ctx context.Context, feedIDs []uuid.UUID, viewerID uuid.UUID) ([]*entities.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeedsByIDs", ctx, feedIDs, viewerID)
+	ret0, _ := ret[0].([]*entities.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeedsByIDs indicates an expected call of GetFeedsByIDs.
+func (mr *MockFeedRepositoryMockRecorder) GetFeedsByIDs(ctx, feedIDs, viewerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedsByIDs", reflect.TypeOf((*MockFeedRepository)(nil).GetFeedsByIDs), ctx, feedIDs, viewerID)
+}
+
+// GetFeedsByUser mocks base method.
+func (m *MockFeedRepository) GetFeedsByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeedsByUser", ctx, userID, limit)
+	ret0, _ := ret[0].([]*entities.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeedsByUser indicates an expected call of GetFeedsByUser.
+func (mr *MockFeedRepositoryMockRecorder) GetFeedsByUser(ctx, userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedsByUser", reflect.TypeOf((*MockFeedRepository)(nil).GetFeedsByUser), ctx, userID, limit)
+}
+
+// IsVisible mocks base method.
+func (m *MockFeedRepository) IsVisible(ctx context.Context, feedID, viewerID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsVisible", ctx, feedID, viewerID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsVisible indicates an expected call of IsVisible.
+func (mr *MockFeedRepositoryMockRecorder) IsVisible(ctx, feedID, viewerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsVisible", reflect.TypeOf((*MockFeedRepository)(nil).IsVisible), ctx, feedID, viewerID)
+}
+
+// MarkSeen mocks base method.
+func (m *MockFeedRepository) MarkSeen(ctx context.Context, userID uuid.UUID, feedIDs []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSeen", ctx, userID, feedIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSeen indicates an expected call of MarkSeen.
+func (mr *MockFeedRepositoryMockRecorder) MarkSeen(ctx, userID, feedIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSeen", reflect.TypeOf((*MockFeedRepository)(nil).MarkSeen), ctx, userID, feedIDs)
+}
+
+// PublishDueFeeds mocks base method.
+func (m *MockFeedRepository) PublishDueFeeds(ctx context.Context) ([]*entities.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDueFeeds", ctx)
+	ret0, _ := ret[0].([]*entities.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDueFeeds indicates an expected call of PublishDueFeeds.
+func (mr *MockFeedRepositoryMockRecorder) PublishDueFeeds(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDueFeeds", reflect.TypeOf((*MockFeedRepository)(nil).PublishDueFeeds), ctx)
+}
+
+// RemoveReaction mocks base method.
+func (m *MockFeedRepository) RemoveReaction(ctx context.Context, feedID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReaction", ctx, feedID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveReaction indicates an expected call of RemoveReaction.
+func (mr *MockFeedRepositoryMockRecorder) RemoveReaction(ctx, feedID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReaction", reflect.TypeOf((*MockFeedRepository)(nil).RemoveReaction), ctx, feedID, userID)
+}
+
+// RemoveRepost mocks base method.
+func (m *MockFeedRepository) RemoveRepost(ctx context.Context, feedID, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRepost", ctx, feedID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveRepost indicates an expected call of RemoveRepost.
+func (mr *MockFeedRepositoryMockRecorder) RemoveRepost(ctx, feedID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRepost", reflect.TypeOf((*MockFeedRepository)(nil).RemoveRepost), ctx, feedID, userID)
+}
+
+// ReorderMedias mocks base method.
+func (m *MockFeedRepository) ReorderMedias(ctx context.Context, feedID uuid.UUID, mediaIDs []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderMedias", ctx, feedID, mediaIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderMedias indicates an expected call of ReorderMedias.
+func (mr *MockFeedRepositoryMockRecorder) ReorderMedias(ctx, feedID, mediaIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderMedias", reflect.TypeOf((*MockFeedRepository)(nil).ReorderMedias), ctx, feedID, mediaIDs)
+}
+
+// SetMediaStatus mocks base method.
+func (m *MockFeedRepository) SetMediaStatus(ctx context.Context, mediaID uuid.UUID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMediaStatus", ctx, mediaID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMediaStatus indicates an expected call of SetMediaStatus.
+func (mr *MockFeedRepositoryMockRecorder) SetMediaStatus(ctx, mediaID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMediaStatus", reflect.TypeOf((*MockFeedRepository)(nil).SetMediaStatus), ctx, mediaID, status)
+}
+
+// SetReaction mocks base method.
+func (m *MockFeedRepository) SetReaction(ctx context.Context, feedID, userID uuid.UUID, reactionType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReaction", ctx, feedID, userID, reactionType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReaction indicates an expected call of SetReaction.
+func (mr *MockFeedRepositoryMockRecorder) SetReaction(ctx, feedID, userID, reactionType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReaction", reflect.TypeOf((*MockFeedRepository)(nil).SetReaction), ctx, feedID, userID, reactionType)
+}
+
+// UpdateCaption mocks base method.
+func (m *MockFeedRepository) UpdateCaption(ctx context.Context, feed *entities.Feed) (*entities.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCaption", ctx, feed)
+	ret0, _ := ret[0].(*entities.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCaption indicates an expected call of UpdateCaption.
+func (mr *MockFeedRepositoryMockRecorder) UpdateCaption(ctx, feed interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCaption", reflect.TypeOf((*MockFeedRepository)(nil).UpdateCaption), ctx, feed)
+}
+
+// UpdateDraft mocks base method.
+func (m *MockFeedRepository) UpdateDraft(ctx context.Context, feed *entities.Feed) (*entities.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDraft", ctx, feed)
+	ret0, _ := ret[0].(*entities.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDraft indicates an expected call of UpdateDraft.
+func (mr *MockFeedRepositoryMockRecorder) UpdateDraft(ctx, feed interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDraft", reflect.TypeOf((*MockFeedRepository)(nil).UpdateDraft), ctx, feed)
+}
+
+// UpdateMedia mocks base method.
+func (m *MockFeedRepository) UpdateMedia(ctx context.Context, media *entities.FeedMedia) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMedia", ctx, media)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMedia indicates an expected call of UpdateMedia.
+func (mr *MockFeedRepositoryMockRecorder) UpdateMedia(ctx, media interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMedia", reflect.TypeOf((*MockFeedRepository)(nil).UpdateMedia), ctx, media)
+}
+
+// UpdateMediaDetails mocks base method.
+func (m *MockFeedRepository) UpdateMediaDetails(ctx context.Context, media *entities.FeedMedia) (*entities.FeedMedia, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMediaDetails", ctx, media)
+	ret0, _ := ret[0].(*entities.FeedMedia)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ToggleLiked indicates an expected call of ToggleLiked.
-func (mr *MockFeedRepositoryMockRecorder) ToggleLiked(feedID, userID interface{}) *gomock.Call {
+// UpdateMediaDetails indicates an expected call of UpdateMediaDetails.
+func (mr *MockFeedRepositoryMockRecorder) UpdateMediaDetails(ctx, media interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToggleLiked", reflect.TypeOf((*MockFeedRepository)(nil).ToggleLiked), feedID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMediaDetails", reflect.TypeOf((*MockFeedRepository)(nil).UpdateMediaDetails), ctx, media)
 }
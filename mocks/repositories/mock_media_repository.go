@@ -0,0 +1,154 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: C:\Users\gamin\OneDrive\Desktop\sosmed-app\sosmed-golang\internal\repositories\media_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entities "github.com/davidafdal/post-app/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockMediaRepository is a mock of MediaRepository interface.
+type MockMediaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMediaRepositoryMockRecorder
+}
+
+// MockMediaRepositoryMockRecorder is the mock recorder for MockMediaRepository.
+type MockMediaRepositoryMockRecorder struct {
+	mock *MockMediaRepository
+}
+
+// NewMockMediaRepository creates a new mock instance.
+func NewMockMediaRepository(ctrl *gomock.Controller) *MockMediaRepository {
+	mock := &MockMediaRepository{ctrl: ctrl}
+	mock.recorder = &MockMediaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMediaRepository) EXPECT() *MockMediaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// BlockFeedMedia mocks base method.
+func (m *MockMediaRepository) BlockFeedMedia(ctx context.Context, feedID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockFeedMedia", ctx, feedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockFeedMedia indicates an expected call of BlockFeedMedia.
+func (mr *MockMediaRepositoryMockRecorder) BlockFeedMedia(ctx, feedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockFeedMedia", reflect.TypeOf((*MockMediaRepository)(nil).BlockFeedMedia), ctx, feedID)
+}
+
+// CreateBlob mocks base method.
+func (m *MockMediaRepository) CreateBlob(ctx context.Context, blob *entities.MediaBlob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBlob", ctx, blob)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBlob indicates an expected call of CreateBlob.
+func (mr *MockMediaRepositoryMockRecorder) CreateBlob(ctx, blob interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBlob", reflect.TypeOf((*MockMediaRepository)(nil).CreateBlob), ctx, blob)
+}
+
+// DeleteBlob mocks base method.
+func (m *MockMediaRepository) DeleteBlob(ctx context.Context, blobID uuid.UUID, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBlob", ctx, blobID, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBlob indicates an expected call of DeleteBlob.
+func (mr *MockMediaRepositoryMockRecorder) DeleteBlob(ctx, blobID, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlob", reflect.TypeOf((*MockMediaRepository)(nil).DeleteBlob), ctx, blobID, before)
+}
+
+// FindBlob mocks base method.
+func (m *MockMediaRepository) FindBlob(ctx context.Context, sha256 string) (*entities.MediaBlob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBlob", ctx, sha256)
+	ret0, _ := ret[0].(*entities.MediaBlob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBlob indicates an expected call of FindBlob.
+func (mr *MockMediaRepositoryMockRecorder) FindBlob(ctx, sha256 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBlob", reflect.TypeOf((*MockMediaRepository)(nil).FindBlob), ctx, sha256)
+}
+
+// FindReferencedURLs mocks base method.
+func (m *MockMediaRepository) FindReferencedURLs(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReferencedURLs", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReferencedURLs indicates an expected call of FindReferencedURLs.
+func (mr *MockMediaRepositoryMockRecorder) FindReferencedURLs(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReferencedURLs", reflect.TypeOf((*MockMediaRepository)(nil).FindReferencedURLs), ctx)
+}
+
+// FindUnreferencedBlobs mocks base method.
+func (m *MockMediaRepository) FindUnreferencedBlobs(ctx context.Context, before time.Time) ([]*entities.MediaBlob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUnreferencedBlobs", ctx, before)
+	ret0, _ := ret[0].([]*entities.MediaBlob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUnreferencedBlobs indicates an expected call of FindUnreferencedBlobs.
+func (mr *MockMediaRepositoryMockRecorder) FindUnreferencedBlobs(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnreferencedBlobs", reflect.TypeOf((*MockMediaRepository)(nil).FindUnreferencedBlobs), ctx, before)
+}
+
+// IsBlocked mocks base method.
+func (m *MockMediaRepository) IsBlocked(ctx context.Context, sha256 string, phash *uint64, maxDistance int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBlocked", ctx, sha256, phash, maxDistance)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsBlocked indicates an expected call of IsBlocked.
+func (mr *MockMediaRepositoryMockRecorder) IsBlocked(ctx, sha256, phash, maxDistance interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBlocked", reflect.TypeOf((*MockMediaRepository)(nil).IsBlocked), ctx, sha256, phash, maxDistance)
+}
+
+// UnblockFeedMedia mocks base method.
+func (m *MockMediaRepository) UnblockFeedMedia(ctx context.Context, feedID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnblockFeedMedia", ctx, feedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnblockFeedMedia indicates an expected call of UnblockFeedMedia.
+func (mr *MockMediaRepositoryMockRecorder) UnblockFeedMedia(ctx, feedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockFeedMedia", reflect.TypeOf((*MockMediaRepository)(nil).UnblockFeedMedia), ctx, feedID)
+}
@@ -0,0 +1,216 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: C:\Users\gamin\OneDrive\Desktop\sosmed-app\sosmed-golang\internal\repositories\report_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entities "github.com/davidafdal/post-app/internal/entities"
+	cursor "github.com/davidafdal/post-app/pkg/cursor"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockReportRepository is a mock of ReportRepository interface.
+type MockReportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportRepositoryMockRecorder
+}
+
+// MockReportRepositoryMockRecorder is the mock recorder for MockReportRepository.
+type MockReportRepositoryMockRecorder struct {
+	mock *MockReportRepository
+}
+
+// NewMockReportRepository creates a new mock instance.
+func NewMockReportRepository(ctrl *gomock.Controller) *MockReportRepository {
+	mock := &MockReportRepository{ctrl: ctrl}
+	mock.recorder = &MockReportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportRepository) EXPECT() *MockReportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountOpenReports mocks base method.
+func (m *MockReportRepository) CountOpenReports(ctx context.Context, targetType string, targetID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpenReports", ctx, targetType, targetID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpenReports indicates an expected call of CountOpenReports.
+func (mr *MockReportRepositoryMockRecorder) CountOpenReports(ctx, targetType, targetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpenReports", reflect.TypeOf((*MockReportRepository)(nil).CountOpenReports), ctx, targetType, targetID)
+}
+
+// Create mocks base method.
+func (m *MockReportRepository) Create(ctx context.Context, report *entities.Report) (*entities.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, report)
+	ret0, _ := ret[0].(*entities.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReportRepositoryMockRecorder) Create(ctx, report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReportRepository)(nil).Create), ctx, report)
+}
+
+// CreateAuditLog mocks base method.
+func (m *MockReportRepository) CreateAuditLog(ctx context.Context, log *entities.AuditLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditLog", ctx, log)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAuditLog indicates an expected call of CreateAuditLog.
+func (mr *MockReportRepositoryMockRecorder) CreateAuditLog(ctx, log interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditLog", reflect.TypeOf((*MockReportRepository)(nil).CreateAuditLog), ctx, log)
+}
+
+// CreateSystemReport mocks base method.
+func (m *MockReportRepository) CreateSystemReport(ctx context.Context, report *entities.Report) (*entities.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSystemReport", ctx, report)
+	ret0, _ := ret[0].(*entities.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSystemReport indicates an expected call of CreateSystemReport.
+func (mr *MockReportRepositoryMockRecorder) CreateSystemReport(ctx, report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSystemReport", reflect.TypeOf((*MockReportRepository)(nil).CreateSystemReport), ctx, report)
+}
+
+// FindAuditLogs mocks base method.
+func (m *MockReportRepository) FindAuditLogs(ctx context.Context, limit int) ([]*entities.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAuditLogs", ctx, limit)
+	ret0, _ := ret[0].([]*entities.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAuditLogs indicates an expected call of FindAuditLogs.
+func (mr *MockReportRepositoryMockRecorder) FindAuditLogs(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAuditLogs", reflect.TypeOf((*MockReportRepository)(nil).FindAuditLogs), ctx, limit)
+}
+
+// FindByID mocks base method.
+func (m *MockReportRepository) FindByID(ctx context.Context, reportID uuid.UUID) (*entities.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, reportID)
+	ret0, _ := ret[0].(*entities.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockReportRepositoryMockRecorder) FindByID(ctx, reportID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockReportRepository)(nil).FindByID), ctx, reportID)
+}
+
+// FindByStatus mocks base method.
+func (m *MockReportRepository) FindByStatus(ctx context.Context, status string, after *cursor.Cursor, limit int) ([]*entities.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByStatus", ctx, status, after, limit)
+	ret0, _ := ret[0].([]*entities.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByStatus indicates an expected call of FindByStatus.
+func (mr *MockReportRepositoryMockRecorder) FindByStatus(ctx, status, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByStatus", reflect.TypeOf((*MockReportRepository)(nil).FindByStatus), ctx, status, after, limit)
+}
+
+// FindTargetOwner mocks base method.
+func (m *MockReportRepository) FindTargetOwner(ctx context.Context, targetType string, targetID uuid.UUID) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTargetOwner", ctx, targetType, targetID)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTargetOwner indicates an expected call of FindTargetOwner.
+func (mr *MockReportRepositoryMockRecorder) FindTargetOwner(ctx, targetType, targetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTargetOwner", reflect.TypeOf((*MockReportRepository)(nil).FindTargetOwner), ctx, targetType, targetID)
+}
+
+// HideContent mocks base method.
+func (m *MockReportRepository) HideContent(ctx context.Context, targetType string, targetID uuid.UUID, until *time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HideContent", ctx, targetType, targetID, until)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HideContent indicates an expected call of HideContent.
+func (mr *MockReportRepositoryMockRecorder) HideContent(ctx, targetType, targetID, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HideContent", reflect.TypeOf((*MockReportRepository)(nil).HideContent), ctx, targetType, targetID, until)
+}
+
+// RemoveContent mocks base method.
+func (m *MockReportRepository) RemoveContent(ctx context.Context, targetType string, targetID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveContent", ctx, targetType, targetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveContent indicates an expected call of RemoveContent.
+func (mr *MockReportRepositoryMockRecorder) RemoveContent(ctx, targetType, targetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveContent", reflect.TypeOf((*MockReportRepository)(nil).RemoveContent), ctx, targetType, targetID)
+}
+
+// ResolveTarget mocks base method.
+func (m *MockReportRepository) ResolveTarget(ctx context.Context, targetType string, targetID uuid.UUID, status string, moderatorID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveTarget", ctx, targetType, targetID, status, moderatorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveTarget indicates an expected call of ResolveTarget.
+func (mr *MockReportRepositoryMockRecorder) ResolveTarget(ctx, targetType, targetID, status, moderatorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveTarget", reflect.TypeOf((*MockReportRepository)(nil).ResolveTarget), ctx, targetType, targetID, status, moderatorID)
+}
+
+// UnhideContent mocks base method.
+func (m *MockReportRepository) UnhideContent(ctx context.Context, targetType string, targetID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnhideContent", ctx, targetType, targetID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnhideContent indicates an expected call of UnhideContent.
+func (mr *MockReportRepositoryMockRecorder) UnhideContent(ctx, targetType, targetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnhideContent", reflect.TypeOf((*MockReportRepository)(nil).UnhideContent), ctx, targetType, targetID)
+}
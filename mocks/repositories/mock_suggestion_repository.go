@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: C:\Users\gamin\OneDrive\Desktop\sosmed-app\sosmed-golang\internal\repositories\suggestion_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entities "github.com/davidafdal/post-app/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockSuggestionRepository is a mock of SuggestionRepository interface.
+type MockSuggestionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSuggestionRepositoryMockRecorder
+}
+
+// MockSuggestionRepositoryMockRecorder is the mock recorder for MockSuggestionRepository.
+type MockSuggestionRepositoryMockRecorder struct {
+	mock *MockSuggestionRepository
+}
+
+// NewMockSuggestionRepository creates a new mock instance.
+func NewMockSuggestionRepository(ctrl *gomock.Controller) *MockSuggestionRepository {
+	mock := &MockSuggestionRepository{ctrl: ctrl}
+	mock.recorder = &MockSuggestionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSuggestionRepository) EXPECT() *MockSuggestionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindExcludedIDs mocks base method.
+func (m *MockSuggestionRepository) FindExcludedIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExcludedIDs", ctx, userID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExcludedIDs indicates an expected call of FindExcludedIDs.
+func (mr *MockSuggestionRepositoryMockRecorder) FindExcludedIDs(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExcludedIDs", reflect.TypeOf((*MockSuggestionRepository)(nil).FindExcludedIDs), ctx, userID)
+}
+
+// FindFriendsOfFriends mocks base method.
+func (m *MockSuggestionRepository) FindFriendsOfFriends(ctx context.Context, userID uuid.UUID, limit, mutualLimit int) ([]*entities.Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFriendsOfFriends", ctx, userID, limit, mutualLimit)
+	ret0, _ := ret[0].([]*entities.Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFriendsOfFriends indicates an expected call of FindFriendsOfFriends.
+func (mr *MockSuggestionRepositoryMockRecorder) FindFriendsOfFriends(ctx, userID, limit, mutualLimit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFriendsOfFriends", reflect.TypeOf((*MockSuggestionRepository)(nil).FindFriendsOfFriends), ctx, userID, limit, mutualLimit)
+}
+
+// FindUserIDsWithFollowings mocks base method.
+func (m *MockSuggestionRepository) FindUserIDsWithFollowings(ctx context.Context, afterID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserIDsWithFollowings", ctx, afterID, limit)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserIDsWithFollowings indicates an expected call of FindUserIDsWithFollowings.
+func (mr *MockSuggestionRepositoryMockRecorder) FindUserIDsWithFollowings(ctx, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserIDsWithFollowings", reflect.TypeOf((*MockSuggestionRepository)(nil).FindUserIDsWithFollowings), ctx, afterID, limit)
+}
+
+// GetCached mocks base method.
+func (m *MockSuggestionRepository) GetCached(ctx context.Context, userID uuid.UUID) ([]*entities.Suggestion, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCached", ctx, userID)
+	ret0, _ := ret[0].([]*entities.Suggestion)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCached indicates an expected call of GetCached.
+func (mr *MockSuggestionRepositoryMockRecorder) GetCached(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCached", reflect.TypeOf((*MockSuggestionRepository)(nil).GetCached), ctx, userID)
+}
+
+// SaveCached mocks base method.
+func (m *MockSuggestionRepository) SaveCached(ctx context.Context, userID uuid.UUID, suggestions []*entities.Suggestion, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCached", ctx, userID, suggestions, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCached indicates an expected call of SaveCached.
+func (mr *MockSuggestionRepositoryMockRecorder) SaveCached(ctx, userID, suggestions, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCached", reflect.TypeOf((*MockSuggestionRepository)(nil).SaveCached), ctx, userID, suggestions, ttl)
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: C:\Users\gamin\OneDrive\Desktop\sosmed-app\sosmed-golang\internal\repositories\trending_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entities "github.com/davidafdal/post-app/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTrendingRepository is a mock of TrendingRepository interface.
+type MockTrendingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrendingRepositoryMockRecorder
+}
+
+// MockTrendingRepositoryMockRecorder is the mock recorder for MockTrendingRepository.
+type MockTrendingRepositoryMockRecorder struct {
+	mock *MockTrendingRepository
+}
+
+// NewMockTrendingRepository creates a new mock instance.
+func NewMockTrendingRepository(ctrl *gomock.Controller) *MockTrendingRepository {
+	mock := &MockTrendingRepository{ctrl: ctrl}
+	mock.recorder = &MockTrendingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrendingRepository) EXPECT() *MockTrendingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindFeedEngagements mocks base method.
+func (m *MockTrendingRepository) FindFeedEngagements(ctx context.Context, since time.Time) ([]*entities.Engagement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFeedEngagements", ctx, since)
+	ret0, _ := ret[0].([]*entities.Engagement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFeedEngagements indicates an expected call of FindFeedEngagements.
+func (mr *MockTrendingRepositoryMockRecorder) FindFeedEngagements(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFeedEngagements", reflect.TypeOf((*MockTrendingRepository)(nil).FindFeedEngagements), ctx, since)
+}
+
+// FindHashtagEngagements mocks base method.
+func (m *MockTrendingRepository) FindHashtagEngagements(ctx context.Context, since time.Time) ([]*entities.Engagement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindHashtagEngagements", ctx, since)
+	ret0, _ := ret[0].([]*entities.Engagement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindHashtagEngagements indicates an expected call of FindHashtagEngagements.
+func (mr *MockTrendingRepositoryMockRecorder) FindHashtagEngagements(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindHashtagEngagements", reflect.TypeOf((*MockTrendingRepository)(nil).FindHashtagEngagements), ctx, since)
+}
+
+// GetRanking mocks base method.
+func (m *MockTrendingRepository) GetRanking(ctx context.Context, key string, limit int) ([]*entities.RankedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRanking", ctx, key, limit)
+	ret0, _ := ret[0].([]*entities.RankedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRanking indicates an expected call of GetRanking.
+func (mr *MockTrendingRepositoryMockRecorder) GetRanking(ctx, key, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRanking", reflect.TypeOf((*MockTrendingRepository)(nil).GetRanking), ctx, key, limit)
+}
+
+// SaveRanking mocks base method.
+func (m *MockTrendingRepository) SaveRanking(ctx context.Context, key string, items []*entities.RankedItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRanking", ctx, key, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRanking indicates an expected call of SaveRanking.
+func (mr *MockTrendingRepositoryMockRecorder) SaveRanking(ctx, key, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRanking", reflect.TypeOf((*MockTrendingRepository)(nil).SaveRanking), ctx, key, items)
+}
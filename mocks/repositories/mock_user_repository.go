@@ -0,0 +1,520 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: C:\Users\gamin\OneDrive\Desktop\sosmed-app\sosmed-golang\internal\repositories\user_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	entities "github.com/davidafdal/post-app/internal/entities"
+	cursor "github.com/davidafdal/post-app/pkg/cursor"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockUserRepository is a mock of UserRepository interface.
+type MockUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryMockRecorder
+}
+
+// MockUserRepositoryMockRecorder is the mock recorder for MockUserRepository.
+type MockUserRepositoryMockRecorder struct {
+	mock *MockUserRepository
+}
+
+// NewMockUserRepository creates a new mock instance.
+func NewMockUserRepository(ctrl *gomock.Controller) *MockUserRepository {
+	mock := &MockUserRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ApproveAllFollowRequests mocks base method.
+func (m *MockUserRepository) ApproveAllFollowRequests(targetID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveAllFollowRequests", targetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApproveAllFollowRequests indicates an expected call of ApproveAllFollowRequests.
+func (mr *MockUserRepositoryMockRecorder) ApproveAllFollowRequests(targetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveAllFollowRequests", reflect.TypeOf((*MockUserRepository)(nil).ApproveAllFollowRequests), targetID)
+}
+
+// ApproveFollowRequest mocks base method.
+func (m *MockUserRepository) ApproveFollowRequest(requestID, targetID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveFollowRequest", requestID, targetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApproveFollowRequest indicates an expected call of ApproveFollowRequest.
+func (mr *MockUserRepositoryMockRecorder) ApproveFollowRequest(requestID, targetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveFollowRequest", reflect.TypeOf((*MockUserRepository)(nil).ApproveFollowRequest), requestID, targetID)
+}
+
+// Block mocks base method.
+func (m *MockUserRepository) Block(blockerID, blockedID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Block", blockerID, blockedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Block indicates an expected call of Block.
+func (mr *MockUserRepositoryMockRecorder) Block(blockerID, blockedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Block", reflect.TypeOf((*MockUserRepository)(nil).Block), blockerID, blockedID)
+}
+
+// CanView mocks base method.
+func (m *MockUserRepository) CanView(viewerID, ownerID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CanView", viewerID, ownerID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CanView indicates an expected call of CanView.
+func (mr *MockUserRepositoryMockRecorder) CanView(viewerID, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanView", reflect.TypeOf((*MockUserRepository)(nil).CanView), viewerID, ownerID)
+}
+
+// CancelFollowRequest mocks base method.
+func (m *MockUserRepository) CancelFollowRequest(requesterID, targetID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelFollowRequest", requesterID, targetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelFollowRequest indicates an expected call of CancelFollowRequest.
+func (mr *MockUserRepositoryMockRecorder) CancelFollowRequest(requesterID, targetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelFollowRequest", reflect.TypeOf((*MockUserRepository)(nil).CancelFollowRequest), requesterID, targetID)
+}
+
+// Create mocks base method.
+func (m *MockUserRepository) Create(user *entities.User) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", user)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserRepositoryMockRecorder) Create(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepository)(nil).Create), user)
+}
+
+// Delete mocks base method.
+func (m *MockUserRepository) Delete(userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserRepositoryMockRecorder) Delete(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepository)(nil).Delete), userID)
+}
+
+// Find mocks base method.
+func (m *MockUserRepository) Find(search string) ([]*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", search)
+	ret0, _ := ret[0].([]*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockUserRepositoryMockRecorder) Find(search interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockUserRepository)(nil).Find), search)
+}
+
+// FindAccountStatus mocks base method.
+func (m *MockUserRepository) FindAccountStatus(userID uuid.UUID) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAccountStatus", userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindAccountStatus indicates an expected call of FindAccountStatus.
+func (mr *MockUserRepositoryMockRecorder) FindAccountStatus(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAccountStatus", reflect.TypeOf((*MockUserRepository)(nil).FindAccountStatus), userID)
+}
+
+// FindBlockedIDs mocks base method.
+func (m *MockUserRepository) FindBlockedIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBlockedIDs", userID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBlockedIDs indicates an expected call of FindBlockedIDs.
+func (mr *MockUserRepositoryMockRecorder) FindBlockedIDs(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBlockedIDs", reflect.TypeOf((*MockUserRepository)(nil).FindBlockedIDs), userID)
+}
+
+// FindByEmail mocks base method.
+func (m *MockUserRepository) FindByEmail(credentials string) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByEmail", credentials)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByEmail indicates an expected call of FindByEmail.
+func (mr *MockUserRepositoryMockRecorder) FindByEmail(credentials interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockUserRepository)(nil).FindByEmail), credentials)
+}
+
+// FindByID mocks base method.
+func (m *MockUserRepository) FindByID(id uuid.UUID) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", id)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockUserRepositoryMockRecorder) FindByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockUserRepository)(nil).FindByID), id)
+}
+
+// FindByUsername mocks base method.
+func (m *MockUserRepository) FindByUsername(username string) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUsername", username)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUsername indicates an expected call of FindByUsername.
+func (mr *MockUserRepositoryMockRecorder) FindByUsername(username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUsername", reflect.TypeOf((*MockUserRepository)(nil).FindByUsername), username)
+}
+
+// FindFollowRequests mocks base method.
+func (m *MockUserRepository) FindFollowRequests(targetID uuid.UUID) ([]*entities.FollowRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFollowRequests", targetID)
+	ret0, _ := ret[0].([]*entities.FollowRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFollowRequests indicates an expected call of FindFollowRequests.
+func (mr *MockUserRepositoryMockRecorder) FindFollowRequests(targetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFollowRequests", reflect.TypeOf((*MockUserRepository)(nil).FindFollowRequests), targetID)
+}
+
+// FindFollowers mocks base method.
+func (m *MockUserRepository) FindFollowers(userID, viewerID uuid.UUID, after *cursor.Cursor, limit int) ([]*entities.FollowUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFollowers", userID, viewerID, after, limit)
+	ret0, _ := ret[0].([]*entities.FollowUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFollowers indicates an expected call of FindFollowers.
+func (mr *MockUserRepositoryMockRecorder) FindFollowers(userID, viewerID, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFollowers", reflect.TypeOf((*MockUserRepository)(nil).FindFollowers), userID, viewerID, after, limit)
+}
+
+// FindFollowingIDs mocks base method.
+func (m *MockUserRepository) FindFollowingIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFollowingIDs", userID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFollowingIDs indicates an expected call of FindFollowingIDs.
+func (mr *MockUserRepositoryMockRecorder) FindFollowingIDs(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFollowingIDs", reflect.TypeOf((*MockUserRepository)(nil).FindFollowingIDs), userID)
+}
+
+// FindFollowings mocks base method.
+func (m *MockUserRepository) FindFollowings(userID, viewerID uuid.UUID, after *cursor.Cursor, limit int) ([]*entities.FollowUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFollowings", userID, viewerID, after, limit)
+	ret0, _ := ret[0].([]*entities.FollowUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFollowings indicates an expected call of FindFollowings.
+func (mr *MockUserRepositoryMockRecorder) FindFollowings(userID, viewerID, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFollowings", reflect.TypeOf((*MockUserRepository)(nil).FindFollowings), userID, viewerID, after, limit)
+}
+
+// FindMutedIDs mocks base method.
+func (m *MockUserRepository) FindMutedIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMutedIDs", userID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMutedIDs indicates an expected call of FindMutedIDs.
+func (mr *MockUserRepositoryMockRecorder) FindMutedIDs(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMutedIDs", reflect.TypeOf((*MockUserRepository)(nil).FindMutedIDs), userID)
+}
+
+// FindMutuals mocks base method.
+func (m *MockUserRepository) FindMutuals(userID, viewerID uuid.UUID, after *cursor.Cursor, limit int) ([]*entities.FollowUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMutuals", userID, viewerID, after, limit)
+	ret0, _ := ret[0].([]*entities.FollowUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMutuals indicates an expected call of FindMutuals.
+func (mr *MockUserRepositoryMockRecorder) FindMutuals(userID, viewerID, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMutuals", reflect.TypeOf((*MockUserRepository)(nil).FindMutuals), userID, viewerID, after, limit)
+}
+
+// FindPage mocks base method.
+func (m *MockUserRepository) FindPage(search string, after *cursor.Cursor, limit int) ([]*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", search, after, limit)
+	ret0, _ := ret[0].([]*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPage indicates an expected call of FindPage.
+func (mr *MockUserRepositoryMockRecorder) FindPage(search, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockUserRepository)(nil).FindPage), search, after, limit)
+}
+
+// IsBlockedBetween mocks base method.
+func (m *MockUserRepository) IsBlockedBetween(userID, otherID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBlockedBetween", userID, otherID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsBlockedBetween indicates an expected call of IsBlockedBetween.
+func (mr *MockUserRepositoryMockRecorder) IsBlockedBetween(userID, otherID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBlockedBetween", reflect.TypeOf((*MockUserRepository)(nil).IsBlockedBetween), userID, otherID)
+}
+
+// IsFollowing mocks base method.
+func (m *MockUserRepository) IsFollowing(followerID, followingID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsFollowing", followerID, followingID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsFollowing indicates an expected call of IsFollowing.
+func (mr *MockUserRepositoryMockRecorder) IsFollowing(followerID, followingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFollowing", reflect.TypeOf((*MockUserRepository)(nil).IsFollowing), followerID, followingID)
+}
+
+// Mute mocks base method.
+func (m *MockUserRepository) Mute(muterID, mutedID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Mute", muterID, mutedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Mute indicates an expected call of Mute.
+func (mr *MockUserRepositoryMockRecorder) Mute(muterID, mutedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mute", reflect.TypeOf((*MockUserRepository)(nil).Mute), muterID, mutedID)
+}
+
+// RejectFollowRequest mocks base method.
+func (m *MockUserRepository) RejectFollowRequest(requestID, targetID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectFollowRequest", requestID, targetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectFollowRequest indicates an expected call of RejectFollowRequest.
+func (mr *MockUserRepositoryMockRecorder) RejectFollowRequest(requestID, targetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectFollowRequest", reflect.TypeOf((*MockUserRepository)(nil).RejectFollowRequest), requestID, targetID)
+}
+
+// Suspend mocks base method.
+func (m *MockUserRepository) Suspend(userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suspend", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Suspend indicates an expected call of Suspend.
+func (mr *MockUserRepositoryMockRecorder) Suspend(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suspend", reflect.TypeOf((*MockUserRepository)(nil).Suspend), userID)
+}
+
+// ToggleFollow mocks base method.
+func (m *MockUserRepository) ToggleFollow(followerID, followingID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ToggleFollow", followerID, followingID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ToggleFollow indicates an expected call of ToggleFollow.
+func (mr *MockUserRepositoryMockRecorder) ToggleFollow(followerID, followingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToggleFollow", reflect.TypeOf((*MockUserRepository)(nil).ToggleFollow), followerID, followingID)
+}
+
+// ToggleFollowRequest mocks base method.
+func (m *MockUserRepository) ToggleFollowRequest(requesterID, targetID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ToggleFollowRequest", requesterID, targetID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ToggleFollowRequest indicates an expected call of ToggleFollowRequest.
+func (mr *MockUserRepositoryMockRecorder) ToggleFollowRequest(requesterID, targetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToggleFollowRequest", reflect.TypeOf((*MockUserRepository)(nil).ToggleFollowRequest), requesterID, targetID)
+}
+
+// Unblock mocks base method.
+func (m *MockUserRepository) Unblock(blockerID, blockedID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unblock", blockerID, blockedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unblock indicates an expected call of Unblock.
+func (mr *MockUserRepositoryMockRecorder) Unblock(blockerID, blockedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unblock", reflect.TypeOf((*MockUserRepository)(nil).Unblock), blockerID, blockedID)
+}
+
+// Unfollow mocks base method.
+func (m *MockUserRepository) Unfollow(followerID, followingID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfollow", followerID, followingID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unfollow indicates an expected call of Unfollow.
+func (mr *MockUserRepositoryMockRecorder) Unfollow(followerID, followingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockUserRepository)(nil).Unfollow), followerID, followingID)
+}
+
+// Unmute mocks base method.
+func (m *MockUserRepository) Unmute(muterID, mutedID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unmute", muterID, mutedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unmute indicates an expected call of Unmute.
+func (mr *MockUserRepositoryMockRecorder) Unmute(muterID, mutedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unmute", reflect.TypeOf((*MockUserRepository)(nil).Unmute), muterID, mutedID)
+}
+
+// Unsuspend mocks base method.
+func (m *MockUserRepository) Unsuspend(userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsuspend", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsuspend indicates an expected call of Unsuspend.
+func (mr *MockUserRepositoryMockRecorder) Unsuspend(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsuspend", reflect.TypeOf((*MockUserRepository)(nil).Unsuspend), userID)
+}
+
+// Update mocks base method.
+func (m *MockUserRepository) Update(user *entities.User) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", user)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockUserRepositoryMockRecorder) Update(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepository)(nil).Update), user)
+}
+
+// UpdateRole mocks base method.
+func (m *MockUserRepository) UpdateRole(userID uuid.UUID, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockUserRepositoryMockRecorder) UpdateRole(userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserRepository)(nil).UpdateRole), userID, role)
+}
@@ -0,0 +1,190 @@
+package contentfilter
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+type Action string
+
+const (
+	Allow  Action = "allow"
+	Review Action = "review"
+	Reject Action = "reject"
+)
+
+//go:embed rules.json
+var defaultRules []byte
+
+type RuleSet struct {
+	Words    []string `json:"words"`
+	Patterns []string `json:"patterns"`
+}
+
+type LocaleRules struct {
+	Reject RuleSet `json:"reject"`
+	Review RuleSet `json:"review"`
+}
+
+type DomainRules struct {
+	Reject []string `json:"reject"`
+	Review []string `json:"review"`
+}
+
+type Rules struct {
+	Locales map[string]LocaleRules `json:"locales"`
+	Domains DomainRules            `json:"domains"`
+}
+
+// Match is a single rule that matched a text.
+type Match struct {
+	Rule   string
+	Value  string
+	Action Action
+}
+
+// Verdict is the outcome of checking a text. Action is the strictest action of
+// all matches, or Allow when nothing matched.
+type Verdict struct {
+	Action  Action
+	Matches []Match
+}
+
+type Filter interface {
+	Check(text string) Verdict
+}
+
+type rule struct {
+	name   string
+	re     *regexp.Regexp
+	action Action
+}
+
+type domain struct {
+	name   string
+	action Action
+}
+
+type filter struct {
+	rules        []rule
+	domains      []domain
+	holdRejected bool
+}
+
+var urlPattern = regexp.MustCompile(`(?i)\b(?:https?://)?((?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,})(?:[:/?#]\S*)?`)
+
+// DefaultRules returns the denylists shipped with the app.
+func DefaultRules() (*Rules, error) {
+	return parseRules(defaultRules)
+}
+
+// LoadRules reads denylists from a JSON file with the same layout as the
+// embedded rules.json.
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseRules(data)
+}
+
+func parseRules(data []byte) (*Rules, error) {
+	rules := new(Rules)
+	if err := json.Unmarshal(data, rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// New builds a filter from the word and pattern lists of the given locales
+// and the domain lists. Words match whole words case-insensitively. When
+// holdRejected is true, content that would be rejected is held for review
+// instead.
+func New(rules *Rules, locales []string, holdRejected bool) (Filter, error) {
+	f := &filter{holdRejected: holdRejected}
+
+	for _, locale := range locales {
+		localeRules, ok := rules.Locales[locale]
+		if !ok {
+			return nil, fmt.Errorf("contentfilter: no rules for locale %q", locale)
+		}
+
+		if err := f.addRuleSet(locale, localeRules.Reject, Reject); err != nil {
+			return nil, err
+		}
+		if err := f.addRuleSet(locale, localeRules.Review, Review); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, d := range rules.Domains.Reject {
+		f.domains = append(f.domains, domain{name: normalizeHost(d), action: Reject})
+	}
+	for _, d := range rules.Domains.Review {
+		f.domains = append(f.domains, domain{name: normalizeHost(d), action: Review})
+	}
+
+	return f, nil
+}
+
+func (f *filter) addRuleSet(locale string, set RuleSet, action Action) error {
+	for _, word := range set.Words {
+		re, err := regexp.Compile(`(?i)(?:^|[^\p{L}\p{N}_])` + regexp.QuoteMeta(word) + `(?:$|[^\p{L}\p{N}_])`)
+		if err != nil {
+			return err
+		}
+		f.rules = append(f.rules, rule{name: locale + ":word:" + word, re: re, action: action})
+	}
+
+	for _, pattern := range set.Patterns {
+		re, err := regexp.Compile(`(?i)` + pattern)
+		if err != nil {
+			return fmt.Errorf("contentfilter: invalid pattern %q: %w", pattern, err)
+		}
+		f.rules = append(f.rules, rule{name: locale + ":pattern:" + pattern, re: re, action: action})
+	}
+
+	return nil
+}
+
+func (f *filter) Check(text string) Verdict {
+	verdict := Verdict{Action: Allow}
+
+	for _, r := range f.rules {
+		if value := r.re.FindString(text); value != "" {
+			verdict.add(Match{Rule: r.name, Value: strings.TrimSpace(value), Action: r.action})
+		}
+	}
+
+	for _, m := range urlPattern.FindAllStringSubmatch(text, -1) {
+		host := normalizeHost(m[1])
+
+		for _, d := range f.domains {
+			if host == d.name || strings.HasSuffix(host, "."+d.name) {
+				verdict.add(Match{Rule: "domain:" + d.name, Value: m[0], Action: d.action})
+			}
+		}
+	}
+
+	if f.holdRejected && verdict.Action == Reject {
+		verdict.Action = Review
+	}
+
+	return verdict
+}
+
+func (v *Verdict) add(m Match) {
+	v.Matches = append(v.Matches, m)
+
+	if m.Action == Reject || (m.Action == Review && v.Action == Allow) {
+		v.Action = m.Action
+	}
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
@@ -0,0 +1,69 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points at the last item of a page ordered by (CreatedAt, ID)
+// descending; the next page starts right after it. Lists ranked by a score
+// first, such as top comments, also carry the score of that item.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+	Score     int64
+}
+
+func Encode(c Cursor) string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	if c.Score != 0 {
+		raw += "|" + strconv.FormatInt(c.Score, 10)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// Decode parses a cursor from a query string. An empty value means the first
+// page and returns nil.
+func Decode(value string) (*Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 2 && len(parts) != 3 {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	c := &Cursor{CreatedAt: createdAt, ID: id}
+
+	if len(parts) == 3 {
+		c.Score, err = strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	return c, nil
+}
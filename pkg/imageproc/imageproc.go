@@ -0,0 +1,156 @@
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+var ErrUnsupportedFormat = errors.New("image format cannot be processed")
+
+// Variant is a rendition of an uploaded image whose longest side is at most
+// MaxSize pixels. Images are never upscaled.
+type Variant struct {
+	Name    string
+	MaxSize int
+}
+
+// Image is an encoded variant.
+type Image struct {
+	Name        string
+	Data        []byte
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+}
+
+// Result holds the variants of an image, in the order they were configured,
+// its blurhash placeholder and its perceptual hash. Width and Height are those
+// of the upright original.
+type Result struct {
+	Variants []*Image
+	Blurhash string
+	Hash     uint64
+	Width    int
+	Height   int
+}
+
+type Processor struct {
+	variants    []Variant
+	jpegQuality int
+}
+
+func New(variants []Variant, jpegQuality int) *Processor {
+	return &Processor{variants: variants, jpegQuality: jpegQuality}
+}
+
+// CanProcess reports whether images of contentType can be processed. GIF and
+// WebP are stored as uploaded, less their metadata (see StripMetadata):
+// re-encoding would drop GIF animation and the standard library cannot decode
+// WebP.
+func CanProcess(contentType string) bool {
+	return contentType == "image/jpeg" || contentType == "image/png"
+}
+
+// Process decodes an image, turns it upright according to its EXIF
+// orientation and encodes every variant. Variants are encoded from the
+// decoded pixels only, so EXIF, GPS and any other metadata are dropped.
+// Opaque images are encoded as JPEG, images with transparency as PNG.
+func (p *Processor) Process(r io.Reader) (*Result, error) {
+	upright, err := decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := upright.Bounds()
+	result := &Result{
+		Width:    bounds.Dx(),
+		Height:   bounds.Dy(),
+		Blurhash: Blurhash(fit(upright, 32), 4, 3),
+		Hash:     PerceptualHash(upright),
+	}
+
+	encodePNG := !upright.Opaque()
+
+	for _, variant := range p.variants {
+		resized := fit(upright, variant.MaxSize)
+
+		encoded, err := p.encode(resized, encodePNG)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded.Name = variant.Name
+		result.Variants = append(result.Variants, encoded)
+	}
+
+	return result, nil
+}
+
+// decode reads a JPEG or PNG image and turns it upright.
+func decode(r io.Reader) (*image.NRGBA, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	if format != "jpeg" && format != "png" {
+		return nil, ErrUnsupportedFormat
+	}
+
+	upright := toNRGBA(src)
+	if format == "jpeg" {
+		upright = orient(upright, exifOrientation(raw))
+	}
+
+	return upright, nil
+}
+
+func (p *Processor) encode(img *image.NRGBA, asPNG bool) (*Image, error) {
+	buf := &bytes.Buffer{}
+	encoded := &Image{Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+
+	if asPNG {
+		if err := png.Encode(buf, img); err != nil {
+			return nil, err
+		}
+		encoded.ContentType, encoded.Extension = "image/png", ".png"
+	} else {
+		if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: p.jpegQuality}); err != nil {
+			return nil, err
+		}
+		encoded.ContentType, encoded.Extension = "image/jpeg", ".jpg"
+	}
+
+	encoded.Data = buf.Bytes()
+	return encoded, nil
+}
+
+// fit scales img down so its longest side is at most maxSize.
+func fit(img *image.NRGBA, maxSize int) *image.NRGBA {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if maxSize <= 0 || (width <= maxSize && height <= maxSize) {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*maxSize/width)
+		width = maxSize
+	} else {
+		width = max(1, width*maxSize/height)
+		height = maxSize
+	}
+
+	return resize(img, width, height)
+}
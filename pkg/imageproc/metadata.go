@@ -0,0 +1,184 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+var ErrCorruptImage = errors.New("image is corrupt or truncated")
+
+// webpChunks are the WebP chunks that make up the image. Any other chunk,
+// EXIF and XMP included, is metadata.
+var webpChunks = map[string]bool{
+	"VP8 ": true,
+	"VP8L": true,
+	"VP8X": true,
+	"ALPH": true,
+	"ANIM": true,
+	"ANMF": true,
+	"ICCP": true,
+}
+
+// gifApplications are the GIF application extensions kept: they control
+// animation looping. Others, such as XMP, are metadata.
+var gifApplications = map[string]bool{
+	"NETSCAPE2.0": true,
+	"ANIMEXTS1.0": true,
+}
+
+const (
+	webpFlagXMP  = 1 << 2
+	webpFlagEXIF = 1 << 3
+)
+
+// CanStripMetadata reports whether StripMetadata handles images of
+// contentType. These are the images stored as uploaded rather than
+// re-encoded by Process.
+func CanStripMetadata(contentType string) bool {
+	return contentType == "image/gif" || contentType == "image/webp"
+}
+
+// StripMetadata removes the metadata of a GIF or WebP image without
+// re-encoding it, keeping animation. WebP keeps only its image chunks, and GIF
+// drops comments and application extensions other than animation loops.
+func StripMetadata(contentType string, data []byte) ([]byte, error) {
+	switch contentType {
+	case "image/webp":
+		return stripWebP(data)
+	case "image/gif":
+		return stripGIF(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+func stripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, ErrCorruptImage
+	}
+
+	out := append([]byte{}, data[:12]...)
+
+	for rest := data[12:]; len(rest) > 0; {
+		if len(rest) < 8 {
+			return nil, ErrCorruptImage
+		}
+
+		fourCC := string(rest[:4])
+		size := int(binary.LittleEndian.Uint32(rest[4:8]))
+		end := 8 + size + size%2
+		if end > len(rest) {
+			return nil, ErrCorruptImage
+		}
+
+		if webpChunks[fourCC] {
+			chunk := append([]byte{}, rest[:end]...)
+			if fourCC == "VP8X" && size > 0 {
+				chunk[8] &^= webpFlagEXIF | webpFlagXMP
+			}
+			out = append(out, chunk...)
+		}
+
+		rest = rest[end:]
+	}
+
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+
+	return out, nil
+}
+
+func stripGIF(data []byte) ([]byte, error) {
+	if len(data) < 13 || (string(data[:6]) != "GIF87a" && string(data[:6]) != "GIF89a") {
+		return nil, ErrCorruptImage
+	}
+
+	pos := 13 + colorTableSize(data[10])
+	if pos > len(data) {
+		return nil, ErrCorruptImage
+	}
+
+	var out bytes.Buffer
+	out.Write(data[:pos])
+
+	for {
+		if pos >= len(data) {
+			return nil, ErrCorruptImage
+		}
+
+		switch data[pos] {
+		case 0x3B:
+			out.WriteByte(0x3B)
+			return out.Bytes(), nil
+		case 0x2C:
+			if pos+10 > len(data) {
+				return nil, ErrCorruptImage
+			}
+			// The descriptor, its color table and the LZW code size come
+			// before the image data sub-blocks.
+			header := pos + 10 + colorTableSize(data[pos+9]) + 1
+			end, err := skipSubBlocks(data, header)
+			if err != nil {
+				return nil, err
+			}
+			out.Write(data[pos:end])
+			pos = end
+		case 0x21:
+			if pos+2 > len(data) {
+				return nil, ErrCorruptImage
+			}
+			end, err := skipSubBlocks(data, pos+2)
+			if err != nil {
+				return nil, err
+			}
+			if keepGIFExtension(data[pos+1], data[pos+2:end]) {
+				out.Write(data[pos:end])
+			}
+			pos = end
+		default:
+			return nil, ErrCorruptImage
+		}
+	}
+}
+
+// keepGIFExtension reports whether the extension with the given label and
+// sub-blocks is part of the image rather than metadata.
+func keepGIFExtension(label byte, blocks []byte) bool {
+	switch label {
+	case 0xF9, 0x01:
+		// Graphic control and plain text are rendered.
+		return true
+	case 0xFF:
+		return len(blocks) >= 12 && blocks[0] == 11 && gifApplications[string(blocks[1:12])]
+	default:
+		return false
+	}
+}
+
+// colorTableSize returns the size of the color table flagged in the packed
+// byte of a GIF screen or image descriptor.
+func colorTableSize(packed byte) int {
+	if packed&0x80 == 0 {
+		return 0
+	}
+	return 3 << ((packed & 0x07) + 1)
+}
+
+// skipSubBlocks returns the position right after the sub-blocks starting at
+// pos and their terminator.
+func skipSubBlocks(data []byte, pos int) (int, error) {
+	for {
+		if pos >= len(data) {
+			return 0, ErrCorruptImage
+		}
+
+		size := int(data[pos])
+		pos++
+
+		if size == 0 {
+			return pos, nil
+		}
+
+		pos += size
+	}
+}
@@ -0,0 +1,47 @@
+package imageproc
+
+import (
+	"image"
+	"io"
+	"math/bits"
+)
+
+// PerceptualHash is a difference hash of img: the image is shrunk to 9x8
+// grey pixels and each bit tells whether a pixel is brighter than the one on
+// its right. Resized or recompressed copies of an image hash to the same or a
+// nearby value, unlike a hash of the file bytes.
+func PerceptualHash(img *image.NRGBA) uint64 {
+	small := resize(img, 9, 8)
+
+	var hash uint64
+
+	for y := 0; y < 8; y++ {
+		row := small.Pix[y*small.Stride:]
+		for x := 0; x < 8; x++ {
+			if luma(row[x*4:]) > luma(row[(x+1)*4:]) {
+				hash |= 1 << (y*8 + x)
+			}
+		}
+	}
+
+	return hash
+}
+
+// HashImage decodes a JPEG or PNG image and returns its PerceptualHash.
+func HashImage(r io.Reader) (uint64, error) {
+	upright, err := decode(r)
+	if err != nil {
+		return 0, err
+	}
+	return PerceptualHash(upright), nil
+}
+
+// HashDistance is the number of bits two perceptual hashes differ in. Copies
+// of one image are usually within a few bits of each other.
+func HashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+func luma(pixel []uint8) uint32 {
+	return (299*uint32(pixel[0]) + 587*uint32(pixel[1]) + 114*uint32(pixel[2])) / 1000
+}
@@ -0,0 +1,60 @@
+package imageproc
+
+import (
+	"image"
+	"image/draw"
+)
+
+func toNRGBA(src image.Image) *image.NRGBA {
+	if img, ok := src.(*image.NRGBA); ok && img.Rect.Min == (image.Point{}) {
+		return img
+	}
+
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
+
+// resize scales src down to width x height by averaging the source pixels
+// each destination pixel covers. Colours are weighted by alpha so transparent
+// pixels do not darken their neighbours.
+func resize(src *image.NRGBA, width, height int) *image.NRGBA {
+	srcWidth, srcHeight := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max(y0+1, (y+1)*srcHeight/height)
+
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max(x0+1, (x+1)*srcWidth/width)
+
+			var r, g, b, a, n uint64
+
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					pixel := row[sx*4 : sx*4+4]
+					alpha := uint64(pixel[3])
+					r += uint64(pixel[0]) * alpha
+					g += uint64(pixel[1]) * alpha
+					b += uint64(pixel[2]) * alpha
+					a += alpha
+					n++
+				}
+			}
+
+			offset := y*dst.Stride + x*4
+			if a > 0 {
+				dst.Pix[offset] = uint8(r / a)
+				dst.Pix[offset+1] = uint8(g / a)
+				dst.Pix[offset+2] = uint8(b / a)
+			}
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
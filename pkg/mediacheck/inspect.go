@@ -0,0 +1,271 @@
+package mediacheck
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+type Kind string
+
+const (
+	KindImage Kind = "image"
+	KindVideo Kind = "video"
+)
+
+// formats are the content types uploads may have, by the kind they belong to.
+var formats = map[string]Kind{
+	"image/jpeg":      KindImage,
+	"image/png":       KindImage,
+	"image/gif":       KindImage,
+	"image/webp":      KindImage,
+	"video/mp4":       KindVideo,
+	"video/quicktime": KindVideo,
+}
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported file format")
+	ErrCorruptFile       = errors.New("file is corrupt or truncated")
+)
+
+// Info describes an inspected file. Duration is only set for videos.
+type Info struct {
+	ContentType string
+	Kind        Kind
+	Size        int64
+	Width       int
+	Height      int
+	Duration    time.Duration
+}
+
+// Inspect detects the format of r from its content, never from its name or
+// a client supplied type, and reads the dimensions or duration. r is rewound
+// before returning.
+func Inspect(r io.ReadSeeker, size int64) (*Info, error) {
+	contentType, err := DetectType(r)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{ContentType: contentType, Size: size}
+
+	kind, ok := formats[info.ContentType]
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+	info.Kind = kind
+
+	switch {
+	case info.ContentType == "image/webp":
+		info.Width, info.Height, err = webpSize(r)
+	case kind == KindImage:
+		var config image.Config
+		config, _, err = image.DecodeConfig(r)
+		info.Width, info.Height = config.Width, config.Height
+	case kind == KindVideo:
+		info.Duration, info.Width, info.Height, err = mp4Info(r, size)
+	}
+
+	if err != nil {
+		return nil, ErrCorruptFile
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// DetectType returns the content type of r detected from its first bytes and
+// rewinds r.
+func DetectType(r io.ReadSeeker) (string, error) {
+	mime, err := mimetype.DetectReader(r)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	return mime.String(), nil
+}
+
+// webpSize reads the canvas size from the header of a lossy, lossless or
+// extended WebP file.
+func webpSize(r io.Reader) (int, int, error) {
+	header := make([]byte, 30)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, 0, err
+	}
+
+	switch string(header[12:16]) {
+	case "VP8X":
+		width := int(header[24]) | int(header[25])<<8 | int(header[26])<<16
+		height := int(header[27]) | int(header[28])<<8 | int(header[29])<<16
+		return width + 1, height + 1, nil
+	case "VP8 ":
+		if header[23] != 0x9d || header[24] != 0x01 || header[25] != 0x2a {
+			return 0, 0, ErrCorruptFile
+		}
+		width := int(binary.LittleEndian.Uint16(header[26:28]) & 0x3fff)
+		height := int(binary.LittleEndian.Uint16(header[28:30]) & 0x3fff)
+		return width, height, nil
+	case "VP8L":
+		if header[20] != 0x2f {
+			return 0, 0, ErrCorruptFile
+		}
+		bits := binary.LittleEndian.Uint32(header[21:25])
+		return int(bits&0x3fff) + 1, int(bits>>14&0x3fff) + 1, nil
+	default:
+		return 0, 0, ErrCorruptFile
+	}
+}
+
+// mp4Info reads the duration from the movie header (moov/mvhd) of an MP4 or
+// QuickTime file, and the display size of its first video track.
+func mp4Info(r io.ReadSeeker, size int64) (time.Duration, int, int, error) {
+	moov, moovSize, err := findBox(r, 0, size, "moov")
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	mvhd, _, err := findBox(r, moov, moov+moovSize, "mvhd")
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	header, err := readAt(r, mvhd, 32)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	var timescale, duration uint64
+	if header[0] == 1 {
+		timescale = uint64(binary.BigEndian.Uint32(header[20:24]))
+		duration = binary.BigEndian.Uint64(header[24:32])
+	} else {
+		timescale = uint64(binary.BigEndian.Uint32(header[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(header[16:20]))
+	}
+
+	if timescale == 0 {
+		return 0, 0, 0, ErrCorruptFile
+	}
+
+	width, height := mp4VideoSize(r, moov, moov+moovSize)
+
+	return time.Duration(duration * uint64(time.Second) / timescale), width, height, nil
+}
+
+// mp4VideoSize returns the size of the first track with a picture, taken from
+// its track header and swapped when the track is rotated by 90 degrees. It
+// returns zeros when no track has one.
+func mp4VideoSize(r io.ReadSeeker, start, end int64) (int, int) {
+	for start < end {
+		trak, trakSize, err := findBox(r, start, end, "trak")
+		if err != nil {
+			return 0, 0
+		}
+		start = trak + trakSize
+
+		tkhd, tkhdSize, err := findBox(r, trak, trak+trakSize, "tkhd")
+		if err != nil || tkhdSize < 84 || tkhdSize > 1024 {
+			continue
+		}
+
+		header, err := readAt(r, tkhd, int(tkhdSize))
+		if err != nil {
+			continue
+		}
+
+		// The matrix and size follow the fields whose width depends on the
+		// header version.
+		offset := 40
+		if header[0] == 1 {
+			offset = 52
+		}
+
+		if len(header) < offset+44 {
+			continue
+		}
+
+		matrix := header[offset : offset+36]
+		width := int(binary.BigEndian.Uint32(header[offset+36:offset+40]) >> 16)
+		height := int(binary.BigEndian.Uint32(header[offset+40:offset+44]) >> 16)
+
+		if width == 0 || height == 0 {
+			continue
+		}
+
+		if binary.BigEndian.Uint32(matrix[0:4]) == 0 && binary.BigEndian.Uint32(matrix[4:8]) != 0 {
+			width, height = height, width
+		}
+
+		return width, height
+	}
+
+	return 0, 0
+}
+
+func readAt(r io.ReadSeeker, offset int64, n int) ([]byte, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// findBox looks for a box named name among the boxes between start and end
+// and returns the offset and size of its content.
+func findBox(r io.ReadSeeker, start, end int64, name string) (int64, int64, error) {
+	header := make([]byte, 16)
+
+	for offset := start; offset+8 <= end; {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return 0, 0, err
+		}
+		if _, err := io.ReadFull(r, header[:8]); err != nil {
+			return 0, 0, err
+		}
+
+		boxSize := int64(binary.BigEndian.Uint32(header[:4]))
+		headerSize := int64(8)
+
+		switch boxSize {
+		case 0:
+			boxSize = end - offset
+		case 1:
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return 0, 0, err
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+
+		if boxSize < headerSize || offset+boxSize > end {
+			return 0, 0, ErrCorruptFile
+		}
+
+		if string(header[4:8]) == name {
+			return offset + headerSize, boxSize - headerSize, nil
+		}
+
+		offset += boxSize
+	}
+
+	return 0, 0, ErrCorruptFile
+}
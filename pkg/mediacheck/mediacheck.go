@@ -0,0 +1,167 @@
+package mediacheck
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strings"
+	"time"
+)
+
+// Limits bound what may be uploaded. Zero values disable a limit.
+type Limits struct {
+	MaxFiles         int
+	MaxImageSize     int64
+	MaxVideoSize     int64
+	MaxImageWidth    int
+	MaxImageHeight   int
+	MaxVideoDuration time.Duration
+}
+
+// FileError tells which uploaded file was rejected and why. Index is the
+// position of the file in the upload.
+type FileError struct {
+	Index    int    `json:"index"`
+	Filename string `json:"filename"`
+	Reason   string `json:"reason"`
+}
+
+// ValidationError lists every rejected file of an upload.
+type ValidationError struct {
+	Message string
+	Files   []FileError
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Files) == 0 {
+		return e.Message
+	}
+
+	reasons := make([]string, len(e.Files))
+	for i, file := range e.Files {
+		reasons[i] = file.Filename + ": " + file.Reason
+	}
+
+	return e.Message + ": " + strings.Join(reasons, "; ")
+}
+
+// File is an uploaded file to check, such as a multipart file or a finished
+// resumable upload.
+type File interface {
+	Name() string
+	Size() int64
+	Open() (io.ReadSeekCloser, error)
+}
+
+type multipartFile struct {
+	*multipart.FileHeader
+}
+
+func (f multipartFile) Name() string { return f.Filename }
+
+func (f multipartFile) Size() int64 { return f.FileHeader.Size }
+
+func (f multipartFile) Open() (io.ReadSeekCloser, error) { return f.FileHeader.Open() }
+
+// Multipart adapts multipart files for CheckFiles.
+func Multipart(files []*multipart.FileHeader) []File {
+	adapted := make([]File, len(files))
+	for i, file := range files {
+		adapted[i] = multipartFile{file}
+	}
+	return adapted
+}
+
+type Validator struct {
+	limits Limits
+}
+
+func New(limits Limits) *Validator {
+	return &Validator{limits: limits}
+}
+
+// Check inspects every file and returns their info in order. Files of a kind
+// not listed in kinds, or over the limits, are reported together in a
+// *ValidationError.
+func (v *Validator) Check(files []*multipart.FileHeader, kinds ...Kind) ([]*Info, error) {
+	return v.CheckFiles(Multipart(files), kinds...)
+}
+
+// CheckFiles is Check for any kind of File.
+func (v *Validator) CheckFiles(files []File, kinds ...Kind) ([]*Info, error) {
+	if v.limits.MaxFiles > 0 && len(files) > v.limits.MaxFiles {
+		return nil, &ValidationError{Message: fmt.Sprintf("too many files, at most %d are allowed", v.limits.MaxFiles)}
+	}
+
+	infos := make([]*Info, len(files))
+	var rejected []FileError
+
+	for i, file := range files {
+		info, err := v.checkFile(file, kinds)
+		if err != nil {
+			rejected = append(rejected, FileError{Index: i, Filename: file.Name(), Reason: err.Error()})
+			continue
+		}
+		infos[i] = info
+	}
+
+	if len(rejected) > 0 {
+		return nil, &ValidationError{Message: "invalid files", Files: rejected}
+	}
+
+	return infos, nil
+}
+
+func (v *Validator) checkFile(f File, kinds []Kind) (*Info, error) {
+	file, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := Inspect(file, f.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	if !allowed(info.Kind, kinds) {
+		return nil, fmt.Errorf("%s files are not allowed here", info.Kind)
+	}
+
+	switch info.Kind {
+	case KindImage:
+		if v.limits.MaxImageSize > 0 && info.Size > v.limits.MaxImageSize {
+			return nil, fmt.Errorf("image is larger than %s", formatBytes(v.limits.MaxImageSize))
+		}
+		if (v.limits.MaxImageWidth > 0 && info.Width > v.limits.MaxImageWidth) ||
+			(v.limits.MaxImageHeight > 0 && info.Height > v.limits.MaxImageHeight) {
+			return nil, fmt.Errorf("image is %dx%d, at most %dx%d is allowed", info.Width, info.Height, v.limits.MaxImageWidth, v.limits.MaxImageHeight)
+		}
+	case KindVideo:
+		if v.limits.MaxVideoSize > 0 && info.Size > v.limits.MaxVideoSize {
+			return nil, fmt.Errorf("video is larger than %s", formatBytes(v.limits.MaxVideoSize))
+		}
+		if v.limits.MaxVideoDuration > 0 && info.Duration > v.limits.MaxVideoDuration {
+			return nil, fmt.Errorf("video is longer than %s", v.limits.MaxVideoDuration)
+		}
+	}
+
+	return info, nil
+}
+
+func allowed(kind Kind, kinds []Kind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func formatBytes(size int64) string {
+	const mb = 1 << 20
+	if size%mb == 0 {
+		return fmt.Sprintf("%d MB", size/mb)
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
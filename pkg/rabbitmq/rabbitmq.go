@@ -35,6 +35,13 @@ func NewClient(cfg *config.RabbitConfig) (*Client, error) {
 	}, nil
 }
 
+// DeclareQueue makes sure a durable queue exists, so messages published to it
+// through the default exchange are kept until a consumer reads them.
+func (r *Client) DeclareQueue(queue string) error {
+	_, err := r.Channel.QueueDeclare(queue, true, false, false, false, nil)
+	return err
+}
+
 func (r *Client) Consume(queue string) (<-chan amqp.Delivery, error) {
 	return r.Channel.Consume(
 		queue, "", true, false, false, false, nil,
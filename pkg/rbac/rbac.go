@@ -0,0 +1,53 @@
+package rbac
+
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+type Permission string
+
+const (
+	// PermModerateContent covers the report queue, moderator actions and the
+	// audit log.
+	PermModerateContent Permission = "moderate_content"
+	PermSuspendUsers    Permission = "suspend_users"
+	PermManageUsers     Permission = "manage_users"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleUser:      {},
+	RoleModerator: {PermModerateContent, PermSuspendUsers},
+	RoleAdmin:     {PermModerateContent, PermSuspendUsers, PermManageUsers},
+}
+
+// rank orders roles so that a user can only act on accounts below their own.
+var rank = map[Role]int{
+	RoleUser:      0,
+	RoleModerator: 1,
+	RoleAdmin:     2,
+}
+
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[Role(role)]
+	return ok
+}
+
+// Can reports whether the role is granted the permission. Unknown roles are
+// treated as regular users.
+func Can(role string, permission Permission) bool {
+	for _, p := range rolePermissions[Role(role)] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Outranks reports whether role is strictly higher than other.
+func Outranks(role, other string) bool {
+	return rank[Role(role)] > rank[Role(other)]
+}
@@ -0,0 +1,22 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/davidafdal/post-app/config"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitRedis(cfg *config.RedisConfig) (*redis.Client, error) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		return nil, err
+	}
+
+	return rdb, nil
+}
@@ -23,3 +23,11 @@ func ErrorResponse(c echo.Context, code int, message string) error {
 		Data:    nil,
 	})
 }
+
+func ErrorResponseWithData(c echo.Context, code int, message string, data interface{}) error {
+	return c.JSON(code, ApiResponse{
+		Success: false,
+		Message: message,
+		Data:    data,
+	})
+}
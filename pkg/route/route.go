@@ -1,9 +1,16 @@
 package route
 
-import "github.com/labstack/echo/v4"
+import (
+	"github.com/davidafdal/post-app/pkg/rbac"
+	"github.com/labstack/echo/v4"
+)
 
 type Route struct {
-	Method  string
-	Path    string
-	Handler echo.HandlerFunc
+	Method     string
+	Path       string
+	Handler    echo.HandlerFunc
+	Permission rbac.Permission
+	// QueryToken also accepts the JWT from the token query parameter, for
+	// clients that cannot set headers such as browser websockets.
+	QueryToken bool
 }
@@ -8,6 +8,7 @@ import (
 	"os/signal"
 	"time"
 
+	"github.com/davidafdal/post-app/pkg/rbac"
 	"github.com/davidafdal/post-app/pkg/response"
 	"github.com/davidafdal/post-app/pkg/route"
 	"github.com/davidafdal/post-app/pkg/token"
@@ -21,10 +22,17 @@ type Server struct {
 	*echo.Echo
 }
 
-func NewServer(publicRoutes, privateRoutes []*route.Route, secretKey string, tokenUse token.TokenUseCase) *Server {
+// AccountCheck returns the current role of the account with the given id and
+// whether it is suspended.
+type AccountCheck func(ctx context.Context, userID string) (role string, suspended bool, err error)
+
+func NewServer(publicRoutes, privateRoutes []*route.Route, secretKey string, tokenUse token.TokenUseCase, checkAccount AccountCheck) *Server {
 	e := echo.New()
 
-	e.Use(middleware.CORS())
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		// Browsers only let tus clients read these headers when exposed.
+		ExposeHeaders: []string{"Location", "Upload-Offset", "Upload-Length", "Tus-Resumable", "Tus-Version", "Tus-Extension", "Tus-Max-Size"},
+	}))
 
 	e.GET("/", func(c echo.Context) error {
 		return response.SuccessResponse(c, http.StatusOK, "Hello, World!", nil)
@@ -40,13 +48,30 @@ func NewServer(publicRoutes, privateRoutes []*route.Route, secretKey string, tok
 
 	if len(privateRoutes) > 0 {
 		for _, v := range privateRoutes {
-			v1.Add(v.Method, v.Path, v.Handler, JWTProtection(secretKey), UserContextMiddelware())
+			jwtMiddleware := JWTProtection(secretKey)
+			if v.QueryToken {
+				jwtMiddleware = QueryJWTProtection(secretKey)
+			}
+
+			middlewares := []echo.MiddlewareFunc{jwtMiddleware, UserContextMiddelware(checkAccount)}
+
+			if v.Permission != "" {
+				middlewares = append(middlewares, PermissionMiddleware(v.Permission))
+			}
+
+			v1.Add(v.Method, v.Path, v.Handler, middlewares...)
 		}
 	}
 
 	return &Server{e}
 }
 
+// Mount serves h under prefix, outside the API group and without
+// authentication. h sees paths relative to prefix.
+func (s *Server) Mount(prefix string, h http.Handler) {
+	s.Match([]string{http.MethodGet, http.MethodHead}, prefix+"/*", echo.WrapHandler(http.StripPrefix(prefix, h)))
+}
+
 func (s *Server) Run() {
 	runServer(s)
 	gracefulShutdown(s)
@@ -76,7 +101,10 @@ func gracefulShutdown(srv *Server) {
 	}()
 }
 
-func UserContextMiddelware() echo.MiddlewareFunc {
+// UserContextMiddelware puts the user of the token in the context. The role is
+// read from the database rather than the token, so role changes apply to
+// tokens issued before them.
+func UserContextMiddelware(checkAccount AccountCheck) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 
@@ -87,8 +115,38 @@ func UserContextMiddelware() echo.MiddlewareFunc {
 
 			claims := user.Claims.(*token.JwtCustomClaims)
 
+			role, suspended, err := checkAccount(c.Request().Context(), claims.ID)
+			if err != nil {
+				return response.ErrorResponse(c, http.StatusUnauthorized, "anda harus login untuk mengakses resource ini")
+			}
+
+			if suspended {
+				return response.ErrorResponse(c, http.StatusForbidden, "akun anda telah ditangguhkan")
+			}
+
+			if role == "" {
+				role = string(rbac.RoleUser)
+			}
+
 			c.Set("user_id", claims.ID)
 			c.Set("user_email", claims.Email)
+			c.Set("user_role", role)
+
+			return next(c)
+		}
+	}
+}
+
+// PermissionMiddleware rejects users whose role is not granted the permission.
+// It must run after UserContextMiddelware.
+func PermissionMiddleware(permission rbac.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, _ := c.Get("user_role").(string)
+
+			if !rbac.Can(role, permission) {
+				return response.ErrorResponse(c, http.StatusForbidden, "anda tidak memiliki akses ke resource ini")
+			}
 
 			return next(c)
 		}
@@ -96,11 +154,23 @@ func UserContextMiddelware() echo.MiddlewareFunc {
 }
 
 func JWTProtection(secretKey string) echo.MiddlewareFunc {
+	return jwtProtection(secretKey, "header:Authorization:Bearer ")
+}
+
+// QueryJWTProtection is JWTProtection that also reads the token from the
+// token query parameter. Browsers cannot set headers on websocket requests;
+// other routes must not use it, as query strings end up in logs.
+func QueryJWTProtection(secretKey string) echo.MiddlewareFunc {
+	return jwtProtection(secretKey, "header:Authorization:Bearer ,query:token")
+}
+
+func jwtProtection(secretKey, tokenLookup string) echo.MiddlewareFunc {
 	return echojwt.WithConfig(echojwt.Config{
 		NewClaimsFunc: func(c echo.Context) jwt.Claims {
 			return new(token.JwtCustomClaims)
 		},
-		SigningKey: []byte(secretKey),
+		SigningKey:  []byte(secretKey),
+		TokenLookup: tokenLookup,
 		ErrorHandler: func(c echo.Context, err error) error {
 			return response.ErrorResponse(c, http.StatusUnauthorized, "anda harus login untuk mengakses resource ini")
 		},
@@ -1,9 +1,17 @@
 package socket
 
 import (
+	"time"
+
 	"github.com/gorilla/websocket"
 )
 
+const (
+	writeWait  = 10 * time.Second
+	pingPeriod = 30 * time.Second
+	pongWait   = 2 * pingPeriod
+)
+
 type Connection struct {
 	ws   *websocket.Conn
 	send chan []byte
@@ -24,18 +32,48 @@ func (c *Connection) Send(msg []byte) {
 	}
 }
 
-// func (c *Connection) writePump() {
-// 	ticker := time.NewTicker(time.Second * 50)
+// readPump keeps the connection alive until the client goes away. Anything
+// the client sends is ignored.
+func (c *Connection) readPump() {
+	c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		if _, _, err := c.ws.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
 
-// 	defer func() {
-// 		ticker.Stop()
-// 		c.ws.Close()
-// 	}()
+func (c *Connection) writePump() {
+	ticker := time.NewTicker(pingPeriod)
 
-// 	for {
-// 		select {
-// 		// case msg, ok := <- c.send:
+	defer func() {
+		ticker.Stop()
+		c.ws.Close()
+	}()
 
-// 		// }
-// 	}
-// }
+	for {
+		select {
+		case msg, ok := <-c.send:
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+
+			if !ok {
+				c.ws.WriteMessage(websocket.CloseMessage, nil)
+				return
+			}
+
+			if err := c.ws.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+
+			if err := c.ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
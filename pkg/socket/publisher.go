@@ -0,0 +1,52 @@
+package socket
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// channel is the Redis channel messages travel on to the hubs.
+const channel = "socket:events"
+
+// Event is a message pushed to the websocket connections of a user.
+type Event struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// Publisher sends events to the open connections of a user, on whichever
+// instance they are connected.
+type Publisher interface {
+	Publish(ctx context.Context, userID string, event Event) error
+}
+
+type envelope struct {
+	UserID string          `json:"user_id"`
+	Event  json.RawMessage `json:"event"`
+}
+
+type redisPublisher struct {
+	rdb *redis.Client
+}
+
+// NewPublisher returns a Publisher for any process, such as the worker. Hubs
+// receive its events in Run.
+func NewPublisher(rdb *redis.Client) Publisher {
+	return &redisPublisher{rdb: rdb}
+}
+
+func (p *redisPublisher) Publish(ctx context.Context, userID string, event Event) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(envelope{UserID: userID, Event: raw})
+	if err != nil {
+		return err
+	}
+
+	return p.rdb.Publish(ctx, channel, body).Err()
+}
@@ -1,8 +1,12 @@
 package socket
 
 import (
+	"context"
+	"encoding/json"
+	"log"
 	"sync"
 
+	"github.com/gorilla/websocket"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -11,6 +15,9 @@ type Client struct {
 	Send   chan []byte
 }
 
+// Hub keeps the websocket connections of this process, by user. Messages
+// reach it through Redis, so a user is reached whichever instance they are
+// connected to.
 type Hub struct {
 	mu      sync.RWMutex
 	clients map[string]map[*Connection]bool
@@ -24,10 +31,49 @@ func NewHub(rdb *redis.Client) *Hub {
 	}
 }
 
+// Serve delivers the messages of userID to ws until the client disconnects.
+func (h *Hub) Serve(userID string, ws *websocket.Conn) {
+	conn := NewConnection(ws)
+
+	h.Register(userID, conn)
+	go conn.writePump()
+
+	conn.readPump()
+	h.Unregister(userID, conn)
+}
+
+// Run relays the messages published through Redis to the connections of this
+// hub until ctx is cancelled.
+func (h *Hub) Run(ctx context.Context) {
+	sub := h.rdb.Subscribe(ctx, channel)
+	defer sub.Close()
+
+	messages := sub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			var env envelope
+			if err := json.Unmarshal([]byte(message.Payload), &env); err != nil {
+				log.Printf("invalid socket message: %v", err)
+				continue
+			}
+
+			h.SendToUser(env.UserID, env.Event)
+		}
+	}
+}
+
 func (h *Hub) Register(userID string, conn *Connection) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	if _, ok := h.clients[userID]; ok {
+	if _, ok := h.clients[userID]; !ok {
 		h.clients[userID] = make(map[*Connection]bool)
 	}
 	h.clients[userID][conn] = true
@@ -37,6 +83,9 @@ func (h *Hub) Unregister(userID string, conn *Connection) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	if conns, ok := h.clients[userID]; ok {
+		if conns[conn] {
+			close(conn.send)
+		}
 		delete(conns, conn)
 		if len(conns) == 0 {
 			delete(h.clients, userID)
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/cloudinary/cloudinary-go/v2/api/admin"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+)
+
+// cloudinaryResourceTypes are the resource types an object can be stored as.
+// Cloudinary needs the right one to find an asset again.
+var cloudinaryResourceTypes = []string{"image", "video", "raw"}
+
+// Cloudinary stores objects as Cloudinary assets. The key without its
+// extension is used as the public ID.
+type Cloudinary struct {
+	cld *cloudinary.Cloudinary
+}
+
+func NewCloudinary(cloudinaryURL string) (*Cloudinary, error) {
+	cld, err := cloudinary.NewFromURL(cloudinaryURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cloudinary{cld: cld}, nil
+}
+
+func (c *Cloudinary) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (*Object, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.cld.Upload.Upload(ctx, body, uploader.UploadParams{
+		PublicID:     publicID(key),
+		ResourceType: "auto",
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Error.Message != "" {
+		return nil, fmt.Errorf("cloudinary upload: %s", result.Error.Message)
+	}
+
+	return &Object{
+		Key:         key,
+		URL:         result.SecureURL,
+		Size:        int64(result.Bytes),
+		ContentType: contentType,
+		ModTime:     result.CreatedAt,
+	}, nil
+}
+
+func (c *Cloudinary) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	for _, resourceType := range cloudinaryResourceTypes {
+		result, err := c.cld.Upload.Destroy(ctx, uploader.DestroyParams{
+			PublicID:     publicID(key),
+			ResourceType: resourceType,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if result.Result == "ok" {
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+// SignedURL returns a signed delivery URL. Cloudinary signatures protect the
+// URL against tampering but do not expire, so expires is ignored.
+func (c *Cloudinary) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	object, err := c.Stat(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	asset, err := c.cld.Media(publicID(object.Key))
+	if err != nil {
+		return "", err
+	}
+
+	asset.AssetType = api.AssetType(strings.SplitN(object.ContentType, "/", 2)[0])
+	asset.Config.URL.SignURL = true
+	asset.Config.URL.Secure = true
+
+	return asset.String()
+}
+
+func (c *Cloudinary) Stat(ctx context.Context, key string) (*Object, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resourceType := range cloudinaryResourceTypes {
+		result, err := c.cld.Admin.Asset(ctx, admin.AssetParams{
+			AssetType: api.AssetType(resourceType),
+			PublicID:  publicID(key),
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		if result.Error.Message != "" {
+			continue
+		}
+
+		return &Object{
+			Key:         key,
+			URL:         result.SecureURL,
+			Size:        int64(result.Bytes),
+			ContentType: resourceType + "/" + result.Format,
+			ModTime:     result.CreatedAt,
+		}, nil
+	}
+
+	return nil, ErrNotFound
+}
+
+func (c *Cloudinary) List(ctx context.Context, prefix string, fn func(*Object) error) error {
+	for _, resourceType := range cloudinaryResourceTypes {
+		params := admin.AssetsParams{
+			AssetType:    api.AssetType(resourceType),
+			DeliveryType: "upload",
+			Prefix:       prefix,
+			MaxResults:   500,
+		}
+
+		for {
+			result, err := c.cld.Admin.Assets(ctx, params)
+			if err != nil {
+				return err
+			}
+
+			if result.Error.Message != "" {
+				return fmt.Errorf("cloudinary list: %s", result.Error.Message)
+			}
+
+			for _, asset := range result.Assets {
+				// Raw assets keep their extension in the public ID.
+				key := asset.PublicID
+				if asset.Format != "" {
+					key += "." + asset.Format
+				}
+
+				err := fn(&Object{
+					Key:         key,
+					URL:         asset.SecureURL,
+					Size:        int64(asset.Bytes),
+					ContentType: resourceType + "/" + asset.Format,
+					ModTime:     asset.CreatedAt,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			if result.NextCursor == "" {
+				break
+			}
+			params.NextCursor = result.NextCursor
+		}
+	}
+
+	return nil
+}
+
+// KeyFromURL extracts the key from a delivery URL such as
+// https://res.cloudinary.com/<cloud>/image/upload/v123/<public id>.jpg.
+func (c *Cloudinary) KeyFromURL(rawURL string) (string, error) {
+	parts := strings.Split(rawURL, "/upload/")
+	if len(parts) != 2 {
+		return "", ErrInvalidURL
+	}
+
+	pathParts := strings.SplitN(parts[1], "/", 2)
+	if len(pathParts) < 2 {
+		return "", ErrInvalidURL
+	}
+
+	return cleanKey(pathParts[1])
+}
+
+func publicID(key string) string {
+	return strings.TrimSuffix(key, path.Ext(filepath.ToSlash(key)))
+}
@@ -0,0 +1,241 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Local stores objects on the local filesystem under root. The API serves
+// them under baseURL through Handler.
+type Local struct {
+	root       string
+	baseURL    string
+	signingKey []byte
+}
+
+func NewLocal(root, baseURL, signingKey string) (*Local, error) {
+	if signingKey == "" {
+		return nil, ErrNoSigningKey
+	}
+
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &Local{
+		root:       root,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		signingKey: []byte(signingKey),
+	}, nil
+}
+
+func (l *Local) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (*Object, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	fullPath := l.path(key)
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return nil, err
+	}
+
+	dst, err := os.Create(fullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	written, err := io.Copy(dst, body)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(fullPath)
+		return nil, err
+	}
+
+	return &Object{
+		Key:         key,
+		URL:         l.url(key),
+		Size:        written,
+		ContentType: contentType,
+		ModTime:     time.Now(),
+	}, nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(l.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// SignedURL returns a URL that Handler only serves until it expires.
+func (l *Local) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	expiresAt := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expiresAt)
+	query.Set("signature", l.sign(key, expiresAt))
+
+	return l.url(key) + "?" + query.Encode(), nil
+}
+
+func (l *Local) Stat(ctx context.Context, key string) (*Object, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(l.path(key))
+	if errors.Is(err, os.ErrNotExist) || (err == nil && info.IsDir()) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Object{
+		Key:         key,
+		URL:         l.url(key),
+		Size:        info.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(key)),
+		ModTime:     info.ModTime(),
+	}, nil
+}
+
+func (l *Local) List(ctx context.Context, prefix string, fn func(*Object) error) error {
+	err := filepath.WalkDir(l.root, func(fullPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(l.root, fullPath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+
+		if entry.IsDir() {
+			// Skip folders that cannot hold a key with the prefix.
+			if key != "." && !strings.HasPrefix(key+"/", prefix) && !strings.HasPrefix(prefix, key+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		return fn(&Object{
+			Key:         key,
+			URL:         l.url(key),
+			Size:        info.Size(),
+			ContentType: mime.TypeByExtension(filepath.Ext(key)),
+			ModTime:     info.ModTime(),
+		})
+	})
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (l *Local) KeyFromURL(rawURL string) (string, error) {
+	if !strings.HasPrefix(rawURL, l.baseURL+"/") {
+		return "", ErrInvalidURL
+	}
+
+	key := strings.TrimPrefix(rawURL, l.baseURL+"/")
+	if i := strings.IndexByte(key, '?'); i >= 0 {
+		key = key[:i]
+	}
+
+	return cleanKey(key)
+}
+
+// Handler serves stored objects by key, relative to the path it is mounted
+// on. Requests for a signed URL, carrying a signature or an expiry, are only
+// served while the signature matches and has not expired.
+func (l *Local) Handler() http.Handler {
+	files := http.FileServer(http.Dir(l.root))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		if query.Has("signature") || query.Has("expires") {
+			key, err := cleanKey(r.URL.Path)
+			if err != nil || !l.validSignature(key, query.Get("expires"), query.Get("signature")) {
+				http.Error(w, "invalid or expired signature", http.StatusForbidden)
+				return
+			}
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		files.ServeHTTP(w, r)
+	})
+}
+
+func (l *Local) validSignature(key, expiresAt, signature string) bool {
+	expires, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(l.sign(key, expiresAt)))
+}
+
+func (l *Local) sign(key, expiresAt string) string {
+	mac := hmac.New(sha256.New, l.signingKey)
+	mac.Write([]byte(key + "|" + expiresAt))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (l *Local) path(key string) string {
+	return filepath.Join(l.root, filepath.FromSlash(key))
+}
+
+func (l *Local) url(key string) string {
+	return l.baseURL + "/" + key
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotFound     = errors.New("object not found")
+	ErrInvalidKey   = errors.New("invalid object key")
+	ErrInvalidURL   = errors.New("url does not belong to this storage")
+	ErrNoSigningKey = errors.New("a signing key is required")
+)
+
+// Object describes a stored file. URL is the public address of the object.
+type Object struct {
+	Key         string
+	URL         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Storage is an object store. Keys are slash separated paths such as
+// "feeds/<feed id>/<file>"; the stored URL of an object can be turned back
+// into its key with KeyFromURL.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (*Object, error)
+	Delete(ctx context.Context, key string) error
+	SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+	Stat(ctx context.Context, key string) (*Object, error)
+	// List calls fn with every object whose key starts with prefix, stopping
+	// at the first error fn returns.
+	List(ctx context.Context, prefix string, fn func(*Object) error) error
+	KeyFromURL(url string) (string, error)
+}
+
+// NewKey returns a unique key under prefix that keeps the extension of
+// filename.
+func NewKey(prefix, filename string) string {
+	return path.Join(prefix, uuid.New().String()+strings.ToLower(filepath.Ext(filename)))
+}
+
+// PutFile stores an uploaded multipart file under prefix. contentType should
+// come from the file content rather than the client.
+func PutFile(ctx context.Context, s Storage, prefix string, fileHeader *multipart.FileHeader, contentType string) (*Object, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return s.Put(ctx, NewKey(prefix, fileHeader.Filename), file, fileHeader.Size, contentType)
+}
+
+// DeleteURL deletes the object stored at url.
+func DeleteURL(ctx context.Context, s Storage, url string) error {
+	key, err := s.KeyFromURL(url)
+	if err != nil {
+		return err
+	}
+
+	return s.Delete(ctx, key)
+}
+
+// cleanKey rejects keys that are empty or try to escape the storage root.
+func cleanKey(key string) (string, error) {
+	key = strings.TrimPrefix(path.Clean("/"+key), "/")
+	if key == "" || key == "." {
+		return "", ErrInvalidKey
+	}
+	return key, nil
+}
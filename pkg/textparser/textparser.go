@@ -0,0 +1,125 @@
+package textparser
+
+import (
+	"strings"
+	"unicode"
+)
+
+type EntityType string
+
+const (
+	Mention EntityType = "mention"
+	Hashtag EntityType = "hashtag"
+)
+
+const (
+	maxMentionLength = 30
+	maxHashtagLength = 100
+)
+
+// Entity is a @mention or #hashtag found in a text. Start and End are rune
+// offsets (End is exclusive) and cover the prefix character as well.
+type Entity struct {
+	Type  EntityType
+	Value string
+	Start int
+	End   int
+}
+
+func Parse(text string) []Entity {
+	entities := make([]Entity, 0)
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r != '@' && r != '#' {
+			continue
+		}
+
+		if i > 0 && isWordRune(runes[i-1]) {
+			continue
+		}
+
+		entityType := Mention
+		isValid := isMentionRune
+		maxLength := maxMentionLength
+		if r == '#' {
+			entityType = Hashtag
+			isValid = isHashtagRune
+			maxLength = maxHashtagLength
+		}
+
+		end := i + 1
+		for end < len(runes) && isValid(runes[end]) {
+			end++
+		}
+
+		value := strings.TrimRight(string(runes[i+1:end]), ".")
+		length := len([]rune(value))
+
+		if length == 0 || length > maxLength {
+			i = end - 1
+			continue
+		}
+
+		if entityType == Hashtag && !hasLetter(value) {
+			i = end - 1
+			continue
+		}
+
+		entities = append(entities, Entity{
+			Type:  entityType,
+			Value: strings.ToLower(value),
+			Start: i,
+			End:   i + 1 + length,
+		})
+
+		i = end - 1
+	}
+
+	return entities
+}
+
+func Mentions(entities []Entity) []string {
+	return uniqueValues(entities, Mention)
+}
+
+func Hashtags(entities []Entity) []string {
+	return uniqueValues(entities, Hashtag)
+}
+
+func uniqueValues(entities []Entity, entityType EntityType) []string {
+	seen := make(map[string]bool)
+	values := make([]string, 0)
+
+	for _, e := range entities {
+		if e.Type != entityType || seen[e.Value] {
+			continue
+		}
+		seen[e.Value] = true
+		values = append(values, e.Value)
+	}
+
+	return values
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func isMentionRune(r rune) bool {
+	return r < unicode.MaxASCII && (isWordRune(r) || r == '.')
+}
+
+func isHashtagRune(r rune) bool {
+	return isWordRune(r)
+}
+
+func hasLetter(value string) bool {
+	for _, r := range value {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
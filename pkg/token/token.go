@@ -10,7 +10,7 @@ import (
 
 type TokenUseCase interface {
 	GenerateAccessToken(claims JwtCustomClaims) (string, time.Time, error)
-	CreateClaims(userId string, email string) JwtCustomClaims
+	CreateClaims(userId string, email string, role string) JwtCustomClaims
 	IsTokenBlacklisted(tokenString string) bool
 	InvalidateToken(tokenString string) error
 }
@@ -25,6 +25,7 @@ type tokenUseCase struct {
 type JwtCustomClaims struct {
 	ID    string `json:"id"`
 	Email string `json:"email"`
+	Role  string `json:"role"`
 	jwt.RegisteredClaims
 }
 
@@ -36,10 +37,11 @@ func NewTokenUseCase(secretKey string, expirationDuration time.Duration) *tokenU
 	}
 }
 
-func (t *tokenUseCase) CreateClaims(userId string, email string) JwtCustomClaims {
+func (t *tokenUseCase) CreateClaims(userId string, email string, role string) JwtCustomClaims {
 	return JwtCustomClaims{
 		ID:    userId,
 		Email: email,
+		Role:  role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer: "workout-app",
 		},
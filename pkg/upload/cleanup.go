@@ -0,0 +1,51 @@
+package upload
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// StaleFiles returns the paths of the files in dir that were last written
+// before olderThan. The data and info files of a resumable upload go
+// together, dated by the last chunk written, so an upload still receiving
+// chunks is kept whole.
+func StaleFiles(dir string, olderThan time.Time) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	modTimes := make(map[string]time.Time, len(entries))
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		modTimes[entry.Name()] = info.ModTime()
+	}
+
+	var stale []string
+	for name, modTime := range modTimes {
+		if id, ok := strings.CutSuffix(name, ".info"); ok {
+			if dataModTime, ok := modTimes[id+".part"]; ok && dataModTime.After(modTime) {
+				modTime = dataModTime
+			}
+		}
+
+		if modTime.Before(olderThan) {
+			stale = append(stale, filepath.Join(dir, name))
+		}
+	}
+
+	return stale, nil
+}
@@ -0,0 +1,267 @@
+package upload
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TempDir is where uploaded files wait for the worker, whether they came in
+// one request or in chunks.
+const TempDir = "/app/uploads"
+
+var (
+	ErrUploadNotFound   = errors.New("upload not found")
+	ErrUploadLocked     = errors.New("upload is being written by another request")
+	ErrOffsetMismatch   = errors.New("upload offset does not match")
+	ErrUploadTooLarge   = errors.New("upload is larger than its declared length")
+	ErrUploadIncomplete = errors.New("upload is not complete")
+)
+
+// Upload is a file sent in chunks. Its data and a small info file are kept in
+// the store directory until the upload is claimed or deleted.
+type Upload struct {
+	ID        string            `json:"id"`
+	OwnerID   string            `json:"owner_id"`
+	Length    int64             `json:"length"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	Offset    int64             `json:"-"`
+
+	path string
+}
+
+func (u *Upload) Complete() bool {
+	return u.Offset == u.Length
+}
+
+// Name is the filename the client gave, or the upload id when it gave none.
+func (u *Upload) Name() string {
+	if name := u.Metadata["filename"]; name != "" {
+		return filepath.Base(name)
+	}
+	return u.ID
+}
+
+func (u *Upload) Size() int64 {
+	return u.Offset
+}
+
+func (u *Upload) Open() (io.ReadSeekCloser, error) {
+	return os.Open(u.path)
+}
+
+// ResumableStore keeps uploads that arrive in chunks, so an interrupted
+// upload can carry on from where it stopped.
+type ResumableStore interface {
+	Create(ownerID string, length int64, metadata map[string]string) (*Upload, error)
+	Get(id string) (*Upload, error)
+	// Append writes r at offset, which must be the current end of the upload.
+	// What was written is kept even when reading r fails part way.
+	Append(id string, offset int64, r io.Reader) (*Upload, error)
+	// Claim moves a complete upload to a temp file of its own, like
+	// SaveTempFile, and forgets the upload.
+	Claim(id string) (string, error)
+	// Release puts back an upload claimed to tempPath, for when what it was
+	// claimed for fails.
+	Release(upload *Upload, tempPath string) error
+	Delete(id string) error
+}
+
+type fileResumableStore struct {
+	dir   string
+	locks sync.Map
+}
+
+func NewResumableStore(dir string) ResumableStore {
+	return &fileResumableStore{dir: dir}
+}
+
+func (s *fileResumableStore) Create(ownerID string, length int64, metadata map[string]string) (*Upload, error) {
+	upload := &Upload{
+		ID:        uuid.NewString(),
+		OwnerID:   ownerID,
+		Length:    length,
+		Metadata:  metadata,
+		CreatedAt: time.Now(),
+	}
+	upload.path = s.dataPath(upload.ID)
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	data, err := os.OpenFile(upload.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	data.Close()
+
+	info, err := json.Marshal(upload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(s.infoPath(upload.ID), info, 0o644); err != nil {
+		os.Remove(upload.path)
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+func (s *fileResumableStore) Get(id string) (*Upload, error) {
+	// Only ids this store made are looked up, which also keeps them from
+	// pointing outside the directory.
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrUploadNotFound
+	}
+
+	info, err := os.ReadFile(s.infoPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrUploadNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	upload := new(Upload)
+	if err := json.Unmarshal(info, upload); err != nil {
+		return nil, err
+	}
+	upload.path = s.dataPath(id)
+
+	stat, err := os.Stat(upload.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrUploadNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	upload.Offset = stat.Size()
+
+	return upload, nil
+}
+
+func (s *fileResumableStore) Append(id string, offset int64, r io.Reader) (*Upload, error) {
+	unlock, err := s.lock(id)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	upload, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset != upload.Offset {
+		return nil, ErrOffsetMismatch
+	}
+
+	data, err := os.OpenFile(upload.path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer data.Close()
+
+	written, err := io.Copy(data, io.LimitReader(r, upload.Length-upload.Offset))
+	upload.Offset += written
+
+	if err != nil {
+		return upload, err
+	}
+
+	// Whatever is left past the declared length is refused rather than
+	// silently dropped.
+	if n, _ := r.Read(make([]byte, 1)); n > 0 {
+		return upload, ErrUploadTooLarge
+	}
+
+	return upload, nil
+}
+
+func (s *fileResumableStore) Claim(id string) (string, error) {
+	unlock, err := s.lock(id)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	upload, err := s.Get(id)
+	if err != nil {
+		return "", err
+	}
+
+	if !upload.Complete() {
+		return "", ErrUploadIncomplete
+	}
+
+	tempPath := filepath.Join(s.dir, uuid.NewString()+filepath.Ext(upload.Name()))
+
+	if err := os.Rename(upload.path, tempPath); err != nil {
+		return "", err
+	}
+
+	return tempPath, os.Remove(s.infoPath(id))
+}
+
+func (s *fileResumableStore) Release(upload *Upload, tempPath string) error {
+	unlock, err := s.lock(upload.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	info, err := json.Marshal(upload)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(tempPath, s.dataPath(upload.ID)); err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.infoPath(upload.ID), info, 0o644)
+}
+
+func (s *fileResumableStore) Delete(id string) error {
+	unlock, err := s.lock(id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, err := s.Get(id); err != nil {
+		return err
+	}
+
+	if err := os.Remove(s.dataPath(id)); err != nil {
+		return err
+	}
+
+	return os.Remove(s.infoPath(id))
+}
+
+// lock keeps two requests from writing the same upload at once. The second
+// one fails instead of waiting, as its offset would be stale by then.
+func (s *fileResumableStore) lock(id string) (func(), error) {
+	if _, loaded := s.locks.LoadOrStore(id, struct{}{}); loaded {
+		return nil, ErrUploadLocked
+	}
+	return func() { s.locks.Delete(id) }, nil
+}
+
+func (s *fileResumableStore) dataPath(id string) string {
+	return filepath.Join(s.dir, id+".part")
+}
+
+func (s *fileResumableStore) infoPath(id string) string {
+	return filepath.Join(s.dir, id+".info")
+}
@@ -0,0 +1,64 @@
+package videoproc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Processor converts uploaded videos into a format every client can play.
+type Processor interface {
+	// Transcode re-encodes src as an H.264/AAC MP4 without metadata that
+	// starts playing before it is fully downloaded.
+	Transcode(ctx context.Context, src, dst string) error
+	// Poster writes the frame of src shown at the given time as a JPEG.
+	Poster(ctx context.Context, src, dst string, at time.Duration) error
+}
+
+type ffmpeg struct {
+	path      string
+	maxHeight int
+}
+
+// NewFFmpeg returns a Processor running the ffmpeg binary at path. Videos
+// taller than maxHeight are scaled down, keeping their aspect ratio.
+func NewFFmpeg(path string, maxHeight int) Processor {
+	return &ffmpeg{path: path, maxHeight: maxHeight}
+}
+
+func (f *ffmpeg) Transcode(ctx context.Context, src, dst string) error {
+	return f.run(ctx,
+		"-y", "-i", src,
+		"-map_metadata", "-1",
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "23", "-pix_fmt", "yuv420p",
+		"-vf", fmt.Sprintf("scale=-2:'min(%d,ih)'", f.maxHeight),
+		"-c:a", "aac", "-b:a", "128k",
+		"-movflags", "+faststart",
+		dst,
+	)
+}
+
+func (f *ffmpeg) Poster(ctx context.Context, src, dst string, at time.Duration) error {
+	return f.run(ctx,
+		"-y", "-ss", strconv.FormatFloat(at.Seconds(), 'f', 3, 64), "-i", src,
+		"-frames:v", "1", "-q:v", "3",
+		dst,
+	)
+}
+
+func (f *ffmpeg) run(ctx context.Context, args ...string) error {
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, f.path, append([]string{"-hide_banner", "-loglevel", "error"}, args...)...)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Handler processes the messages of one event type.
+type Handler struct {
+	EventType string
+	Run       func(ctx context.Context, body []byte) error
+}
+
+type Consumer interface {
+	Consume(queue string) (<-chan amqp.Delivery, error)
+}
+
+// JSON adapts a function taking a decoded payload into a Handler.Run.
+func JSON[T any](run func(ctx context.Context, payload *T) error) func(ctx context.Context, body []byte) error {
+	return func(ctx context.Context, body []byte) error {
+		payload := new(T)
+		if err := json.Unmarshal(body, payload); err != nil {
+			return err
+		}
+		return run(ctx, payload)
+	}
+}
+
+// Run consumes queue and dispatches each message to the handler of its type
+// until ctx is cancelled or the channel closes. Failed messages are logged
+// and dropped.
+func Run(ctx context.Context, consumer Consumer, queue string, handlers []*Handler) error {
+	deliveries, err := consumer.Consume(queue)
+	if err != nil {
+		return err
+	}
+
+	byType := make(map[string]*Handler, len(handlers))
+	for _, handler := range handlers {
+		byType[handler.EventType] = handler
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return nil
+			}
+
+			handler, found := byType[delivery.Type]
+			if !found {
+				log.Printf("no handler for event %q", delivery.Type)
+				continue
+			}
+
+			if err := handler.Run(ctx, delivery.Body); err != nil {
+				log.Printf("event %s failed: %v", delivery.Type, err)
+			}
+		}
+	}
+}
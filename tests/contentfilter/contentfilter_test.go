@@ -0,0 +1,143 @@
+package contentfilter_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/davidafdal/post-app/pkg/contentfilter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRules = &contentfilter.Rules{
+	Locales: map[string]contentfilter.LocaleRules{
+		"en": {
+			Reject: contentfilter.RuleSet{Words: []string{"scam"}, Patterns: []string{`fr[e3]{2}\s*money`}},
+			Review: contentfilter.RuleSet{Words: []string{"hate"}},
+		},
+		"id": {
+			Reject: contentfilter.RuleSet{Words: []string{"judi"}},
+		},
+	},
+	Domains: contentfilter.DomainRules{
+		Reject: []string{"evil.com"},
+		Review: []string{"www.shady.net"},
+	},
+}
+
+func newFilter(t *testing.T, locales []string, holdRejected bool) contentfilter.Filter {
+	t.Helper()
+	f, err := contentfilter.New(testRules, locales, holdRejected)
+	require.NoError(t, err)
+	return f
+}
+
+func TestFilter_Check(t *testing.T) {
+	f := newFilter(t, []string{"en", "id"}, false)
+
+	tests := []struct {
+		name   string
+		text   string
+		action contentfilter.Action
+		rule   string
+	}{
+		{name: "clean text", text: "a nice day at the beach", action: contentfilter.Allow},
+		{name: "whole word", text: "this is a scam!", action: contentfilter.Reject, rule: "en:word:scam"},
+		{name: "case insensitive", text: "SCAM alert", action: contentfilter.Reject, rule: "en:word:scam"},
+		{name: "word inside another word", text: "scampi for dinner", action: contentfilter.Allow},
+		{name: "word after underscore", text: "my_scam_tag", action: contentfilter.Allow},
+		{name: "word from second locale", text: "ayo main judi", action: contentfilter.Reject, rule: "id:word:judi"},
+		{name: "review word", text: "i hate mondays", action: contentfilter.Review, rule: "en:word:hate"},
+		{name: "pattern", text: "get FR33 money now", action: contentfilter.Reject, rule: `en:pattern:fr[e3]{2}\s*money`},
+		{name: "reject beats review", text: "i hate this scam", action: contentfilter.Reject},
+		{name: "denied domain", text: "see evil.com", action: contentfilter.Reject, rule: "domain:evil.com"},
+		{name: "denied domain with scheme and path", text: "https://evil.com/x?y=1", action: contentfilter.Reject, rule: "domain:evil.com"},
+		{name: "subdomain of denied domain", text: "go to sub.evil.com", action: contentfilter.Reject, rule: "domain:evil.com"},
+		{name: "domain ending in denied name", text: "go to notevil.com", action: contentfilter.Allow},
+		{name: "domain listed with www", text: "http://shady.net", action: contentfilter.Review, rule: "domain:shady.net"},
+		{name: "www of listed domain", text: "www.SHADY.net/page", action: contentfilter.Review, rule: "domain:shady.net"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := f.Check(tt.text)
+
+			assert.Equal(t, tt.action, verdict.Action)
+
+			if tt.action == contentfilter.Allow {
+				assert.Empty(t, verdict.Matches)
+			}
+
+			if tt.rule != "" {
+				rules := make([]string, len(verdict.Matches))
+				for i, m := range verdict.Matches {
+					rules[i] = m.Rule
+				}
+				assert.Contains(t, rules, tt.rule)
+			}
+		})
+	}
+}
+
+func TestFilter_Check_OnlyGivenLocales(t *testing.T) {
+	f := newFilter(t, []string{"en"}, false)
+
+	assert.Equal(t, contentfilter.Allow, f.Check("ayo main judi").Action)
+}
+
+func TestFilter_Check_HoldRejected(t *testing.T) {
+	f := newFilter(t, []string{"en"}, true)
+
+	tests := []struct {
+		text   string
+		action contentfilter.Action
+	}{
+		{text: "this is a scam", action: contentfilter.Review},
+		{text: "see evil.com", action: contentfilter.Review},
+		{text: "i hate mondays", action: contentfilter.Review},
+		{text: "a nice day", action: contentfilter.Allow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			verdict := f.Check(tt.text)
+
+			assert.Equal(t, tt.action, verdict.Action)
+
+			// The matches keep the action of their rule.
+			for _, m := range verdict.Matches {
+				if m.Rule == "en:word:scam" || m.Rule == "domain:evil.com" {
+					assert.Equal(t, contentfilter.Reject, m.Action)
+				}
+			}
+		})
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	_, err := contentfilter.New(testRules, []string{"fr"}, false)
+	assert.Error(t, err)
+
+	invalid := &contentfilter.Rules{Locales: map[string]contentfilter.LocaleRules{
+		"en": {Reject: contentfilter.RuleSet{Patterns: []string{"(unclosed"}}},
+	}}
+	_, err = contentfilter.New(invalid, []string{"en"}, false)
+	assert.Error(t, err)
+}
+
+func TestRules_Load(t *testing.T) {
+	rules, err := contentfilter.DefaultRules()
+	require.NoError(t, err)
+	assert.NotEmpty(t, rules.Locales)
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"locales":{"en":{"reject":{"words":["spam"]}}},"domains":{"reject":["bad.io"]}}`), 0o644))
+
+	loaded, err := contentfilter.LoadRules(path)
+	require.NoError(t, err)
+
+	f, err := contentfilter.New(loaded, []string{"en"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, contentfilter.Reject, f.Check("buy spam at bad.io").Action)
+}
@@ -0,0 +1,70 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/http/handler"
+	"github.com/davidafdal/post-app/internal/services"
+	"github.com/davidafdal/post-app/pkg/upload"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadHandler_RejectsOtherTusVersion(t *testing.T) {
+	ctx := context.Background()
+	svc := services.NewUploadService(upload.NewResumableStore(t.TempDir()), 1<<20)
+	h := handler.NewUploadHandler(svc)
+
+	ownerID := uuid.New()
+
+	created, err := svc.CreateUpload(ctx, &dto.CreateUploadRequest{Length: 6, UserID: ownerID})
+	require.NoError(t, err)
+
+	newContext := func(method, body string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(method, "/uploads/"+created.ID, strings.NewReader(body))
+		req.Header.Set("Tus-Resumable", "0.2.2")
+		req.Header.Set("Upload-Offset", "0")
+		req.Header.Set("Upload-Length", "6")
+		req.Header.Set(echo.HeaderContentType, "application/offset+octet-stream")
+		rec := httptest.NewRecorder()
+
+		c := echo.New().NewContext(req, rec)
+		c.SetParamNames("upload_id")
+		c.SetParamValues(created.ID)
+		c.Set("user_id", ownerID.String())
+		return c, rec
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		run    func(echo.Context) error
+	}{
+		{name: "create", method: http.MethodPost, run: h.CreateUpload},
+		{name: "append", method: http.MethodPatch, run: h.AppendUpload},
+		{name: "delete", method: http.MethodDelete, run: h.DeleteUpload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newContext(tt.method, "abc")
+
+			require.NoError(t, tt.run(c))
+
+			assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+			assert.Empty(t, rec.Header().Get("Location"))
+			assert.Empty(t, rec.Header().Get("Upload-Offset"))
+
+			found, err := svc.GetUpload(ctx, created.ID, ownerID)
+			require.NoError(t, err)
+			assert.Equal(t, int64(0), found.Offset)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davidafdal/post-app/internal/http/handler"
+	"github.com/davidafdal/post-app/internal/services"
+	mocksRepo "github.com/davidafdal/post-app/mocks/repositories"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserHandler_GetFollowers_InvalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	h := handler.NewUserHandler(services.NewUserService(userRepo, nil, nil, nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/owner/followers?cursor=not-a-cursor", nil)
+	rec := httptest.NewRecorder()
+
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("username")
+	c.SetParamValues("owner")
+	c.Set("user_id", uuid.New().String())
+
+	require.NoError(t, h.GetFollowers(c))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,148 @@
+package imageproc_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/davidafdal/post-app/pkg/imageproc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var variants = []imageproc.Variant{
+	{Name: "thumbnail", MaxSize: 10},
+	{Name: "full", MaxSize: 100},
+}
+
+// halves returns a width x height image, red on the left half and blue on
+// the right.
+func halves(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x < width/2 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	return img
+}
+
+// withOrientation inserts an EXIF segment carrying orientation right after
+// the start marker of a JPEG.
+func withOrientation(jpegData []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01")
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, jpegData[:2]...)
+	out = append(out, segment...)
+	return append(out, jpegData[2:]...)
+}
+
+func TestProcess_OrientsAndStripsMetadata(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, jpeg.Encode(buf, halves(40, 20), &jpeg.Options{Quality: 95}))
+	upload := withOrientation(buf.Bytes(), 6)
+
+	result, err := imageproc.New(variants, 90).Process(bytes.NewReader(upload))
+	require.NoError(t, err)
+
+	assert.Equal(t, 20, result.Width)
+	assert.Equal(t, 40, result.Height)
+	require.Len(t, result.Variants, 2)
+
+	thumbnail, full := result.Variants[0], result.Variants[1]
+	assert.Equal(t, "thumbnail", thumbnail.Name)
+	assert.Equal(t, 5, thumbnail.Width)
+	assert.Equal(t, 10, thumbnail.Height)
+	assert.Equal(t, 20, full.Width, "images are never upscaled")
+	assert.Equal(t, "image/jpeg", full.ContentType)
+	assert.NotContains(t, string(full.Data), "Exif")
+
+	decoded, err := jpeg.Decode(bytes.NewReader(full.Data))
+	require.NoError(t, err)
+
+	r, _, b, _ := decoded.At(10, 2).RGBA()
+	assert.Greater(t, r, b, "the left half of the photo ends up on top")
+}
+
+func TestProcess_KeepsTransparencyAsPNG(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, png.Encode(buf, image.NewNRGBA(image.Rect(0, 0, 30, 30))))
+
+	result, err := imageproc.New(variants, 90).Process(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	for _, variant := range result.Variants {
+		assert.Equal(t, "image/png", variant.ContentType)
+		assert.Equal(t, ".png", variant.Extension)
+	}
+}
+
+func TestBlurhash_SolidColour(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+3] = 255, 255
+	}
+
+	hash := imageproc.Blurhash(img, 4, 3)
+
+	assert.Len(t, hash, 28)
+	assert.Equal(t, "L", hash[:1], "4x3 components")
+	assert.Equal(t, "TI:j", hash[2:6], "average colour is pure red")
+}
+
+func TestCanProcess(t *testing.T) {
+	assert.True(t, imageproc.CanProcess("image/jpeg"))
+	assert.True(t, imageproc.CanProcess("image/png"))
+	assert.False(t, imageproc.CanProcess("image/gif"))
+	assert.False(t, imageproc.CanProcess("video/mp4"))
+}
+
+// gradient returns a width x height grey image getting lighter from left to
+// right, or from right to left when reversed.
+func gradient(width, height int, reversed bool) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			level := x * 255 / (width - 1)
+			if reversed {
+				level = 255 - level
+			}
+			img.SetGray(x, y, color.Gray{Y: uint8(level)})
+		}
+	}
+	return img
+}
+
+func TestPerceptualHash_MatchesResizedCopies(t *testing.T) {
+	original := &bytes.Buffer{}
+	require.NoError(t, png.Encode(original, gradient(300, 200, false)))
+
+	result, err := imageproc.New(variants, 90).Process(bytes.NewReader(original.Bytes()))
+	require.NoError(t, err)
+
+	copyHash, err := imageproc.HashImage(bytes.NewReader(result.Variants[0].Data))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, imageproc.HashDistance(result.Hash, copyHash), 4)
+
+	reversed := &bytes.Buffer{}
+	require.NoError(t, png.Encode(reversed, gradient(300, 200, true)))
+
+	otherHash, err := imageproc.HashImage(reversed)
+	require.NoError(t, err)
+	assert.Greater(t, imageproc.HashDistance(result.Hash, otherHash), 32)
+}
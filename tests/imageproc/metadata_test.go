@@ -0,0 +1,125 @@
+package imageproc_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+
+	"github.com/davidafdal/post-app/pkg/imageproc"
+	"github.com/davidafdal/post-app/pkg/mediacheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// losslessPixel is the VP8L chunk of a 1x1 lossless WebP.
+var losslessPixel = func() []byte {
+	data, _ := base64.StdEncoding.DecodeString("UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA==")
+	return data[12:]
+}()
+
+func riffChunk(fourCC string, payload []byte) []byte {
+	chunk := append([]byte(fourCC), binary.LittleEndian.AppendUint32(nil, uint32(len(payload)))...)
+	chunk = append(chunk, payload...)
+	if len(payload)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+func webp(chunks ...[]byte) []byte {
+	body := []byte("WEBP")
+	for _, chunk := range chunks {
+		body = append(body, chunk...)
+	}
+	return append(append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(body)))...), body...)
+}
+
+// webpWithEXIF returns a 1x1 extended WebP carrying EXIF with GPS and XMP.
+func webpWithEXIF() []byte {
+	vp8x := []byte{0x08 | 0x04, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	exif := append([]byte("MM\x00\x2a\x00\x00\x00\x08"), "GPSLatitude=52.37"...)
+	xmp := []byte(`<x:xmpmeta><rdf:Description exif:GPSLongitude="4.89"/></x:xmpmeta>`)
+
+	return webp(riffChunk("VP8X", vp8x), losslessPixel, riffChunk("EXIF", exif), riffChunk("XMP ", xmp))
+}
+
+func TestStripMetadata_WebP(t *testing.T) {
+	original := webpWithEXIF()
+
+	stripped, err := imageproc.StripMetadata("image/webp", original)
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(stripped), "GPS")
+	assert.NotContains(t, string(stripped), "EXIF")
+	assert.NotContains(t, string(stripped), "XMP ")
+	assert.Contains(t, string(stripped), string(losslessPixel))
+
+	assert.Equal(t, uint32(len(stripped)-8), binary.LittleEndian.Uint32(stripped[4:8]))
+	assert.Equal(t, "VP8X", string(stripped[12:16]))
+	assert.Zero(t, stripped[20]&(0x08|0x04), "EXIF and XMP flags are cleared")
+
+	info, err := mediacheck.Inspect(bytes.NewReader(stripped), int64(len(stripped)))
+	require.NoError(t, err)
+	assert.Equal(t, "image/webp", info.ContentType)
+}
+
+func TestStripMetadata_GIF(t *testing.T) {
+	img := image.NewPaletted(image.Rect(0, 0, 2, 2), color.Palette{color.Black, color.White})
+	anim := &gif.GIF{Image: []*image.Paletted{img, img}, Delay: []int{10, 10}}
+
+	var buf bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buf, anim))
+	encoded := buf.Bytes()
+
+	header := 13
+	if encoded[10]&0x80 != 0 {
+		header += 3 << ((encoded[10] & 0x07) + 1)
+	}
+
+	comment := append([]byte{0x21, 0xFE, 3}, "gps"...)
+	comment = append(comment, 0)
+	xmp := append([]byte{0x21, 0xFF, 11}, "XMP DataXMP"...)
+	xmp = append(xmp, 4)
+	xmp = append(xmp, "52.3"...)
+	xmp = append(xmp, 0)
+
+	original := append([]byte{}, encoded[:header]...)
+	original = append(original, comment...)
+	original = append(original, xmp...)
+	original = append(original, encoded[header:]...)
+
+	stripped, err := imageproc.StripMetadata("image/gif", original)
+	require.NoError(t, err)
+
+	assert.Equal(t, encoded, stripped, "only the metadata is dropped")
+	assert.Contains(t, string(stripped), "NETSCAPE2.0", "the animation loop is kept")
+
+	decoded, err := gif.DecodeAll(bytes.NewReader(stripped))
+	require.NoError(t, err)
+	assert.Len(t, decoded.Image, 2)
+}
+
+func TestStripMetadata_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+		err         error
+	}{
+		{name: "truncated webp chunk", contentType: "image/webp", data: webpWithEXIF()[:40], err: imageproc.ErrCorruptImage},
+		{name: "not a webp", contentType: "image/webp", data: []byte("RIFF\x04\x00\x00\x00WAVE"), err: imageproc.ErrCorruptImage},
+		{name: "gif without trailer", contentType: "image/gif", data: []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00"), err: imageproc.ErrCorruptImage},
+		{name: "other format", contentType: "image/png", data: []byte("\x89PNG"), err: imageproc.ErrUnsupportedFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := imageproc.StripMetadata(tt.contentType, tt.data)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
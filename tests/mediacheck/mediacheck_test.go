@@ -0,0 +1,158 @@
+package mediacheck_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"github.com/davidafdal/post-app/pkg/mediacheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func box(name string, content ...[]byte) []byte {
+	body := bytes.Join(content, nil)
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(8+len(body)))
+	copy(header[4:], name)
+	return append(header, body...)
+}
+
+// mp4Bytes builds the smallest MP4 carrying a movie header and the given
+// tracks.
+func mp4Bytes(brand string, duration time.Duration, tracks ...[]byte) []byte {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:20], uint32(duration.Milliseconds()))
+
+	ftyp := append([]byte(brand), 0, 0, 0, 0)
+	ftyp = append(ftyp, []byte("isommp41")...)
+
+	return append(box("ftyp", ftyp), box("moov", append([][]byte{box("mvhd", mvhd)}, tracks...)...)...)
+}
+
+// trakBytes builds a track holding only a version 0 track header, rotated by
+// 90 degrees when rotated is set.
+func trakBytes(width, height int, rotated bool) []byte {
+	tkhd := make([]byte, 84)
+	matrix := tkhd[40:76]
+	if rotated {
+		binary.BigEndian.PutUint32(matrix[4:8], 0x00010000)
+		binary.BigEndian.PutUint32(matrix[12:16], 0xffff0000)
+	} else {
+		binary.BigEndian.PutUint32(matrix[0:4], 0x00010000)
+		binary.BigEndian.PutUint32(matrix[16:20], 0x00010000)
+	}
+	binary.BigEndian.PutUint32(tkhd[76:80], uint32(width)<<16)
+	binary.BigEndian.PutUint32(tkhd[80:84], uint32(height)<<16)
+
+	return box("trak", box("tkhd", tkhd))
+}
+
+func webpBytes(width, height int) []byte {
+	chunk := make([]byte, 10)
+	chunk[4], chunk[5], chunk[6] = byte(width-1), byte((width-1)>>8), byte((width-1)>>16)
+	chunk[7], chunk[8], chunk[9] = byte(height-1), byte((height-1)>>8), byte((height-1)>>16)
+
+	vp8x := append([]byte("VP8X\x0a\x00\x00\x00"), chunk...)
+	riff := append([]byte("WEBP"), vp8x...)
+
+	size := make([]byte, 4)
+	binary.LittleEndian.PutUint32(size, uint32(len(riff)))
+
+	return append(append([]byte("RIFF"), size...), riff...)
+}
+
+func pngBytes(t *testing.T, width, height int) []byte {
+	buf := &bytes.Buffer{}
+	require.NoError(t, png.Encode(buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func fileHeader(t *testing.T, filename string, content []byte) *multipart.FileHeader {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+
+	return form.File["file"][0]
+}
+
+func TestInspect(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     []byte
+		contentType string
+		width       int
+		height      int
+		duration    time.Duration
+	}{
+		{name: "png", content: pngBytes(t, 30, 20), contentType: "image/png", width: 30, height: 20},
+		{name: "webp", content: webpBytes(640, 480), contentType: "image/webp", width: 640, height: 480},
+		{name: "mp4", content: mp4Bytes("isom", 90*time.Second), contentType: "video/mp4", duration: 90 * time.Second},
+		{name: "quicktime", content: mp4Bytes("qt  ", 5*time.Second), contentType: "video/quicktime", duration: 5 * time.Second},
+		{name: "mp4 with video track", content: mp4Bytes("isom", 3*time.Second, trakBytes(0, 0, false), trakBytes(1280, 720, false)), contentType: "video/mp4", width: 1280, height: 720, duration: 3 * time.Second},
+		{name: "rotated mp4", content: mp4Bytes("isom", 3*time.Second, trakBytes(1920, 1080, true)), contentType: "video/mp4", width: 1080, height: 1920, duration: 3 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := mediacheck.Inspect(bytes.NewReader(tt.content), int64(len(tt.content)))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.contentType, info.ContentType)
+			assert.Equal(t, tt.width, info.Width)
+			assert.Equal(t, tt.height, info.Height)
+			assert.Equal(t, tt.duration, info.Duration)
+		})
+	}
+}
+
+func TestInspect_Unsupported(t *testing.T) {
+	_, err := mediacheck.Inspect(bytes.NewReader([]byte("%PDF-1.4 not an image")), 21)
+
+	assert.ErrorIs(t, err, mediacheck.ErrUnsupportedFormat)
+}
+
+func TestValidator_Check(t *testing.T) {
+	validator := mediacheck.New(mediacheck.Limits{
+		MaxFiles:         3,
+		MaxVideoDuration: time.Minute,
+		MaxImageWidth:    100,
+		MaxImageHeight:   100,
+	})
+
+	files := []*multipart.FileHeader{
+		fileHeader(t, "cat.png", pngBytes(t, 10, 10)),
+		fileHeader(t, "clip.mp4", mp4Bytes("isom", 2*time.Minute)),
+		fileHeader(t, "short.mp4", mp4Bytes("isom", 10*time.Second)),
+	}
+
+	_, err := validator.Check(files, mediacheck.KindImage, mediacheck.KindVideo)
+
+	var invalid *mediacheck.ValidationError
+	require.ErrorAs(t, err, &invalid)
+	require.Len(t, invalid.Files, 1)
+	assert.Equal(t, 1, invalid.Files[0].Index)
+	assert.Equal(t, "video is longer than 1m0s", invalid.Files[0].Reason)
+
+	infos, err := validator.Check([]*multipart.FileHeader{files[0], files[2]}, mediacheck.KindImage, mediacheck.KindVideo)
+	require.NoError(t, err)
+	assert.Equal(t, mediacheck.KindImage, infos[0].Kind)
+	assert.Equal(t, mediacheck.KindVideo, infos[1].Kind)
+
+	_, err = validator.Check(files[2:], mediacheck.KindImage)
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "video files are not allowed here", invalid.Files[0].Reason)
+}
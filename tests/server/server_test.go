@@ -0,0 +1,58 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davidafdal/post-app/pkg/route"
+	"github.com/davidafdal/post-app/pkg/server"
+	"github.com/davidafdal/post-app/pkg/token"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secretKey = "test-secret"
+
+func TestServer_QueryTokenOnlyOnOptedInRoutes(t *testing.T) {
+	tokenUse := token.NewTokenUseCase(secretKey, time.Hour)
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	checkAccount := func(ctx context.Context, userID string) (string, bool, error) { return "user", false, nil }
+
+	srv := server.NewServer(nil, []*route.Route{
+		{Method: http.MethodGet, Path: "/feeds", Handler: ok},
+		{Method: http.MethodGet, Path: "/ws", Handler: ok, QueryToken: true},
+	}, secretKey, tokenUse, checkAccount)
+
+	accessToken, _, err := tokenUse.GenerateAccessToken(tokenUse.CreateClaims("user-id", "user@example.com", "user"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		target string
+		header bool
+		status int
+	}{
+		{name: "header on api route", target: "/api/feeds", header: true, status: http.StatusOK},
+		{name: "query on api route", target: "/api/feeds?token=" + accessToken, status: http.StatusUnauthorized},
+		{name: "header on websocket route", target: "/api/ws", header: true, status: http.StatusOK},
+		{name: "query on websocket route", target: "/api/ws?token=" + accessToken, status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header {
+				req.Header.Set(echo.HeaderAuthorization, "Bearer "+accessToken)
+			}
+			rec := httptest.NewRecorder()
+
+			srv.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
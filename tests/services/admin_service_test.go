@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/services"
+	mocksRepo "github.com/davidafdal/post-app/mocks/repositories"
+	"github.com/google/uuid"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminService_SuspendUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	reportRepo := mocksRepo.NewMockReportRepository(ctrl)
+
+	svc := services.NewAdminService(userRepo, reportRepo)
+
+	moderatorID := uuid.New()
+	userID := uuid.New()
+
+	userRepo.EXPECT().FindByID(userID).Return(&entities.User{ID: userID, Role: "user"}, nil)
+	userRepo.EXPECT().Suspend(userID).Return(nil)
+	reportRepo.
+		EXPECT().
+		CreateAuditLog(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, log *entities.AuditLog) error {
+			assert.Equal(t, entities.ModerationActionSuspendUser, log.Action)
+			assert.Equal(t, userID, log.TargetID)
+			return nil
+		})
+
+	assert.NoError(t, svc.SuspendUser(ctx, moderatorID, "moderator", userID))
+}
+
+func TestAdminService_SuspendUser_Outranked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	reportRepo := mocksRepo.NewMockReportRepository(ctrl)
+
+	svc := services.NewAdminService(userRepo, reportRepo)
+
+	adminID := uuid.New()
+
+	userRepo.EXPECT().FindByID(adminID).Return(&entities.User{ID: adminID, Role: "admin"}, nil)
+
+	err := svc.SuspendUser(context.Background(), uuid.New(), "moderator", adminID)
+
+	assert.ErrorIs(t, err, services.ErrForbidden)
+}
+
+func TestAdminService_ChangeRole_Self(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	reportRepo := mocksRepo.NewMockReportRepository(ctrl)
+
+	svc := services.NewAdminService(userRepo, reportRepo)
+
+	adminID := uuid.New()
+
+	err := svc.ChangeRole(context.Background(), &dto.ChangeRoleRequest{
+		UserID:  adminID,
+		Role:    "user",
+		ActorID: adminID,
+	})
+
+	assert.ErrorIs(t, err, services.ErrSelfAction)
+}
+
+func TestAdminService_ChangeRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	reportRepo := mocksRepo.NewMockReportRepository(ctrl)
+
+	svc := services.NewAdminService(userRepo, reportRepo)
+
+	userID := uuid.New()
+
+	userRepo.EXPECT().FindByID(userID).Return(&entities.User{ID: userID, Role: "user"}, nil)
+	userRepo.EXPECT().UpdateRole(userID, "moderator").Return(nil)
+	reportRepo.EXPECT().CreateAuditLog(ctx, gomock.Any()).Return(nil)
+
+	err := svc.ChangeRole(ctx, &dto.ChangeRoleRequest{
+		UserID:    userID,
+		Role:      "moderator",
+		ActorID:   uuid.New(),
+		ActorRole: "admin",
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestAdminService_ChangeRole_Forbidden(t *testing.T) {
+	tests := []struct {
+		name       string
+		actorRole  string
+		targetRole string
+		role       string
+	}{
+		{name: "demote a peer", actorRole: "admin", targetRole: "admin", role: "user"},
+		{name: "re-role a higher rank", actorRole: "moderator", targetRole: "admin", role: "moderator"},
+		{name: "grant a role above own", actorRole: "moderator", targetRole: "user", role: "admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mocksRepo.NewMockUserRepository(ctrl)
+			reportRepo := mocksRepo.NewMockReportRepository(ctrl)
+
+			svc := services.NewAdminService(userRepo, reportRepo)
+
+			userID := uuid.New()
+
+			userRepo.EXPECT().FindByID(userID).Return(&entities.User{ID: userID, Role: tt.targetRole}, nil)
+
+			err := svc.ChangeRole(context.Background(), &dto.ChangeRoleRequest{
+				UserID:    userID,
+				Role:      tt.role,
+				ActorID:   uuid.New(),
+				ActorRole: tt.actorRole,
+			})
+
+			assert.ErrorIs(t, err, services.ErrForbidden)
+		})
+	}
+}
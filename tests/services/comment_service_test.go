@@ -0,0 +1,440 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/davidafdal/post-app/internal/services"
+	mocksRepo "github.com/davidafdal/post-app/mocks/repositories"
+	"github.com/davidafdal/post-app/pkg/contentfilter"
+	"github.com/davidafdal/post-app/pkg/cursor"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentService_CreateCommentReplies_FeedMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	commentRepo := mocksRepo.NewMockCommentRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewCommentService(commentRepo, feedRepo, notificationRepo, newContentModerator(t, nil, nil))
+
+	parent := &entities.Comment{ID: uuid.New(), FeedID: uuid.New(), UserID: uuid.New()}
+	senderID := uuid.New()
+
+	commentRepo.EXPECT().FindByID(ctx, parent.ID).Return(parent, nil)
+	feedRepo.EXPECT().IsVisible(ctx, parent.FeedID, senderID).Return(true, nil)
+
+	_, err := svc.CreateCommentReplies(ctx, &dto.CreateReplyCommentRequest{
+		CommentID: parent.ID,
+		FeedID:    uuid.New(),
+		SenderID:  senderID,
+		Comment:   "reply",
+	})
+
+	assert.ErrorIs(t, err, services.ErrCommentNotFound)
+}
+
+func TestCommentService_CreateCommentReplies_UsesParentFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	commentRepo := mocksRepo.NewMockCommentRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewCommentService(commentRepo, feedRepo, notificationRepo, newContentModerator(t, nil, nil))
+
+	parent := &entities.Comment{ID: uuid.New(), FeedID: uuid.New(), UserID: uuid.New()}
+	senderID := uuid.New()
+
+	commentRepo.EXPECT().FindByID(ctx, parent.ID).Return(parent, nil)
+	feedRepo.EXPECT().IsVisible(ctx, parent.FeedID, senderID).Return(true, nil)
+	commentRepo.
+		EXPECT().
+		CreateReply(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, comment *entities.Comment) (*entities.Comment, error) {
+			assert.Equal(t, parent.FeedID, comment.FeedID)
+			assert.Equal(t, parent.ID, comment.ParentID)
+			return comment, nil
+		})
+
+	_, err := svc.CreateCommentReplies(ctx, &dto.CreateReplyCommentRequest{
+		CommentID: parent.ID,
+		SenderID:  senderID,
+		Comment:   "reply",
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestCommentService_GetTopLevelComment_FeedNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	commentRepo := mocksRepo.NewMockCommentRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewCommentService(commentRepo, feedRepo, notificationRepo, newContentModerator(t, nil, nil))
+
+	feedID := uuid.New()
+	viewerID := uuid.New()
+
+	feedRepo.EXPECT().IsVisible(ctx, feedID, viewerID).Return(false, repositories.ErrFeedNotFound)
+
+	_, err := svc.GetTopLevelComment(ctx, feedID, viewerID, "", "", 20)
+
+	assert.ErrorIs(t, err, services.ErrFeedNotFound)
+}
+
+func TestCommentService_CreateComment_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	commentRepo := mocksRepo.NewMockCommentRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewCommentService(commentRepo, feedRepo, notificationRepo, newContentModerator(t, nil, nil))
+
+	feedID := uuid.New()
+	senderID := uuid.New()
+
+	feedRepo.EXPECT().IsVisible(ctx, feedID, senderID).Return(true, nil)
+
+	_, err := svc.CreateComment(ctx, &dto.CreateCommentRequest{
+		FeedID:   feedID,
+		SenderID: senderID,
+		Comment:  "yuk main SLOT GACOR hari ini",
+	})
+
+	assert.ErrorIs(t, err, services.ErrContentRejected)
+}
+
+func TestCommentService_CreateComment_HeldForReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	commentRepo := mocksRepo.NewMockCommentRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+	reportRepo := mocksRepo.NewMockReportRepository(ctrl)
+
+	svc := services.NewCommentService(commentRepo, feedRepo, notificationRepo, newContentModerator(t, reportRepo, nil))
+
+	feedID := uuid.New()
+	senderID := uuid.New()
+	commentID := uuid.New()
+
+	feedRepo.EXPECT().IsVisible(ctx, feedID, senderID).Return(true, nil)
+	commentRepo.
+		EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, comment *entities.Comment) (*entities.Comment, error) {
+			assert.True(t, comment.IsHidden)
+			comment.ID = commentID
+			comment.MentionedUsers = []uuid.UUID{uuid.New()}
+			return comment, nil
+		})
+	reportRepo.EXPECT().HideContent(ctx, entities.ReportTargetComment, commentID, nil).Return(false, nil)
+	reportRepo.
+		EXPECT().
+		CreateSystemReport(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, report *entities.Report) (*entities.Report, error) {
+			assert.Equal(t, entities.ReportReasonContentFilter, report.Reason)
+			report.ID = uuid.New()
+			return report, nil
+		})
+	reportRepo.EXPECT().CreateAuditLog(ctx, gomock.Any()).Return(nil)
+
+	comment, err := svc.CreateComment(ctx, &dto.CreateCommentRequest{
+		FeedID:   feedID,
+		SenderID: senderID,
+		Comment:  "dasar goblok @budi",
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, comment.HeldForReview)
+}
+
+func TestCommentService_UpdateComment_NotAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	commentRepo := mocksRepo.NewMockCommentRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewCommentService(commentRepo, feedRepo, notificationRepo, newContentModerator(t, nil, nil))
+
+	comment := &entities.Comment{ID: uuid.New(), FeedID: uuid.New(), UserID: uuid.New()}
+	userID := uuid.New()
+
+	commentRepo.EXPECT().FindByID(ctx, comment.ID).Return(comment, nil)
+	feedRepo.EXPECT().IsVisible(ctx, comment.FeedID, userID).Return(true, nil)
+
+	_, err := svc.UpdateComment(ctx, &dto.UpdateCommentRequest{
+		CommentID: comment.ID,
+		Comment:   "edited",
+		UserID:    userID,
+	})
+
+	assert.ErrorIs(t, err, services.ErrNotOwner)
+}
+
+func TestCommentService_UpdateComment_MarksEdited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	commentRepo := mocksRepo.NewMockCommentRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewCommentService(commentRepo, feedRepo, notificationRepo, newContentModerator(t, nil, nil))
+
+	comment := &entities.Comment{ID: uuid.New(), FeedID: uuid.New(), UserID: uuid.New()}
+	editedAt := time.Now()
+
+	commentRepo.EXPECT().FindByID(ctx, comment.ID).Return(comment, nil)
+	feedRepo.EXPECT().IsVisible(ctx, comment.FeedID, comment.UserID).Return(true, nil)
+	commentRepo.
+		EXPECT().
+		Update(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, c *entities.Comment) (*entities.Comment, error) {
+			assert.Equal(t, "edited #go", c.Comment)
+			assert.Equal(t, []string{"go"}, c.Hashtags)
+			c.EditedAt = &editedAt
+			return c, nil
+		})
+
+	res, err := svc.UpdateComment(ctx, &dto.UpdateCommentRequest{
+		CommentID: comment.ID,
+		Comment:   "edited #go",
+		UserID:    comment.UserID,
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, res.IsEdited)
+	assert.Equal(t, &editedAt, res.EditedAt)
+}
+
+func TestCommentService_DeleteComment_ByFeedOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	commentRepo := mocksRepo.NewMockCommentRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewCommentService(commentRepo, feedRepo, notificationRepo, newContentModerator(t, nil, nil))
+
+	comment := &entities.Comment{ID: uuid.New(), FeedID: uuid.New(), UserID: uuid.New()}
+	feedOwnerID := uuid.New()
+
+	commentRepo.EXPECT().FindByID(ctx, comment.ID).Return(comment, nil)
+	feedRepo.EXPECT().FindOwnerID(ctx, comment.FeedID).Return(feedOwnerID, nil)
+	commentRepo.EXPECT().SoftDelete(ctx, comment.ID).Return(nil)
+
+	err := svc.DeleteComment(ctx, comment.ID, feedOwnerID)
+
+	assert.NoError(t, err)
+}
+
+func TestCommentService_DeleteComment_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	commentRepo := mocksRepo.NewMockCommentRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewCommentService(commentRepo, feedRepo, notificationRepo, newContentModerator(t, nil, nil))
+
+	comment := &entities.Comment{ID: uuid.New(), FeedID: uuid.New(), UserID: uuid.New()}
+
+	commentRepo.EXPECT().FindByID(ctx, comment.ID).Return(comment, nil)
+	feedRepo.EXPECT().FindOwnerID(ctx, comment.FeedID).Return(uuid.New(), nil)
+
+	err := svc.DeleteComment(ctx, comment.ID, uuid.New())
+
+	assert.ErrorIs(t, err, services.ErrNotOwner)
+}
+
+func TestCommentService_PinComment_RejectsReply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	commentRepo := mocksRepo.NewMockCommentRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewCommentService(commentRepo, feedRepo, notificationRepo, newContentModerator(t, nil, nil))
+
+	ownerID := uuid.New()
+	reply := &entities.Comment{ID: uuid.New(), FeedID: uuid.New(), ParentID: uuid.New(), UserID: uuid.New()}
+
+	feedRepo.EXPECT().FindOwnerID(ctx, reply.FeedID).Return(ownerID, nil)
+	commentRepo.EXPECT().FindByID(ctx, reply.ID).Return(reply, nil)
+
+	_, err := svc.PinComment(ctx, reply.FeedID, reply.ID, ownerID)
+
+	assert.ErrorIs(t, err, services.ErrCommentNotFound)
+}
+
+func TestCommentService_GetTopLevelComment_DeletedPlaceholder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	commentRepo := mocksRepo.NewMockCommentRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewCommentService(commentRepo, feedRepo, notificationRepo, newContentModerator(t, nil, nil))
+
+	feedID := uuid.New()
+	viewerID := uuid.New()
+	deletedAt := time.Now()
+
+	feedRepo.EXPECT().IsVisible(ctx, feedID, viewerID).Return(true, nil)
+	deleted := &entities.Comment{ID: uuid.New(), FeedID: feedID, Comment: "", ReplyCout: 2, DeletedAt: &deletedAt}
+
+	commentRepo.EXPECT().FindTopComment(ctx, feedID, viewerID, entities.CommentSortNewest, nil, 20).Return([]*entities.Comment{deleted}, nil)
+	commentRepo.EXPECT().FindPinnedComment(ctx, feedID, viewerID).Return(nil, nil)
+	commentRepo.EXPECT().FindReplyPreviews(ctx, []uuid.UUID{deleted.ID}, viewerID, 3).Return([]*entities.Comment{}, nil)
+
+	res, err := svc.GetTopLevelComment(ctx, feedID, viewerID, "", "", 20)
+
+	assert.NoError(t, err)
+	assert.Len(t, res.Items, 1)
+	assert.Equal(t, "[deleted]", res.Items[0].Comment)
+	assert.True(t, res.Items[0].IsDeleted)
+	assert.Nil(t, res.Items[0].User)
+	assert.Equal(t, 2, res.Items[0].ReplyCount)
+}
+
+func TestCommentService_GetTopLevelComment_PinnedFirstWithPreviews(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	commentRepo := mocksRepo.NewMockCommentRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewCommentService(commentRepo, feedRepo, notificationRepo, newContentModerator(t, nil, nil))
+
+	feedID := uuid.New()
+	viewerID := uuid.New()
+	author := &entities.User{ID: uuid.New(), Username: "budi", Avatar: "budi.jpg"}
+
+	pinned := &entities.Comment{ID: uuid.New(), FeedID: feedID, Comment: "pinned", IsPinned: true, User: author}
+	first := &entities.Comment{ID: uuid.New(), FeedID: feedID, Comment: "first", ReplyCout: 1, LikeCount: 4, CreatedAt: time.Now(), User: author}
+	second := &entities.Comment{ID: uuid.New(), FeedID: feedID, Comment: "second", LikeCount: 2, CreatedAt: time.Now(), User: author}
+	reply := &entities.Comment{ID: uuid.New(), FeedID: feedID, ParentID: first.ID, Comment: "reply", User: author}
+
+	feedRepo.EXPECT().IsVisible(ctx, feedID, viewerID).Return(true, nil)
+	commentRepo.EXPECT().FindTopComment(ctx, feedID, viewerID, entities.CommentSortTop, nil, 2).Return([]*entities.Comment{first, second}, nil)
+	commentRepo.EXPECT().FindPinnedComment(ctx, feedID, viewerID).Return(pinned, nil)
+	commentRepo.EXPECT().FindReplyPreviews(ctx, []uuid.UUID{first.ID}, viewerID, 3).Return([]*entities.Comment{reply}, nil)
+
+	res, err := svc.GetTopLevelComment(ctx, feedID, viewerID, entities.CommentSortTop, "", 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, res.Items, 3)
+	assert.Equal(t, pinned.ID, res.Items[0].ID)
+	assert.True(t, res.Items[0].IsPinned)
+	assert.Equal(t, "budi", res.Items[1].User.Username)
+	assert.Len(t, res.Items[1].ReplyPreview, 1)
+	assert.Equal(t, reply.ID, res.Items[1].ReplyPreview[0].ID)
+
+	next, err := cursor.Decode(res.NextCursor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, next.ID)
+	assert.Equal(t, int64(2), next.Score)
+}
+
+func TestCommentService_GetTopLevelComment_InvalidSort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	commentRepo := mocksRepo.NewMockCommentRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewCommentService(commentRepo, feedRepo, notificationRepo, newContentModerator(t, nil, nil))
+
+	_, err := svc.GetTopLevelComment(ctx, uuid.New(), uuid.New(), "random", "", 20)
+
+	assert.ErrorIs(t, err, services.ErrInvalidCommentSort)
+}
+
+func TestCommentService_LikeComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	commentRepo := mocksRepo.NewMockCommentRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewCommentService(commentRepo, feedRepo, notificationRepo, newContentModerator(t, nil, nil))
+
+	comment := &entities.Comment{ID: uuid.New(), FeedID: uuid.New(), UserID: uuid.New()}
+	userID := uuid.New()
+
+	commentRepo.EXPECT().FindByID(ctx, comment.ID).Return(comment, nil)
+	feedRepo.EXPECT().IsVisible(ctx, comment.FeedID, userID).Return(true, nil)
+	commentRepo.EXPECT().ToggleLiked(ctx, comment.ID, userID).Return("liked", nil)
+
+	status, err := svc.LikeComment(ctx, comment.ID, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "liked", status)
+}
+
+func newContentModerator(t *testing.T, reportRepo *mocksRepo.MockReportRepository, mediaRepo *mocksRepo.MockMediaRepository) services.ContentModerator {
+	rules, err := contentfilter.DefaultRules()
+	assert.NoError(t, err)
+
+	filter, err := contentfilter.New(rules, []string{"id", "en"}, false)
+	assert.NoError(t, err)
+
+	return services.NewContentModerator(filter, reportRepo, mediaRepo, 6)
+}
@@ -1,22 +1,48 @@
 package services_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
 	"mime/multipart"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/davidafdal/post-app/internal/dto"
 	"github.com/davidafdal/post-app/internal/entities"
 	"github.com/davidafdal/post-app/internal/events"
+	"github.com/davidafdal/post-app/internal/repositories"
 	"github.com/davidafdal/post-app/internal/services"
 	mocksPkg "github.com/davidafdal/post-app/mocks/pkg"
 	mocksRepo "github.com/davidafdal/post-app/mocks/repositories"
+	"github.com/davidafdal/post-app/pkg/mediacheck"
+	"github.com/davidafdal/post-app/pkg/upload"
 
 	gomock "github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+var reactionTypes = []string{"like", "love", "laugh", "wow", "sad", "angry"}
+
+// unblockedMedia returns a media repository in which no content is blocked.
+func unblockedMedia(ctrl *gomock.Controller) *mocksRepo.MockMediaRepository {
+	mediaRepo := mocksRepo.NewMockMediaRepository(ctrl)
+	mediaRepo.EXPECT().IsBlocked(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	return mediaRepo
+}
+
+var mediaValidator = mediacheck.New(mediacheck.Limits{MaxFiles: 2, MaxImageSize: 1 << 20, MaxImageWidth: 100, MaxImageHeight: 100})
+
 func TestFeedService_CreateFeed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -25,23 +51,20 @@ func TestFeedService_CreateFeed(t *testing.T) {
 
 	// mock dependencies
 	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
 	storage := mocksPkg.NewMockUploadUseCase(ctrl)
 	publisher := mocksPkg.NewMockMessageBroker(ctrl)
 
 	// service under test
-	svc := services.NewFeedService(feedRepo, storage, publisher)
+	svc := services.NewFeedService(feedRepo, userRepo, notificationRepo, newContentModerator(t, nil, unblockedMedia(ctrl)), reactionTypes, mediaValidator, storage, nil, publisher)
 
 	req := &dto.CreateFeedRequest{
 		Caption: "test caption",
 		UserID:  uuid.New(),
 	}
 
-	// Dummy file header (valid & minimal)
-	fileHeader := &multipart.FileHeader{
-		Filename: "test.jpg",
-		Size:     10,
-	}
-	files := []*multipart.FileHeader{fileHeader}
+	files := []*multipart.FileHeader{newFileHeader(t, "test.png", pngBytes(t, 4, 4))}
 
 	mockFeed := &entities.Feed{
 		ID:      uuid.New(),
@@ -57,8 +80,9 @@ func TestFeedService_CreateFeed(t *testing.T) {
 				Type: "image",
 			},
 		},
-		Likes:    5,
-		Comments: 2,
+		ReactionCount: 5,
+		Reactions:     map[string]int{"like": 3, "love": 2},
+		Comments:      2,
 	}
 
 	// EXPECTATIONS
@@ -67,6 +91,21 @@ func TestFeedService_CreateFeed(t *testing.T) {
 		Create(gomock.Any(), gomock.Any()).
 		Return(mockFeed, nil)
 
+	mediaID := uuid.New()
+
+	feedRepo.
+		EXPECT().
+		AddMedia(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, media *entities.FeedMedia) error {
+			assert.Equal(t, mockFeed.ID, media.FeedId)
+			assert.Equal(t, entities.MediaImage, media.Type)
+			assert.Equal(t, entities.MediaPending, media.Status)
+			assert.Equal(t, 4, media.Width)
+
+			media.ID = mediaID
+			return nil
+		})
+
 	storage.
 		EXPECT().
 		SaveTempFile(gomock.Any(), gomock.Any()).
@@ -80,7 +119,16 @@ func TestFeedService_CreateFeed(t *testing.T) {
 			string(events.UploadFeedMedias),
 			gomock.Any(),
 		).
-		Return(nil)
+		DoAndReturn(func(_, _, _ string, body []byte) error {
+			var payload events.UploadPayload
+			assert.NoError(t, json.Unmarshal(body, &payload))
+
+			content := payload.Content
+			assert.Len(t, content, 1)
+			assert.Equal(t, mediaID.String(), content[0].MediaID)
+			assert.Equal(t, entities.MediaImage, content[0].FileType)
+			return nil
+		})
 
 	// DO
 	res, err := svc.CreateFeed(ctx, req, files)
@@ -88,4 +136,913 @@ func TestFeedService_CreateFeed(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, res)
 	assert.Equal(t, req.Caption, res.Caption)
+	assert.Equal(t, entities.MediaPending, res.Medias[len(res.Medias)-1].Status)
+}
+
+func TestFeedService_CreateFeed_NotifiesMentionedUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+	storage := mocksPkg.NewMockUploadUseCase(ctrl)
+	publisher := mocksPkg.NewMockMessageBroker(ctrl)
+
+	svc := services.NewFeedService(feedRepo, userRepo, notificationRepo, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, storage, nil, publisher)
+
+	req := &dto.CreateFeedRequest{
+		Caption: "liburan bareng @Budi dan @author #Bali #bali",
+		UserID:  uuid.New(),
+	}
+
+	mentionedID := uuid.New()
+
+	feedRepo.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, feed *entities.Feed) (*entities.Feed, error) {
+			assert.Equal(t, []string{"bali"}, feed.Hashtags)
+			assert.Equal(t, []string{"budi", "author"}, feed.Mentions)
+			assert.Equal(t, entities.FeedPublished, feed.Status)
+
+			feed.ID = uuid.New()
+			feed.MentionedUsers = []uuid.UUID{mentionedID, req.UserID}
+			return feed, nil
+		})
+
+	notificationRepo.
+		EXPECT().
+		CreateMany(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, notifications []*entities.Notification) error {
+			assert.Len(t, notifications, 1)
+			assert.Equal(t, mentionedID, notifications[0].UserID)
+			assert.Equal(t, entities.NotificationFeedMention, notifications[0].Type)
+			return nil
+		})
+
+	res, err := svc.CreateFeed(ctx, req, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, res.Entities, 4)
+	assert.Equal(t, "mention", res.Entities[0].Type)
+	assert.Equal(t, "budi", res.Entities[0].Value)
+}
+
+func TestFeedService_CreateFeed_ScheduledDoesNotNotify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+	publisher := mocksPkg.NewMockMessageBroker(ctrl)
+
+	svc := services.NewFeedService(feedRepo, nil, notificationRepo, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, nil, nil, publisher)
+
+	publishAt := time.Now().Add(time.Hour)
+	req := &dto.CreateFeedRequest{
+		Caption:   "nanti ya @budi",
+		PublishAt: &publishAt,
+		UserID:    uuid.New(),
+	}
+
+	feedRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, feed *entities.Feed) (*entities.Feed, error) {
+			assert.Equal(t, entities.FeedScheduled, feed.Status)
+			assert.True(t, publishAt.Equal(*feed.PublishAt))
+
+			feed.ID = uuid.New()
+			feed.MentionedUsers = []uuid.UUID{uuid.New()}
+			return feed, nil
+		})
+	res, err := svc.CreateFeed(context.Background(), req, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.FeedScheduled, res.Status)
+	assert.True(t, publishAt.Equal(*res.PublishAt))
+}
+
+func TestFeedService_CreateFeed_StoresPublishAtInUTC(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+
+	svc := services.NewFeedService(feedRepo, nil, nil, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, nil, nil, nil)
+
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	publishAt := time.Now().Add(time.Hour).In(jakarta)
+	req := &dto.CreateFeedRequest{
+		Caption:   "pagi",
+		PublishAt: &publishAt,
+		UserID:    uuid.New(),
+	}
+
+	feedRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, feed *entities.Feed) (*entities.Feed, error) {
+			require.NotNil(t, feed.PublishAt)
+			assert.Equal(t, time.UTC, feed.PublishAt.Location())
+			assert.True(t, feed.PublishAt.Equal(publishAt))
+			assert.Equal(t, publishAt.UTC().Hour(), feed.PublishAt.Hour())
+
+			feed.ID = uuid.New()
+			return feed, nil
+		})
+
+	_, err := svc.CreateFeed(context.Background(), req, nil)
+
+	require.NoError(t, err)
+}
+
+func TestFeedService_CreateFeed_RejectsPastPublishAt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := services.NewFeedService(mocksRepo.NewMockFeedRepository(ctrl), nil, nil, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, nil, nil, nil)
+
+	publishAt := time.Now().Add(-time.Minute)
+	req := &dto.CreateFeedRequest{
+		Caption:   "kemarin",
+		Status:    entities.FeedScheduled,
+		PublishAt: &publishAt,
+		UserID:    uuid.New(),
+	}
+
+	_, err := svc.CreateFeed(context.Background(), req, nil)
+
+	assert.ErrorIs(t, err, services.ErrInvalidPublishAt)
+}
+
+func TestFeedService_UpdateDraft_PublishNotifiesMentions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewFeedService(feedRepo, nil, notificationRepo, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, nil, nil, nil)
+
+	req := &dto.UpdateDraftRequest{
+		FeedID:  uuid.New(),
+		Caption: "akhirnya @budi",
+		Status:  entities.FeedPublished,
+		UserID:  uuid.New(),
+	}
+
+	mentionedID := uuid.New()
+
+	feedRepo.EXPECT().FindOwnerID(ctx, req.FeedID).Return(req.UserID, nil)
+	feedRepo.EXPECT().UpdateDraft(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, feed *entities.Feed) (*entities.Feed, error) {
+			assert.Equal(t, entities.FeedPublished, feed.Status)
+			assert.Nil(t, feed.PublishAt)
+
+			// Budi was mentioned while drafting, so syncing adds no one.
+			feed.MentionedUsers = []uuid.UUID{}
+			return feed, nil
+		})
+	feedRepo.EXPECT().FindMentionedUsers(ctx, req.FeedID).Return([]uuid.UUID{mentionedID}, nil)
+	notificationRepo.EXPECT().CreateMany(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, notifications []*entities.Notification) error {
+			assert.Len(t, notifications, 1)
+			assert.Equal(t, mentionedID, notifications[0].UserID)
+			return nil
+		})
+	feedRepo.EXPECT().FindMedias(ctx, req.FeedID).Return([]*entities.FeedMedia{}, nil)
+
+	res, err := svc.UpdateDraft(ctx, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.FeedPublished, res.Status)
+}
+
+func TestFeedService_UpdateDraft_AlreadyPublished(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+
+	svc := services.NewFeedService(feedRepo, nil, nil, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, nil, nil, nil)
+
+	req := &dto.UpdateDraftRequest{
+		FeedID:  uuid.New(),
+		Caption: "sudah terbit",
+		UserID:  uuid.New(),
+	}
+
+	feedRepo.EXPECT().FindOwnerID(ctx, req.FeedID).Return(req.UserID, nil)
+	feedRepo.EXPECT().UpdateDraft(ctx, gomock.Any()).Return(nil, repositories.ErrFeedNotFound)
+
+	_, err := svc.UpdateDraft(ctx, req)
+
+	assert.ErrorIs(t, err, services.ErrFeedPublished)
+}
+
+func TestFeedService_CreateFeed_KeepsMediaOrderAndDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	storage := mocksPkg.NewMockUploadUseCase(ctrl)
+	publisher := mocksPkg.NewMockMessageBroker(ctrl)
+
+	svc := services.NewFeedService(feedRepo, nil, nil, newContentModerator(t, nil, unblockedMedia(ctrl)), reactionTypes, mediaValidator, storage, nil, publisher)
+
+	files := []*multipart.FileHeader{
+		newFileHeader(t, "first.png", pngBytes(t, 4, 4)),
+		newFileHeader(t, "second.png", pngBytes(t, 8, 8)),
+	}
+
+	req := &dto.CreateFeedRequest{
+		Caption:       "pantai",
+		AltTexts:      []string{"sunset over the sea", "two dogs on the sand"},
+		MediaCaptions: []string{"", "best friends"},
+		UserID:        uuid.New(),
+	}
+
+	_, err := svc.CreateFeed(context.Background(), &dto.CreateFeedRequest{Caption: "pantai", AltTexts: []string{"a", "b", "c"}, UserID: req.UserID}, files)
+	assert.ErrorIs(t, err, services.ErrTooManyMediaDetails)
+
+	feedRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, feed *entities.Feed) (*entities.Feed, error) {
+			feed.ID = uuid.New()
+			return feed, nil
+		})
+
+	var added []*entities.FeedMedia
+	feedRepo.EXPECT().AddMedia(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, media *entities.FeedMedia) error {
+			media.ID = uuid.New()
+			added = append(added, media)
+			return nil
+		}).
+		Times(2)
+	storage.EXPECT().SaveTempFile(gomock.Any(), gomock.Any()).Return("/tmp/media", nil).Times(2)
+	publisher.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	res, err := svc.CreateFeed(context.Background(), req, files)
+
+	assert.NoError(t, err)
+	require.Len(t, added, 2)
+	assert.Equal(t, 0, added[0].Position)
+	assert.Equal(t, 4, added[0].Width)
+	assert.Equal(t, "sunset over the sea", added[0].AltText)
+	assert.Equal(t, 1, added[1].Position)
+	assert.Equal(t, 8, added[1].Width)
+	assert.Equal(t, "best friends", added[1].Caption)
+	assert.Equal(t, "two dogs on the sand", res.Medias[1].AltText)
+}
+
+func TestFeedService_ReorderMedias(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	svc := services.NewFeedService(feedRepo, nil, nil, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, nil, nil, nil)
+
+	feedID, ownerID := uuid.New(), uuid.New()
+	first, second := uuid.New(), uuid.New()
+
+	feedRepo.EXPECT().FindOwnerID(gomock.Any(), feedID).Return(ownerID, nil).Times(3)
+	feedRepo.EXPECT().FindMedias(gomock.Any(), feedID).Return([]*entities.FeedMedia{
+		{ID: first, FeedId: feedID, Position: 0},
+		{ID: second, FeedId: feedID, Position: 1},
+	}, nil).Times(3)
+
+	_, err := svc.ReorderMedias(context.Background(), &dto.ReorderMediasRequest{FeedID: feedID, MediaIDs: []uuid.UUID{second, second}, UserID: ownerID})
+	assert.ErrorIs(t, err, services.ErrInvalidMediaOrder)
+
+	_, err = svc.ReorderMedias(context.Background(), &dto.ReorderMediasRequest{FeedID: feedID, MediaIDs: []uuid.UUID{second}, UserID: ownerID})
+	assert.ErrorIs(t, err, services.ErrInvalidMediaOrder)
+
+	feedRepo.EXPECT().ReorderMedias(gomock.Any(), feedID, []uuid.UUID{second, first}).Return(nil)
+
+	medias, err := svc.ReorderMedias(context.Background(), &dto.ReorderMediasRequest{FeedID: feedID, MediaIDs: []uuid.UUID{second, first}, UserID: ownerID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, second, medias[0].ID)
+	assert.Equal(t, 0, medias[0].Position)
+	assert.Equal(t, first, medias[1].ID)
+	assert.Equal(t, 1, medias[1].Position)
+}
+
+func TestFeedService_UpdateMedia_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	svc := services.NewFeedService(feedRepo, nil, nil, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, nil, nil, nil)
+
+	feedID, ownerID := uuid.New(), uuid.New()
+
+	feedRepo.EXPECT().FindOwnerID(gomock.Any(), feedID).Return(ownerID, nil)
+	feedRepo.EXPECT().UpdateMediaDetails(gomock.Any(), gomock.Any()).Return(nil, repositories.ErrMediaNotFound)
+
+	_, err := svc.UpdateMedia(context.Background(), &dto.UpdateMediaRequest{FeedID: feedID, MediaID: uuid.New(), AltText: "a cat", UserID: ownerID})
+
+	assert.ErrorIs(t, err, services.ErrMediaNotFound)
+}
+
+func TestFeedService_CreateFeed_WithUploads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	publisher := mocksPkg.NewMockMessageBroker(ctrl)
+	resumable := upload.NewResumableStore(t.TempDir())
+
+	svc := services.NewFeedService(feedRepo, nil, nil, newContentModerator(t, nil, unblockedMedia(ctrl)), reactionTypes, mediaValidator, nil, resumable, publisher)
+
+	userID := uuid.New()
+	content := pngBytes(t, 4, 4)
+
+	complete, err := resumable.Create(userID.String(), int64(len(content)), map[string]string{"filename": "photo.png"})
+	require.NoError(t, err)
+	_, err = resumable.Append(complete.ID, 0, bytes.NewReader(content))
+	require.NoError(t, err)
+
+	partial, err := resumable.Create(userID.String(), int64(len(content)), nil)
+	require.NoError(t, err)
+
+	others, err := resumable.Create(uuid.NewString(), int64(len(content)), nil)
+	require.NoError(t, err)
+
+	_, err = svc.CreateFeed(ctx, &dto.CreateFeedRequest{Caption: "hi", UserID: userID, UploadIDs: []string{others.ID}}, nil)
+	assert.ErrorIs(t, err, services.ErrUploadNotFound)
+
+	_, err = svc.CreateFeed(ctx, &dto.CreateFeedRequest{Caption: "hi", UserID: userID, UploadIDs: []string{partial.ID}}, nil)
+	assert.ErrorIs(t, err, services.ErrUploadIncomplete)
+
+	feedRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, feed *entities.Feed) (*entities.Feed, error) {
+			feed.ID = uuid.New()
+			return feed, nil
+		})
+	feedRepo.EXPECT().AddMedia(gomock.Any(), gomock.Any()).Return(nil)
+	publisher.EXPECT().Publish("", "events", string(events.UploadFeedMedias), gomock.Any()).
+		DoAndReturn(func(_, _, _ string, body []byte) error {
+			var payload events.UploadPayload
+			require.NoError(t, json.Unmarshal(body, &payload))
+			require.Len(t, payload.Content, 1)
+
+			claimed, err := os.ReadFile(payload.Content[0].FilePath)
+			require.NoError(t, err)
+			assert.Equal(t, content, claimed)
+			return nil
+		})
+
+	_, err = svc.CreateFeed(ctx, &dto.CreateFeedRequest{Caption: "hi", UserID: userID, UploadIDs: []string{complete.ID}}, nil)
+
+	assert.NoError(t, err)
+	_, err = resumable.Get(complete.ID)
+	assert.ErrorIs(t, err, upload.ErrUploadNotFound)
+}
+
+func TestFeedService_CreateFeed_DuplicateUploadIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	publisher := mocksPkg.NewMockMessageBroker(ctrl)
+	resumable := upload.NewResumableStore(t.TempDir())
+
+	svc := services.NewFeedService(feedRepo, nil, nil, newContentModerator(t, nil, unblockedMedia(ctrl)), reactionTypes, mediaValidator, nil, resumable, publisher)
+
+	userID := uuid.New()
+	content := pngBytes(t, 4, 4)
+
+	complete, err := resumable.Create(userID.String(), int64(len(content)), map[string]string{"filename": "photo.png"})
+	require.NoError(t, err)
+	_, err = resumable.Append(complete.ID, 0, bytes.NewReader(content))
+	require.NoError(t, err)
+
+	feedRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, feed *entities.Feed) (*entities.Feed, error) {
+			feed.ID = uuid.New()
+			return feed, nil
+		})
+	feedRepo.EXPECT().AddMedia(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	publisher.EXPECT().Publish("", "events", string(events.UploadFeedMedias), gomock.Any()).
+		DoAndReturn(func(_, _, _ string, body []byte) error {
+			var payload events.UploadPayload
+			require.NoError(t, json.Unmarshal(body, &payload))
+			assert.Len(t, payload.Content, 1)
+			return nil
+		})
+
+	feed, err := svc.CreateFeed(ctx, &dto.CreateFeedRequest{Caption: "hi", UserID: userID, UploadIDs: []string{complete.ID, complete.ID}}, nil)
+
+	require.NoError(t, err)
+	assert.Len(t, feed.Medias, 1)
+}
+
+func TestFeedService_CreateFeed_ReleasesUploadsOnFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	resumable := upload.NewResumableStore(t.TempDir())
+
+	svc := services.NewFeedService(feedRepo, nil, nil, newContentModerator(t, nil, unblockedMedia(ctrl)), reactionTypes, mediaValidator, nil, resumable, nil)
+
+	userID := uuid.New()
+	content := pngBytes(t, 4, 4)
+
+	complete, err := resumable.Create(userID.String(), int64(len(content)), map[string]string{"filename": "photo.png"})
+	require.NoError(t, err)
+	_, err = resumable.Append(complete.ID, 0, bytes.NewReader(content))
+	require.NoError(t, err)
+
+	feedRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("db down"))
+
+	_, err = svc.CreateFeed(ctx, &dto.CreateFeedRequest{Caption: "hi", UserID: userID, UploadIDs: []string{complete.ID}}, nil)
+
+	assert.Error(t, err)
+
+	found, err := resumable.Get(complete.ID)
+	require.NoError(t, err)
+	assert.True(t, found.Complete())
+	assert.Equal(t, "photo.png", found.Name())
+}
+
+func TestFeedService_CreateFeed_RejectsInvalidFiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	svc := services.NewFeedService(feedRepo, nil, nil, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, nil, nil, nil)
+
+	files := []*multipart.FileHeader{
+		newFileHeader(t, "ok.png", pngBytes(t, 4, 4)),
+		newFileHeader(t, "notes.jpg", []byte("just some text")),
+		newFileHeader(t, "huge.png", pngBytes(t, 200, 50)),
+	}
+
+	_, err := svc.CreateFeed(context.Background(), &dto.CreateFeedRequest{Caption: "hi", UserID: uuid.New()}, files[1:])
+
+	var invalid *mediacheck.ValidationError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Len(t, invalid.Files, 2)
+	assert.Equal(t, 0, invalid.Files[0].Index)
+	assert.Equal(t, "notes.jpg", invalid.Files[0].Filename)
+	assert.Equal(t, "huge.png", invalid.Files[1].Filename)
+
+	_, err = svc.CreateFeed(context.Background(), &dto.CreateFeedRequest{Caption: "hi", UserID: uuid.New()}, []*multipart.FileHeader{files[0], files[0], files[0]})
+
+	assert.ErrorAs(t, err, &invalid)
+	assert.Empty(t, invalid.Files)
+}
+
+func TestFeedService_CreateFeed_RejectsBlockedMedia(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	mediaRepo := mocksRepo.NewMockMediaRepository(ctrl)
+	svc := services.NewFeedService(feedRepo, nil, nil, newContentModerator(t, nil, mediaRepo), reactionTypes, mediaValidator, nil, nil, nil)
+
+	content := pngBytes(t, 4, 4)
+	digest := sha256.Sum256(content)
+
+	mediaRepo.EXPECT().IsBlocked(gomock.Any(), hex.EncodeToString(digest[:]), gomock.Not(gomock.Nil()), 6).Return(true, nil)
+
+	_, err := svc.CreateFeed(context.Background(), &dto.CreateFeedRequest{Caption: "hi", UserID: uuid.New()}, []*multipart.FileHeader{newFileHeader(t, "removed.png", content)})
+
+	assert.ErrorIs(t, err, services.ErrMediaBlocked)
+}
+
+func TestFeedService_GetFeedMedias(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	svc := services.NewFeedService(feedRepo, nil, nil, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, nil, nil, nil)
+
+	feedID, viewerID := uuid.New(), uuid.New()
+
+	feedRepo.EXPECT().IsVisible(gomock.Any(), feedID, viewerID).Return(true, nil)
+	feedRepo.EXPECT().FindMedias(gomock.Any(), feedID).Return([]*entities.FeedMedia{
+		{ID: uuid.New(), FeedId: feedID, Type: entities.MediaVideo, Status: entities.MediaProcessing, DurationMs: 4000},
+	}, nil)
+
+	medias, err := svc.GetFeedMedias(context.Background(), feedID, viewerID)
+
+	assert.NoError(t, err)
+	assert.Len(t, medias, 1)
+	assert.Equal(t, entities.MediaProcessing, medias[0].Status)
+	assert.Equal(t, 4000, medias[0].DurationMs)
+}
+
+func TestFeedService_UpdateFeedCaption_NotOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+	storage := mocksPkg.NewMockUploadUseCase(ctrl)
+	publisher := mocksPkg.NewMockMessageBroker(ctrl)
+
+	svc := services.NewFeedService(feedRepo, userRepo, notificationRepo, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, storage, nil, publisher)
+
+	req := &dto.UpdateFeedRequest{
+		FeedID:  uuid.New(),
+		Caption: "new caption",
+		UserID:  uuid.New(),
+	}
+
+	feedRepo.EXPECT().FindOwnerID(ctx, req.FeedID).Return(uuid.New(), nil)
+
+	_, err := svc.UpdateFeedCaption(ctx, req)
+
+	assert.ErrorIs(t, err, services.ErrNotOwner)
+}
+
+func TestFeedService_ReactToFeed_ChangesReaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+	storage := mocksPkg.NewMockUploadUseCase(ctrl)
+	publisher := mocksPkg.NewMockMessageBroker(ctrl)
+
+	svc := services.NewFeedService(feedRepo, userRepo, notificationRepo, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, storage, nil, publisher)
+
+	feedID := uuid.New()
+	userID := uuid.New()
+
+	feedRepo.EXPECT().IsVisible(ctx, feedID, userID).Return(true, nil)
+	feedRepo.EXPECT().FindReaction(ctx, feedID, userID).Return("like", nil)
+	feedRepo.EXPECT().SetReaction(ctx, feedID, userID, "love").Return(nil)
+
+	status, err := svc.ReactToFeed(ctx, &dto.ReactFeedRequest{FeedID: feedID, Type: "Love", UserID: userID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "changed", status)
+}
+
+func TestFeedService_LikeFeed_RemovesLike(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+	storage := mocksPkg.NewMockUploadUseCase(ctrl)
+	publisher := mocksPkg.NewMockMessageBroker(ctrl)
+
+	svc := services.NewFeedService(feedRepo, userRepo, notificationRepo, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, storage, nil, publisher)
+
+	feedID := uuid.New()
+	userID := uuid.New()
+
+	feedRepo.EXPECT().IsVisible(ctx, feedID, userID).Return(true, nil)
+	feedRepo.EXPECT().FindReaction(ctx, feedID, userID).Return("like", nil)
+	feedRepo.EXPECT().RemoveReaction(ctx, feedID, userID).Return(nil)
+
+	status, err := svc.LikeFeed(ctx, feedID, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "unliked", status)
+}
+
+func TestFeedService_ReactToFeed_InvalidType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+	storage := mocksPkg.NewMockUploadUseCase(ctrl)
+	publisher := mocksPkg.NewMockMessageBroker(ctrl)
+
+	svc := services.NewFeedService(feedRepo, userRepo, notificationRepo, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, storage, nil, publisher)
+
+	_, err := svc.ReactToFeed(ctx, &dto.ReactFeedRequest{FeedID: uuid.New(), Type: "party", UserID: uuid.New()})
+
+	assert.ErrorIs(t, err, services.ErrInvalidReaction)
+}
+
+// newFileHeader builds a multipart file the way an upload request would.
+func TestFeedService_GetExploreFeeds_KeepsRanking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	svc := services.NewFeedService(feedRepo, userRepo, nil, nil, reactionTypes, nil, nil, nil, nil)
+
+	userID := uuid.New()
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	ranked := []uuid.UUID{first, second, third}
+
+	userRepo.EXPECT().FindFollowingIDs(userID).Return(nil, nil)
+	userRepo.EXPECT().FindBlockedIDs(userID).Return(nil, nil)
+	userRepo.EXPECT().FindMutedIDs(userID).Return(nil, nil)
+	feedRepo.EXPECT().GetExploreFeedIDs(ctx, userID, gomock.Any(), 3).Return(ranked, nil)
+	// The second feed was hidden between ranking and loading.
+	feedRepo.EXPECT().GetFeedsByIDs(ctx, ranked, userID).Return([]*entities.Feed{
+		{ID: first, UserID: uuid.New()},
+		{ID: third, UserID: uuid.New()},
+	}, nil)
+
+	feeds, err := svc.GetExploreFeeds(ctx, userID, 3)
+
+	require.NoError(t, err)
+	require.Len(t, feeds, 2)
+	assert.Equal(t, first, feeds[0].ID)
+	assert.Equal(t, third, feeds[1].ID)
+}
+
+func TestFeedService_GetExploreFeeds_ExcludesAuthors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	svc := services.NewFeedService(feedRepo, userRepo, nil, nil, reactionTypes, nil, nil, nil, nil)
+
+	userID := uuid.New()
+	followed, blocked, blockedBy, muted := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	userRepo.EXPECT().FindFollowingIDs(userID).Return([]uuid.UUID{followed}, nil)
+	userRepo.EXPECT().FindBlockedIDs(userID).Return([]uuid.UUID{blocked, blockedBy}, nil)
+	userRepo.EXPECT().FindMutedIDs(userID).Return([]uuid.UUID{muted}, nil)
+	feedRepo.EXPECT().GetExploreFeedIDs(ctx, userID, gomock.Any(), 20).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, excluded []uuid.UUID, _ int) ([]uuid.UUID, error) {
+			assert.ElementsMatch(t, []uuid.UUID{userID, followed, blocked, blockedBy, muted}, excluded)
+			return []uuid.UUID{}, nil
+		})
+	feedRepo.EXPECT().GetFeedsByIDs(ctx, []uuid.UUID{}, userID).Return([]*entities.Feed{}, nil)
+
+	feeds, err := svc.GetExploreFeeds(ctx, userID, 20)
+
+	require.NoError(t, err)
+	assert.Empty(t, feeds)
+}
+
+func TestFeedService_GetExploreFeeds_StopsOnLookupError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	svc := services.NewFeedService(mocksRepo.NewMockFeedRepository(ctrl), userRepo, nil, nil, reactionTypes, nil, nil, nil, nil)
+
+	userID := uuid.New()
+	lookupErr := errors.New("db down")
+
+	userRepo.EXPECT().FindFollowingIDs(userID).Return(nil, nil)
+	userRepo.EXPECT().FindBlockedIDs(userID).Return(nil, lookupErr)
+
+	_, err := svc.GetExploreFeeds(context.Background(), userID, 20)
+
+	assert.ErrorIs(t, err, lookupErr)
+}
+
+func TestFeedService_MarkFeedsSeen(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+
+	tooMany := make([]uuid.UUID, 101)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+
+	tests := []struct {
+		name    string
+		feedIDs []uuid.UUID
+		marked  []uuid.UUID
+		err     error
+	}{
+		{name: "distinct feeds", feedIDs: []uuid.UUID{first, second}, marked: []uuid.UUID{first, second}},
+		{name: "duplicates and nil ids", feedIDs: []uuid.UUID{first, uuid.Nil, first, second}, marked: []uuid.UUID{first, second}},
+		{name: "as many as allowed", feedIDs: tooMany[:100], marked: tooMany[:100]},
+		{name: "only nil ids", feedIDs: []uuid.UUID{uuid.Nil}, err: services.ErrInvalidSeenFeeds},
+		{name: "no feeds", feedIDs: nil, err: services.ErrInvalidSeenFeeds},
+		{name: "too many feeds", feedIDs: tooMany, err: services.ErrInvalidSeenFeeds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+
+			feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+			svc := services.NewFeedService(feedRepo, nil, nil, nil, reactionTypes, nil, nil, nil, nil)
+
+			userID := uuid.New()
+
+			if tt.marked != nil {
+				feedRepo.EXPECT().MarkSeen(ctx, userID, tt.marked).Return(nil)
+			}
+
+			err := svc.MarkFeedsSeen(ctx, &dto.MarkFeedsSeenRequest{FeedIDs: tt.feedIDs, UserID: userID})
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func newFileHeader(t *testing.T, filename string, content []byte) *multipart.FileHeader {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("files", filename)
+	assert.NoError(t, err)
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(1 << 20)
+	assert.NoError(t, err)
+
+	return form.File["files"][0]
+}
+
+// pngBytes encodes an opaque width x height PNG.
+func pngBytes(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{R: 200, G: 80, B: 40, A: 255}), image.Point{}, draw.Src)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, png.Encode(buf, img))
+	return buf.Bytes()
+}
+
+func TestFeedService_RepostFeed_Toggles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewFeedService(feedRepo, nil, notificationRepo, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, nil, nil, nil)
+
+	feedID, userID, ownerID := uuid.New(), uuid.New(), uuid.New()
+
+	feedRepo.EXPECT().IsVisible(ctx, feedID, userID).Return(true, nil).Times(2)
+
+	gomock.InOrder(
+		feedRepo.EXPECT().RemoveRepost(ctx, feedID, userID).Return(false, nil),
+		feedRepo.EXPECT().AddRepost(ctx, feedID, userID).Return(true, nil),
+		feedRepo.EXPECT().FindOwnerID(ctx, feedID).Return(ownerID, nil),
+		notificationRepo.EXPECT().CreateMany(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, notifications []*entities.Notification) error {
+				assert.Len(t, notifications, 1)
+				assert.Equal(t, ownerID, notifications[0].UserID)
+				assert.Equal(t, entities.NotificationRepost, notifications[0].Type)
+				return nil
+			}),
+		feedRepo.EXPECT().RemoveRepost(ctx, feedID, userID).Return(true, nil),
+	)
+
+	status, err := svc.RepostFeed(ctx, feedID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "reposted", status)
+
+	status, err = svc.RepostFeed(ctx, feedID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "unreposted", status)
+}
+
+func TestFeedService_CreateFeed_QuotePost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	// No media are sent, so nothing is queued for upload.
+	svc := services.NewFeedService(feedRepo, nil, notificationRepo, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, nil, nil, mocksPkg.NewMockMessageBroker(ctrl))
+
+	quoted := &entities.Feed{ID: uuid.New(), UserID: uuid.New(), Caption: "aslinya", User: &entities.User{Username: "budi"}}
+	req := &dto.CreateFeedRequest{
+		Caption:      "setuju banget",
+		QuotedFeedID: &quoted.ID,
+		UserID:       uuid.New(),
+	}
+
+	feedRepo.EXPECT().IsVisible(ctx, quoted.ID, req.UserID).Return(true, nil)
+	feedRepo.EXPECT().Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, feed *entities.Feed) (*entities.Feed, error) {
+			assert.Equal(t, &quoted.ID, feed.QuotedFeedID)
+
+			feed.ID = uuid.New()
+			return feed, nil
+		})
+	feedRepo.EXPECT().FindOwnerID(ctx, quoted.ID).Return(quoted.UserID, nil)
+	notificationRepo.EXPECT().CreateMany(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, notifications []*entities.Notification) error {
+			assert.Len(t, notifications, 1)
+			assert.Equal(t, quoted.UserID, notifications[0].UserID)
+			assert.Equal(t, entities.NotificationQuote, notifications[0].Type)
+			return nil
+		})
+	feedRepo.EXPECT().GetFeedsByIDs(ctx, []uuid.UUID{quoted.ID}, req.UserID).Return([]*entities.Feed{quoted}, nil)
+
+	res, err := svc.CreateFeed(ctx, req, nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, res.Medias)
+	require.NotNil(t, res.QuotedFeed)
+	assert.Equal(t, "aslinya", res.QuotedFeed.Caption)
+	assert.False(t, res.QuoteUnavailable)
+}
+
+func TestFeedService_CreateFeed_QuoteOfPrivateFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+
+	svc := services.NewFeedService(feedRepo, nil, nil, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, nil, nil, nil)
+
+	quotedID := uuid.New()
+	req := &dto.CreateFeedRequest{
+		Caption:      "ikut",
+		QuotedFeedID: &quotedID,
+		UserID:       uuid.New(),
+	}
+
+	feedRepo.EXPECT().IsVisible(ctx, quotedID, req.UserID).Return(false, nil)
+
+	_, err := svc.CreateFeed(ctx, req, nil)
+
+	assert.ErrorIs(t, err, services.ErrPrivateAccount)
+}
+
+func TestFeedService_GetFeeds_RepostsAndUnavailableQuotes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+
+	svc := services.NewFeedService(feedRepo, nil, nil, newContentModerator(t, nil, nil), reactionTypes, mediaValidator, nil, nil, nil)
+
+	userID := uuid.New()
+	goneID := uuid.New()
+
+	reposted := &entities.Feed{
+		ID:         uuid.New(),
+		Caption:    "dibagikan",
+		Reposts:    3,
+		RepostedBy: &entities.User{ID: uuid.New(), Username: "budi"},
+	}
+	quote := &entities.Feed{ID: uuid.New(), Caption: "kutipan", QuotedFeedID: &goneID}
+
+	feedRepo.EXPECT().GetFeeds(ctx, userID, gomock.Any()).Return([]*entities.Feed{reposted, quote}, nil)
+	// The quoted feed was deleted or its author went private.
+	feedRepo.EXPECT().GetFeedsByIDs(ctx, []uuid.UUID{goneID}, userID).Return([]*entities.Feed{}, nil)
+
+	res, err := svc.GetFeeds(ctx, userID)
+
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+
+	assert.Equal(t, 3, res[0].Reposts)
+	require.NotNil(t, res[0].RepostedBy)
+	assert.Equal(t, "budi", res[0].RepostedBy.Username)
+
+	assert.Equal(t, &goneID, res[1].QuotedFeedID)
+	assert.Nil(t, res[1].QuotedFeed)
+	assert.True(t, res[1].QuoteUnavailable)
 }
@@ -0,0 +1,141 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/services"
+	mocksRepo "github.com/davidafdal/post-app/mocks/repositories"
+	"github.com/davidafdal/post-app/pkg/storage"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// janitorFixture stores a referenced feed file, an orphaned feed file, an
+// orphaned avatar stored just now, the file of a blob no media uses any more
+// and a stale temp file.
+type janitorFixture struct {
+	root, tempDir, tempFile string
+	local                   *storage.Local
+	referenced              *storage.Object
+	blob                    *entities.MediaBlob
+}
+
+func newJanitorFixture(t *testing.T) *janitorFixture {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	local, err := storage.NewLocal(root, "http://localhost/media", "secret")
+	require.NoError(t, err)
+
+	old := time.Now().Add(-48 * time.Hour)
+
+	put := func(key string, modTime time.Time) *storage.Object {
+		object, err := local.Put(ctx, key, strings.NewReader("data"), 4, "text/plain")
+		require.NoError(t, err)
+		require.NoError(t, os.Chtimes(filepath.Join(root, key), modTime, modTime))
+		return object
+	}
+
+	referenced := put("feeds/f1/kept.jpg", old)
+	put("feeds/f1/orphan.jpg", old)
+	put("avatars/fresh.png", time.Now())
+	blobObject := put("feeds/f2/m1/full.jpg", old)
+	blob := &entities.MediaBlob{ID: uuid.New(), Url: blobObject.URL, Keys: []string{blobObject.Key}}
+
+	tempDir := t.TempDir()
+	tempFile := filepath.Join(tempDir, "upload.jpg")
+	require.NoError(t, os.WriteFile(tempFile, []byte("temp"), 0o644))
+	require.NoError(t, os.Chtimes(tempFile, old, old))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "recent.jpg"), []byte("temp"), 0o644))
+
+	return &janitorFixture{root: root, tempDir: tempDir, tempFile: tempFile, local: local, referenced: referenced, blob: blob}
+}
+
+func (f *janitorFixture) options(dryRun bool) services.JanitorOptions {
+	return services.JanitorOptions{
+		TempDir:        f.tempDir,
+		TempFileMaxAge: 24 * time.Hour,
+		OrphanMinAge:   24 * time.Hour,
+		DryRun:         dryRun,
+	}
+}
+
+func TestJanitorService_Clean(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixture := newJanitorFixture(t)
+	mediaRepo := mocksRepo.NewMockMediaRepository(ctrl)
+	mediaRepo.EXPECT().FindUnreferencedBlobs(gomock.Any(), gomock.Any()).Return([]*entities.MediaBlob{fixture.blob}, nil)
+	mediaRepo.EXPECT().DeleteBlob(gomock.Any(), fixture.blob.ID, gomock.Any()).Return(nil)
+	mediaRepo.EXPECT().FindReferencedURLs(gomock.Any()).Return([]string{fixture.referenced.URL, "https://elsewhere.example/a.jpg"}, nil)
+
+	report, err := services.NewJanitorService(mediaRepo, fixture.local, fixture.options(false)).Clean(ctx)
+	require.NoError(t, err)
+
+	assert.False(t, report.DryRun)
+	assert.Equal(t, []string{fixture.tempFile}, report.TempFiles)
+	assert.Equal(t, []string{"feeds/f2/m1/full.jpg", "feeds/f1/orphan.jpg"}, report.Objects)
+
+	assert.NoFileExists(t, fixture.tempFile)
+	assert.FileExists(t, filepath.Join(fixture.tempDir, "recent.jpg"))
+
+	_, err = fixture.local.Stat(ctx, "feeds/f1/orphan.jpg")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = fixture.local.Stat(ctx, "feeds/f2/m1/full.jpg")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = fixture.local.Stat(ctx, "feeds/f1/kept.jpg")
+	assert.NoError(t, err)
+	_, err = fixture.local.Stat(ctx, "avatars/fresh.png")
+	assert.NoError(t, err)
+}
+
+func TestJanitorService_Clean_DryRun(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixture := newJanitorFixture(t)
+	mediaRepo := mocksRepo.NewMockMediaRepository(ctrl)
+	mediaRepo.EXPECT().FindUnreferencedBlobs(gomock.Any(), gomock.Any()).Return([]*entities.MediaBlob{fixture.blob}, nil)
+	mediaRepo.EXPECT().FindReferencedURLs(gomock.Any()).Return([]string{fixture.referenced.URL, fixture.blob.Url}, nil)
+
+	report, err := services.NewJanitorService(mediaRepo, fixture.local, fixture.options(true)).Clean(ctx)
+	require.NoError(t, err)
+
+	assert.True(t, report.DryRun)
+	assert.Equal(t, []string{fixture.tempFile}, report.TempFiles)
+	assert.Equal(t, []string{"feeds/f2/m1/full.jpg", "feeds/f1/orphan.jpg"}, report.Objects)
+
+	assert.FileExists(t, fixture.tempFile)
+	_, err = fixture.local.Stat(ctx, "feeds/f1/orphan.jpg")
+	assert.NoError(t, err)
+}
+
+func TestJanitorService_Clean_KeepsObjectsWhenReferencesFail(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixture := newJanitorFixture(t)
+	mediaRepo := mocksRepo.NewMockMediaRepository(ctrl)
+	mediaRepo.EXPECT().FindUnreferencedBlobs(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mediaRepo.EXPECT().FindReferencedURLs(gomock.Any()).Return(nil, errors.New("db down"))
+
+	report, err := services.NewJanitorService(mediaRepo, fixture.local, fixture.options(false)).Clean(ctx)
+	assert.Error(t, err)
+	assert.Empty(t, report.Objects)
+
+	_, err = fixture.local.Stat(ctx, "feeds/f1/orphan.jpg")
+	assert.NoError(t, err)
+}
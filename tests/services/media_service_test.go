@@ -0,0 +1,392 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/events"
+	"github.com/davidafdal/post-app/internal/repositories"
+	"github.com/davidafdal/post-app/internal/services"
+	mocksPkg "github.com/davidafdal/post-app/mocks/pkg"
+	mocksRepo "github.com/davidafdal/post-app/mocks/repositories"
+	"github.com/davidafdal/post-app/pkg/imageproc"
+	"github.com/davidafdal/post-app/pkg/socket"
+	"github.com/davidafdal/post-app/pkg/storage"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var images = imageproc.New([]imageproc.Variant{
+	{Name: "thumbnail", MaxSize: 8},
+	{Name: "full", MaxSize: 64},
+}, 80)
+
+// gifWithComment returns a small GIF carrying a comment extension, which is
+// metadata the media service must drop.
+func gifWithComment(t *testing.T) []byte {
+	t.Helper()
+
+	img := image.NewPaletted(image.Rect(0, 0, 2, 2), color.Palette{color.Black, color.White})
+
+	var buf bytes.Buffer
+	require.NoError(t, gif.Encode(&buf, img, nil))
+
+	data := buf.Bytes()
+	header := 13
+	if data[10]&0x80 != 0 {
+		header += 3 << ((data[10] & 0x07) + 1)
+	}
+
+	comment := append([]byte{0x21, 0xFE, 6}, "secret"...)
+	comment = append(comment, 0)
+
+	out := append([]byte{}, data[:header]...)
+	out = append(out, comment...)
+	return append(out, data[header:]...)
+}
+
+func TestMediaService_StoreFeedMedias_ProcessesImages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	store := mocksPkg.NewMockStorage(ctrl)
+	publisher := mocksPkg.NewMockPublisher(ctrl)
+	svc := services.NewMediaService(feedRepo, newBlobRepo(ctrl), store, images, nil, publisher)
+
+	feedID, mediaID, ownerID := uuid.New(), uuid.New(), uuid.New()
+	tempPath := filepath.Join(t.TempDir(), "upload.png")
+	assert.NoError(t, os.WriteFile(tempPath, pngBytes(t, 32, 16), 0o644))
+
+	feedRepo.EXPECT().SetMediaStatus(gomock.Any(), mediaID, entities.MediaProcessing).Return(nil)
+	store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "image/jpeg").
+		DoAndReturn(func(_ context.Context, key string, _ any, _ int64, _ string) (*storage.Object, error) {
+			assert.True(t, strings.HasPrefix(key, "feeds/"+feedID.String()+"/"+mediaID.String()+"/"))
+			return &storage.Object{Key: key, URL: "https://cdn/" + key}, nil
+		}).
+		Times(2)
+	feedRepo.EXPECT().UpdateMedia(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, media *entities.FeedMedia) error {
+			assert.Equal(t, mediaID, media.ID)
+			assert.Equal(t, feedID, media.FeedId)
+			assert.NotNil(t, media.BlobID)
+			assert.Equal(t, entities.MediaImage, media.Type)
+			assert.Equal(t, entities.MediaReady, media.Status)
+			assert.Len(t, media.Variants, 2)
+			assert.True(t, strings.HasSuffix(media.Variants["thumbnail"], "/thumbnail.jpg"))
+			assert.Equal(t, media.Variants["full"], media.Url)
+			assert.Equal(t, 32, media.Width)
+			assert.NotEmpty(t, media.Blurhash)
+			return nil
+		})
+	feedRepo.EXPECT().FindOwnerID(gomock.Any(), feedID).Return(ownerID, nil)
+	publisher.EXPECT().Publish(gomock.Any(), ownerID.String(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, event socket.Event) error {
+			processed := event.Data.(*dto.MediaProcessedEvent)
+			assert.Equal(t, services.EventMediaProcessed, event.Type)
+			assert.Equal(t, feedID, processed.FeedID)
+			assert.Equal(t, mediaID, processed.Media.ID)
+			assert.Equal(t, entities.MediaReady, processed.Media.Status)
+			return nil
+		})
+
+	err := svc.StoreFeedMedias(context.Background(), &events.UploadPayload{
+		FeedID:  feedID.String(),
+		Content: []events.ContentData{{MediaID: mediaID.String(), FilePath: tempPath, FileType: "image"}},
+	})
+
+	assert.NoError(t, err)
+	_, err = os.Stat(tempPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestMediaService_StoreFeedMedias_ReusesStoredContent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	mediaRepo := mocksRepo.NewMockMediaRepository(ctrl)
+	store := mocksPkg.NewMockStorage(ctrl)
+	publisher := mocksPkg.NewMockPublisher(ctrl)
+	svc := services.NewMediaService(feedRepo, mediaRepo, store, images, nil, publisher)
+
+	feedID, mediaID := uuid.New(), uuid.New()
+	content := pngBytes(t, 32, 16)
+	tempPath := filepath.Join(t.TempDir(), "upload.png")
+	assert.NoError(t, os.WriteFile(tempPath, content, 0o644))
+
+	digest := sha256.Sum256(content)
+	blob := &entities.MediaBlob{
+		ID:       uuid.New(),
+		SHA256:   hex.EncodeToString(digest[:]),
+		Type:     entities.MediaImage,
+		Url:      "https://cdn/feeds/first/full.jpg",
+		Variants: map[string]string{"full": "https://cdn/feeds/first/full.jpg"},
+		Width:    32,
+		Height:   16,
+	}
+
+	feedRepo.EXPECT().SetMediaStatus(gomock.Any(), mediaID, entities.MediaProcessing).Return(nil)
+	mediaRepo.EXPECT().FindBlob(gomock.Any(), blob.SHA256).Return(blob, nil)
+	feedRepo.EXPECT().UpdateMedia(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, media *entities.FeedMedia) error {
+			assert.Equal(t, &blob.ID, media.BlobID)
+			assert.Equal(t, blob.Url, media.Url)
+			assert.Equal(t, blob.Variants, media.Variants)
+			assert.Equal(t, entities.MediaReady, media.Status)
+			return nil
+		})
+	feedRepo.EXPECT().FindOwnerID(gomock.Any(), feedID).Return(uuid.New(), nil)
+	publisher.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	err := svc.StoreFeedMedias(context.Background(), &events.UploadPayload{
+		FeedID:  feedID.String(),
+		Content: []events.ContentData{{MediaID: mediaID.String(), FilePath: tempPath, FileType: "image"}},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestMediaService_StoreFeedMedias_DropsCopyStoredConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	mediaRepo := mocksRepo.NewMockMediaRepository(ctrl)
+	store := mocksPkg.NewMockStorage(ctrl)
+	publisher := mocksPkg.NewMockPublisher(ctrl)
+	svc := services.NewMediaService(feedRepo, mediaRepo, store, images, nil, publisher)
+
+	tempPath := filepath.Join(t.TempDir(), "upload.gif")
+	assert.NoError(t, os.WriteFile(tempPath, gifWithComment(t), 0o644))
+
+	winner := &entities.MediaBlob{ID: uuid.New(), Type: entities.MediaImage, Url: "https://cdn/feeds/first.gif"}
+
+	gomock.InOrder(
+		mediaRepo.EXPECT().FindBlob(gomock.Any(), gomock.Any()).Return(nil, repositories.ErrBlobNotFound),
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, key string, _ any, _ int64, _ string) (*storage.Object, error) {
+				return &storage.Object{Key: key, URL: "https://cdn/" + key}, nil
+			}),
+		mediaRepo.EXPECT().CreateBlob(gomock.Any(), gomock.Any()).Return(repositories.ErrBlobExists),
+		store.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil),
+		mediaRepo.EXPECT().FindBlob(gomock.Any(), gomock.Any()).Return(winner, nil),
+	)
+	feedRepo.EXPECT().AddMedia(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, media *entities.FeedMedia) error {
+			assert.Equal(t, &winner.ID, media.BlobID)
+			assert.Equal(t, winner.Url, media.Url)
+			return nil
+		})
+	feedRepo.EXPECT().FindOwnerID(gomock.Any(), gomock.Any()).Return(uuid.New(), nil)
+	publisher.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	err := svc.StoreFeedMedias(context.Background(), &events.UploadPayload{
+		FeedID:  uuid.NewString(),
+		Content: []events.ContentData{{FilePath: tempPath}},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestMediaService_StoreFeedMedias_TranscodesVideos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	store := mocksPkg.NewMockStorage(ctrl)
+	videos := mocksPkg.NewMockProcessor(ctrl)
+	publisher := mocksPkg.NewMockPublisher(ctrl)
+	svc := services.NewMediaService(feedRepo, newBlobRepo(ctrl), store, images, videos, publisher)
+
+	feedID, mediaID := uuid.New(), uuid.New()
+	tempPath := filepath.Join(t.TempDir(), "upload.mov")
+	assert.NoError(t, os.WriteFile(tempPath, mp4Bytes(5*time.Second, 1920, 1080), 0o644))
+
+	feedRepo.EXPECT().SetMediaStatus(gomock.Any(), mediaID, entities.MediaProcessing).Return(nil)
+	videos.EXPECT().Transcode(gomock.Any(), tempPath, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, dst string) error {
+			return os.WriteFile(dst, mp4Bytes(5*time.Second, 1280, 720), 0o644)
+		})
+	videos.EXPECT().Poster(gomock.Any(), gomock.Any(), gomock.Any(), time.Second).
+		DoAndReturn(func(_ context.Context, _, dst string, _ time.Duration) error {
+			var buf bytes.Buffer
+			require.NoError(t, jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 16, 9)), nil))
+			return os.WriteFile(dst, buf.Bytes(), 0o644)
+		})
+	store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key string, _ any, _ int64, _ string) (*storage.Object, error) {
+			return &storage.Object{Key: key, URL: "https://cdn/" + key}, nil
+		}).
+		Times(2)
+	feedRepo.EXPECT().UpdateMedia(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, media *entities.FeedMedia) error {
+			assert.Equal(t, entities.MediaVideo, media.Type)
+			assert.Equal(t, entities.MediaReady, media.Status)
+			assert.True(t, strings.HasSuffix(media.Url, "/video.mp4"))
+			assert.True(t, strings.HasSuffix(media.PosterUrl, "/poster.jpg"))
+			assert.Equal(t, 5000, media.DurationMs)
+			assert.Equal(t, 1280, media.Width)
+			assert.Equal(t, 720, media.Height)
+			assert.NotEmpty(t, media.Blurhash)
+			return nil
+		})
+	feedRepo.EXPECT().FindOwnerID(gomock.Any(), feedID).Return(uuid.New(), nil)
+	publisher.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	err := svc.StoreFeedMedias(context.Background(), &events.UploadPayload{
+		FeedID:  feedID.String(),
+		Content: []events.ContentData{{MediaID: mediaID.String(), FilePath: tempPath, FileType: "video"}},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestMediaService_StoreFeedMedias_StoresGIFWithoutMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	store := mocksPkg.NewMockStorage(ctrl)
+	publisher := mocksPkg.NewMockPublisher(ctrl)
+	svc := services.NewMediaService(feedRepo, newBlobRepo(ctrl), store, images, nil, publisher)
+
+	feedID := uuid.New()
+	content := gifWithComment(t)
+	tempPath := filepath.Join(t.TempDir(), "upload.gif")
+	assert.NoError(t, os.WriteFile(tempPath, content, 0o644))
+
+	store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), int64(len(content)-10), "image/gif").
+		DoAndReturn(func(_ context.Context, key string, body io.Reader, _ int64, _ string) (*storage.Object, error) {
+			assert.True(t, strings.HasSuffix(key, ".gif"))
+
+			stored, err := io.ReadAll(body)
+			require.NoError(t, err)
+			assert.NotContains(t, string(stored), "secret")
+
+			_, err = gif.Decode(bytes.NewReader(stored))
+			assert.NoError(t, err)
+			return &storage.Object{Key: key, URL: "https://cdn/" + key}, nil
+		})
+	feedRepo.EXPECT().AddMedia(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, media *entities.FeedMedia) error {
+			assert.Equal(t, "image", media.Type)
+			assert.Empty(t, media.Variants)
+			return nil
+		})
+	feedRepo.EXPECT().FindOwnerID(gomock.Any(), feedID).Return(uuid.New(), nil)
+	publisher.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	err := svc.StoreFeedMedias(context.Background(), &events.UploadPayload{
+		FeedID:  feedID.String(),
+		Content: []events.ContentData{{FilePath: tempPath}},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestMediaService_StoreFeedMedias_KeepsTempFileOnFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	store := mocksPkg.NewMockStorage(ctrl)
+	publisher := mocksPkg.NewMockPublisher(ctrl)
+	svc := services.NewMediaService(feedRepo, newBlobRepo(ctrl), store, images, nil, publisher)
+
+	mediaID := uuid.New()
+	tempPath := filepath.Join(t.TempDir(), "upload.gif")
+	assert.NoError(t, os.WriteFile(tempPath, gifWithComment(t), 0o644))
+
+	gomock.InOrder(
+		feedRepo.EXPECT().SetMediaStatus(gomock.Any(), mediaID, entities.MediaProcessing).Return(nil),
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("storage down")),
+		feedRepo.EXPECT().SetMediaStatus(gomock.Any(), mediaID, entities.MediaFailed).Return(nil),
+	)
+	feedRepo.EXPECT().FindOwnerID(gomock.Any(), gomock.Any()).Return(uuid.New(), nil)
+	publisher.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, event socket.Event) error {
+			assert.Equal(t, entities.MediaFailed, event.Data.(*dto.MediaProcessedEvent).Media.Status)
+			return nil
+		})
+
+	err := svc.StoreFeedMedias(context.Background(), &events.UploadPayload{
+		FeedID:  uuid.NewString(),
+		Content: []events.ContentData{{MediaID: mediaID.String(), FilePath: tempPath}},
+	})
+
+	assert.Error(t, err)
+	_, err = os.Stat(tempPath)
+	assert.NoError(t, err)
+}
+
+func TestMediaService_DeleteFeedMedias_IgnoresMissing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocksPkg.NewMockStorage(ctrl)
+	svc := services.NewMediaService(nil, nil, store, images, nil, nil)
+
+	store.EXPECT().Delete(gomock.Any(), "feeds/a.png").Return(nil)
+	store.EXPECT().Delete(gomock.Any(), "feeds/b.png").Return(storage.ErrNotFound)
+
+	err := svc.DeleteFeedMedias(context.Background(), &events.DeletePayload{Keys: []string{"feeds/a.png", "feeds/b.png"}})
+
+	assert.NoError(t, err)
+}
+
+// newBlobRepo returns a media repository holding no blobs, which hands out
+// ids to the blobs created.
+func newBlobRepo(ctrl *gomock.Controller) *mocksRepo.MockMediaRepository {
+	mediaRepo := mocksRepo.NewMockMediaRepository(ctrl)
+	mediaRepo.EXPECT().FindBlob(gomock.Any(), gomock.Any()).Return(nil, repositories.ErrBlobNotFound).AnyTimes()
+	mediaRepo.EXPECT().CreateBlob(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, blob *entities.MediaBlob) error {
+			blob.ID = uuid.New()
+			return nil
+		}).
+		AnyTimes()
+	return mediaRepo
+}
+
+// mp4Bytes builds the smallest MP4 with a movie header and one video track.
+func mp4Bytes(duration time.Duration, width, height int) []byte {
+	box := func(name string, content ...[]byte) []byte {
+		body := bytes.Join(content, nil)
+		header := make([]byte, 8)
+		binary.BigEndian.PutUint32(header, uint32(8+len(body)))
+		copy(header[4:], name)
+		return append(header, body...)
+	}
+
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:20], uint32(duration.Milliseconds()))
+
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[40:44], 0x00010000)
+	binary.BigEndian.PutUint32(tkhd[56:60], 0x00010000)
+	binary.BigEndian.PutUint32(tkhd[76:80], uint32(width)<<16)
+	binary.BigEndian.PutUint32(tkhd[80:84], uint32(height)<<16)
+
+	return append(box("ftyp", []byte("isom\x00\x00\x00\x00isommp41")), box("moov", box("mvhd", mvhd), box("trak", box("tkhd", tkhd)))...)
+}
@@ -0,0 +1,259 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/services"
+	mocksRepo "github.com/davidafdal/post-app/mocks/repositories"
+	"github.com/google/uuid"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportService_CreateReport_AutoHide(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	reportRepo := mocksRepo.NewMockReportRepository(ctrl)
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+
+	svc := services.NewReportService(reportRepo, userRepo, nil, 3, time.Hour)
+
+	reporterID := uuid.New()
+	authorID := uuid.New()
+	feedID := uuid.New()
+
+	reportRepo.EXPECT().FindTargetOwner(ctx, entities.ReportTargetFeed, feedID).Return(authorID, nil)
+	reportRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, report *entities.Report) (*entities.Report, error) {
+		report.ID = uuid.New()
+		report.Status = entities.ReportStatusOpen
+		return report, nil
+	})
+	reportRepo.EXPECT().CountOpenReports(ctx, entities.ReportTargetFeed, feedID).Return(3, nil)
+	reportRepo.EXPECT().HideContent(ctx, entities.ReportTargetFeed, feedID, gomock.Not(gomock.Nil())).Return(true, nil)
+	reportRepo.
+		EXPECT().
+		CreateAuditLog(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, log *entities.AuditLog) error {
+			assert.Equal(t, entities.ModerationActionAutoHide, log.Action)
+			assert.Nil(t, log.ModeratorID)
+			return nil
+		})
+
+	report, err := svc.CreateReport(ctx, &dto.CreateReportRequest{
+		TargetType: entities.ReportTargetFeed,
+		TargetID:   feedID,
+		Reason:     "spam",
+		ReporterID: reporterID,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entities.ReportStatusOpen, report.Status)
+}
+
+func TestReportService_CreateReport_OwnContent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	reportRepo := mocksRepo.NewMockReportRepository(ctrl)
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+
+	svc := services.NewReportService(reportRepo, userRepo, nil, 3, time.Hour)
+
+	userID := uuid.New()
+	feedID := uuid.New()
+
+	reportRepo.EXPECT().FindTargetOwner(ctx, entities.ReportTargetFeed, feedID).Return(userID, nil)
+
+	_, err := svc.CreateReport(ctx, &dto.CreateReportRequest{
+		TargetType: entities.ReportTargetFeed,
+		TargetID:   feedID,
+		Reason:     "spam",
+		ReporterID: userID,
+	})
+
+	assert.ErrorIs(t, err, services.ErrSelfAction)
+}
+
+func TestReportService_TakeAction_SuspendUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	reportRepo := mocksRepo.NewMockReportRepository(ctrl)
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+
+	moderatorID := uuid.New()
+	authorID := uuid.New()
+	report := &entities.Report{
+		ID:         uuid.New(),
+		TargetType: entities.ReportTargetComment,
+		TargetID:   uuid.New(),
+		Status:     entities.ReportStatusOpen,
+	}
+
+	svc := services.NewReportService(reportRepo, userRepo, nil, 3, time.Hour)
+
+	reportRepo.EXPECT().FindByID(ctx, report.ID).Return(report, nil)
+	reportRepo.EXPECT().FindTargetOwner(ctx, report.TargetType, report.TargetID).Return(authorID, nil)
+	userRepo.EXPECT().FindByID(authorID).Return(&entities.User{ID: authorID, Role: "user"}, nil)
+	userRepo.EXPECT().Suspend(authorID).Return(nil)
+	reportRepo.EXPECT().ResolveTarget(ctx, report.TargetType, report.TargetID, entities.ReportStatusActioned, moderatorID).Return(nil)
+	reportRepo.EXPECT().CreateAuditLog(ctx, gomock.Any()).Return(nil)
+
+	err := svc.TakeAction(ctx, &dto.ModerationActionRequest{
+		ReportID:      report.ID,
+		Action:        entities.ModerationActionSuspendUser,
+		ModeratorID:   moderatorID,
+		ModeratorRole: "moderator",
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestReportService_TakeAction_HideFeedBlocksMedia(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	reportRepo := mocksRepo.NewMockReportRepository(ctrl)
+	mediaRepo := mocksRepo.NewMockMediaRepository(ctrl)
+
+	moderatorID := uuid.New()
+	report := &entities.Report{
+		ID:         uuid.New(),
+		TargetType: entities.ReportTargetFeed,
+		TargetID:   uuid.New(),
+		Status:     entities.ReportStatusOpen,
+	}
+
+	svc := services.NewReportService(reportRepo, nil, mediaRepo, 3, time.Hour)
+
+	reportRepo.EXPECT().FindByID(ctx, report.ID).Return(report, nil)
+	reportRepo.EXPECT().RemoveContent(ctx, report.TargetType, report.TargetID).Return(nil)
+	mediaRepo.EXPECT().BlockFeedMedia(ctx, report.TargetID).Return(nil)
+	reportRepo.EXPECT().ResolveTarget(ctx, report.TargetType, report.TargetID, entities.ReportStatusActioned, moderatorID).Return(nil)
+	reportRepo.EXPECT().CreateAuditLog(ctx, gomock.Any()).Return(nil)
+
+	err := svc.TakeAction(ctx, &dto.ModerationActionRequest{
+		ReportID:      report.ID,
+		Action:        entities.ModerationActionHideContent,
+		ModeratorID:   moderatorID,
+		ModeratorRole: "moderator",
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestReportService_TakeAction_Dismiss(t *testing.T) {
+	tests := []struct {
+		name     string
+		restored bool
+	}{
+		{name: "automatic hide is lifted", restored: true},
+		{name: "removed feed stays removed", restored: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+
+			reportRepo := mocksRepo.NewMockReportRepository(ctrl)
+			mediaRepo := mocksRepo.NewMockMediaRepository(ctrl)
+
+			moderatorID := uuid.New()
+			report := &entities.Report{
+				ID:         uuid.New(),
+				TargetType: entities.ReportTargetFeed,
+				TargetID:   uuid.New(),
+				Status:     entities.ReportStatusOpen,
+			}
+
+			svc := services.NewReportService(reportRepo, nil, mediaRepo, 3, time.Hour)
+
+			reportRepo.EXPECT().FindByID(ctx, report.ID).Return(report, nil)
+			reportRepo.EXPECT().UnhideContent(ctx, report.TargetType, report.TargetID).Return(tt.restored, nil)
+			if tt.restored {
+				mediaRepo.EXPECT().UnblockFeedMedia(ctx, report.TargetID).Return(nil)
+			}
+			reportRepo.EXPECT().ResolveTarget(ctx, report.TargetType, report.TargetID, entities.ReportStatusDismissed, moderatorID).Return(nil)
+			reportRepo.EXPECT().CreateAuditLog(ctx, gomock.Any()).Return(nil)
+
+			err := svc.TakeAction(ctx, &dto.ModerationActionRequest{
+				ReportID:      report.ID,
+				Action:        entities.ModerationActionDismiss,
+				ModeratorID:   moderatorID,
+				ModeratorRole: "moderator",
+			})
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestReportService_TakeAction_ResolvedReport(t *testing.T) {
+	statuses := []string{entities.ReportStatusDismissed, entities.ReportStatusActioned}
+	actions := []string{entities.ModerationActionDismiss, entities.ModerationActionHideContent, entities.ModerationActionSuspendUser}
+
+	for _, status := range statuses {
+		for _, action := range actions {
+			t.Run(status+"/"+action, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				ctx := context.Background()
+
+				reportRepo := mocksRepo.NewMockReportRepository(ctrl)
+				mediaRepo := mocksRepo.NewMockMediaRepository(ctrl)
+
+				report := &entities.Report{
+					ID:         uuid.New(),
+					TargetType: entities.ReportTargetFeed,
+					TargetID:   uuid.New(),
+					Status:     status,
+				}
+
+				svc := services.NewReportService(reportRepo, nil, mediaRepo, 3, time.Hour)
+
+				reportRepo.EXPECT().FindByID(ctx, report.ID).Return(report, nil)
+
+				err := svc.TakeAction(ctx, &dto.ModerationActionRequest{
+					ReportID:      report.ID,
+					Action:        action,
+					ModeratorID:   uuid.New(),
+					ModeratorRole: "moderator",
+				})
+
+				assert.ErrorIs(t, err, services.ErrReportResolved)
+			})
+		}
+	}
+}
+
+func TestReportService_GetReports_InvalidStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reportRepo := mocksRepo.NewMockReportRepository(ctrl)
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+
+	svc := services.NewReportService(reportRepo, userRepo, nil, 3, time.Hour)
+
+	_, err := svc.GetReports(context.Background(), "closed", "", 20)
+
+	assert.ErrorIs(t, err, services.ErrInvalidReportStatus)
+}
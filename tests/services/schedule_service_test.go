@@ -0,0 +1,69 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/services"
+	mocksRepo "github.com/davidafdal/post-app/mocks/repositories"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleService_PublishDueFeeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewScheduleService(feedRepo, notificationRepo)
+
+	due := &entities.Feed{ID: uuid.New(), UserID: uuid.New(), Status: entities.FeedPublished}
+	held := &entities.Feed{ID: uuid.New(), UserID: uuid.New(), Status: entities.FeedPublished, IsHidden: true}
+	mentionedID := uuid.New()
+
+	feedRepo.EXPECT().PublishDueFeeds(ctx).Return([]*entities.Feed{due, held}, nil)
+	feedRepo.EXPECT().FindMentionedUsers(ctx, due.ID).Return([]uuid.UUID{mentionedID, due.UserID}, nil)
+	notificationRepo.EXPECT().CreateMany(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, notifications []*entities.Notification) error {
+			assert.Len(t, notifications, 1)
+			assert.Equal(t, mentionedID, notifications[0].UserID)
+			assert.Equal(t, due.UserID, notifications[0].ActorID)
+			assert.Equal(t, &due.ID, notifications[0].FeedID)
+			return nil
+		})
+
+	assert.NoError(t, svc.PublishDueFeeds(ctx))
+}
+
+func TestScheduleService_PublishDueFeeds_KeepsNotifyingAfterFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+	notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+
+	svc := services.NewScheduleService(feedRepo, notificationRepo)
+
+	first := &entities.Feed{ID: uuid.New(), UserID: uuid.New(), Status: entities.FeedPublished}
+	second := &entities.Feed{ID: uuid.New(), UserID: uuid.New(), Status: entities.FeedPublished}
+	failure := errors.New("connection reset")
+
+	feedRepo.EXPECT().PublishDueFeeds(ctx).Return([]*entities.Feed{first, second}, nil)
+	feedRepo.EXPECT().FindMentionedUsers(ctx, first.ID).Return(nil, failure)
+	feedRepo.EXPECT().FindMentionedUsers(ctx, second.ID).Return([]uuid.UUID{uuid.New()}, nil)
+	notificationRepo.EXPECT().CreateMany(ctx, gomock.Len(1)).Return(nil)
+
+	err := svc.PublishDueFeeds(ctx)
+
+	assert.ErrorIs(t, err, failure)
+}
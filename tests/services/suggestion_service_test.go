@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/services"
+	mocksRepo "github.com/davidafdal/post-app/mocks/repositories"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const suggestionTTL = time.Hour
+
+func suggestion(id uuid.UUID, mutuals ...*entities.User) *entities.Suggestion {
+	return &entities.Suggestion{
+		User:        &entities.User{ID: id, Username: "user-" + id.String()[:8]},
+		MutualCount: len(mutuals),
+		Mutuals:     mutuals,
+	}
+}
+
+func suggestedIDs(suggestions []*dto.SuggestionResponse) []string {
+	ids := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		ids[i] = s.User.ID
+	}
+	return ids
+}
+
+func TestSuggestionService_GetSuggestions_CacheHit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	suggestionRepo := mocksRepo.NewMockSuggestionRepository(ctrl)
+	svc := services.NewSuggestionService(suggestionRepo, suggestionTTL)
+
+	userID := uuid.New()
+	mutual := &entities.User{ID: uuid.New(), Username: "mutual"}
+	cached := []*entities.Suggestion{suggestion(uuid.New(), mutual)}
+
+	suggestionRepo.EXPECT().GetCached(ctx, userID).Return(cached, true, nil)
+	suggestionRepo.EXPECT().FindExcludedIDs(ctx, userID).Return(nil, nil)
+
+	suggestions, err := svc.GetSuggestions(ctx, userID)
+
+	require.NoError(t, err)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, cached[0].User.ID.String(), suggestions[0].User.ID)
+	assert.Equal(t, 1, suggestions[0].MutualCount)
+	require.Len(t, suggestions[0].MutualFollowers, 1)
+	assert.Equal(t, mutual.Username, suggestions[0].MutualFollowers[0].Username)
+}
+
+func TestSuggestionService_GetSuggestions_CacheMiss(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	suggestionRepo := mocksRepo.NewMockSuggestionRepository(ctrl)
+	svc := services.NewSuggestionService(suggestionRepo, suggestionTTL)
+
+	userID := uuid.New()
+	computed := []*entities.Suggestion{suggestion(uuid.New()), suggestion(uuid.New())}
+
+	gomock.InOrder(
+		suggestionRepo.EXPECT().GetCached(ctx, userID).Return(nil, false, nil),
+		suggestionRepo.EXPECT().FindFriendsOfFriends(ctx, userID, gomock.Any(), gomock.Any()).Return(computed, nil),
+		suggestionRepo.EXPECT().SaveCached(ctx, userID, computed, suggestionTTL).Return(nil),
+	)
+	suggestionRepo.EXPECT().FindExcludedIDs(ctx, userID).Return(nil, nil)
+
+	suggestions, err := svc.GetSuggestions(ctx, userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{computed[0].User.ID.String(), computed[1].User.ID.String()}, suggestedIDs(suggestions))
+}
+
+func TestSuggestionService_GetSuggestions_DropsExcludedUsers(t *testing.T) {
+	userID := uuid.New()
+	followedID := uuid.New()
+	blockedID := uuid.New()
+	keptID := uuid.New()
+
+	tests := []struct {
+		name     string
+		cached   []*entities.Suggestion
+		excluded []uuid.UUID
+	}{
+		{
+			name:     "followed since cached",
+			cached:   []*entities.Suggestion{suggestion(followedID), suggestion(keptID)},
+			excluded: []uuid.UUID{followedID},
+		},
+		{
+			name:     "blocked since cached",
+			cached:   []*entities.Suggestion{suggestion(keptID), suggestion(blockedID)},
+			excluded: []uuid.UUID{blockedID},
+		},
+		{
+			name:   "self",
+			cached: []*entities.Suggestion{suggestion(userID), suggestion(keptID)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+			suggestionRepo := mocksRepo.NewMockSuggestionRepository(ctrl)
+			svc := services.NewSuggestionService(suggestionRepo, suggestionTTL)
+
+			suggestionRepo.EXPECT().GetCached(ctx, userID).Return(tt.cached, true, nil)
+			suggestionRepo.EXPECT().FindExcludedIDs(ctx, userID).Return(tt.excluded, nil)
+
+			suggestions, err := svc.GetSuggestions(ctx, userID)
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{keptID.String()}, suggestedIDs(suggestions))
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/services"
+	mocksRepo "github.com/davidafdal/post-app/mocks/repositories"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrendingService_ComputeTrending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	trendingRepo := mocksRepo.NewMockTrendingRepository(ctrl)
+	feedRepo := mocksRepo.NewMockFeedRepository(ctrl)
+
+	svc := services.NewTrendingService(trendingRepo, feedRepo, 10)
+
+	now := time.Now()
+
+	// "old" has more engagements, but they happened long ago and decayed
+	engagements := []*entities.Engagement{
+		{Key: "old", Kind: entities.EngagementComment, CreatedAt: now.Add(-20 * time.Hour)},
+		{Key: "old", Kind: entities.EngagementComment, CreatedAt: now.Add(-20 * time.Hour)},
+		{Key: "old", Kind: entities.EngagementReaction, CreatedAt: now.Add(-20 * time.Hour)},
+		{Key: "new", Kind: entities.EngagementComment, CreatedAt: now.Add(-time.Minute)},
+		{Key: "new", Kind: entities.EngagementReaction, CreatedAt: now.Add(-time.Minute)},
+	}
+
+	trendingRepo.EXPECT().FindFeedEngagements(gomock.Any(), gomock.Any()).Return(engagements, nil).Times(2)
+	trendingRepo.EXPECT().FindHashtagEngagements(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+
+	trendingRepo.
+		EXPECT().
+		SaveRanking(gomock.Any(), "trending:feeds:day", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, items []*entities.RankedItem) error {
+			assert.Len(t, items, 2)
+			assert.Equal(t, "new", items[0].Key)
+			assert.Equal(t, "old", items[1].Key)
+			return nil
+		})
+	trendingRepo.EXPECT().SaveRanking(gomock.Any(), "trending:feeds:week", gomock.Any()).Return(nil)
+	trendingRepo.EXPECT().SaveRanking(gomock.Any(), "trending:tags:day", gomock.Len(0)).Return(nil)
+	trendingRepo.EXPECT().SaveRanking(gomock.Any(), "trending:tags:week", gomock.Len(0)).Return(nil)
+
+	assert.NoError(t, svc.ComputeTrending(ctx))
+}
+
+func TestTrendingService_GetTrendingTags_InvalidWindow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := services.NewTrendingService(mocksRepo.NewMockTrendingRepository(ctrl), mocksRepo.NewMockFeedRepository(ctrl), 10)
+
+	_, err := svc.GetTrendingTags(context.Background(), "year", 10)
+
+	assert.ErrorIs(t, err, services.ErrInvalidTrendingWindow)
+}
@@ -0,0 +1,49 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/davidafdal/post-app/internal/dto"
+	"github.com/davidafdal/post-app/internal/services"
+	"github.com/davidafdal/post-app/pkg/upload"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadService_AppendUpload(t *testing.T) {
+	ctx := context.Background()
+	svc := services.NewUploadService(upload.NewResumableStore(t.TempDir()), 1<<20)
+
+	ownerID := uuid.New()
+
+	created, err := svc.CreateUpload(ctx, &dto.CreateUploadRequest{Length: 6, UserID: ownerID})
+	require.NoError(t, err)
+
+	res, err := svc.AppendUpload(ctx, &dto.AppendUploadRequest{UploadID: created.ID, Offset: 0, UserID: ownerID}, strings.NewReader("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), res.Offset)
+	assert.False(t, res.Complete)
+
+	_, err = svc.AppendUpload(ctx, &dto.AppendUploadRequest{UploadID: created.ID, Offset: 0, UserID: ownerID}, strings.NewReader("abc"))
+	assert.ErrorIs(t, err, services.ErrUploadOffsetMismatch)
+
+	_, err = svc.AppendUpload(ctx, &dto.AppendUploadRequest{UploadID: created.ID, Offset: 3, UserID: uuid.New()}, strings.NewReader("def"))
+	assert.ErrorIs(t, err, services.ErrUploadNotFound)
+
+	res, err = svc.AppendUpload(ctx, &dto.AppendUploadRequest{UploadID: created.ID, Offset: 3, UserID: ownerID}, strings.NewReader("def"))
+	require.NoError(t, err)
+	assert.True(t, res.Complete)
+}
+
+func TestUploadService_CreateUpload_Limits(t *testing.T) {
+	svc := services.NewUploadService(upload.NewResumableStore(t.TempDir()), 100)
+
+	_, err := svc.CreateUpload(context.Background(), &dto.CreateUploadRequest{Length: 101, UserID: uuid.New()})
+	assert.ErrorIs(t, err, services.ErrUploadTooLarge)
+
+	_, err = svc.CreateUpload(context.Background(), &dto.CreateUploadRequest{Length: 0, UserID: uuid.New()})
+	assert.ErrorIs(t, err, services.ErrInvalidUploadLength)
+}
@@ -0,0 +1,327 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/davidafdal/post-app/internal/entities"
+	"github.com/davidafdal/post-app/internal/services"
+	mocksRepo "github.com/davidafdal/post-app/mocks/repositories"
+	"github.com/davidafdal/post-app/pkg/cursor"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserService_FollowUser_PrivateAccountCreatesRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	svc := services.NewUserService(userRepo, nil, nil, nil)
+
+	followerID := uuid.New()
+	followingID := uuid.New()
+
+	userRepo.EXPECT().FindByID(followingID).Return(&entities.User{ID: followingID, IsPrivate: true}, nil)
+	userRepo.EXPECT().IsBlockedBetween(followerID, followingID).Return(false, nil)
+	userRepo.EXPECT().IsFollowing(followerID, followingID).Return(false, nil)
+	userRepo.EXPECT().ToggleFollowRequest(followerID, followingID).Return("requested", nil)
+
+	status, err := svc.FollowUser(followerID, followingID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "requested", status)
+}
+
+func TestUserService_FollowUser_Blocked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	svc := services.NewUserService(userRepo, nil, nil, nil)
+
+	followerID := uuid.New()
+	followingID := uuid.New()
+
+	userRepo.EXPECT().FindByID(followingID).Return(&entities.User{ID: followingID}, nil)
+	userRepo.EXPECT().IsBlockedBetween(followerID, followingID).Return(true, nil)
+
+	_, err := svc.FollowUser(followerID, followingID)
+
+	assert.ErrorIs(t, err, services.ErrUserBlocked)
+}
+
+func TestUserService_GetFollowers_PrivateAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	svc := services.NewUserService(userRepo, nil, nil, nil)
+
+	viewerID := uuid.New()
+	owner := &entities.User{ID: uuid.New(), Username: "private", IsPrivate: true}
+
+	userRepo.EXPECT().FindByUsername(owner.Username).Return(owner, nil)
+	userRepo.EXPECT().CanView(viewerID, owner.ID).Return(false, nil)
+
+	_, err := svc.GetFollowers(owner.Username, viewerID, "", 20)
+
+	assert.ErrorIs(t, err, services.ErrPrivateAccount)
+}
+
+func followUsers(n int) []*entities.FollowUser {
+	followedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	users := make([]*entities.FollowUser, n)
+	for i := range users {
+		users[i] = &entities.FollowUser{
+			FollowID:   uuid.New(),
+			FollowedAt: followedAt.Add(-time.Duration(i) * time.Minute),
+			User:       &entities.User{ID: uuid.New(), Username: "user"},
+		}
+	}
+	return users
+}
+
+func TestUserService_GetFollowers_DecodesCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	svc := services.NewUserService(userRepo, nil, nil, nil)
+
+	viewerID := uuid.New()
+	owner := &entities.User{ID: uuid.New(), Username: "owner"}
+	after := cursor.Cursor{CreatedAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), ID: uuid.New()}
+
+	userRepo.EXPECT().FindByUsername(owner.Username).Return(owner, nil)
+	userRepo.EXPECT().CanView(viewerID, owner.ID).Return(true, nil)
+	userRepo.EXPECT().FindFollowers(owner.ID, viewerID, &after, 20).Return(nil, nil)
+
+	list, err := svc.GetFollowers(owner.Username, viewerID, cursor.Encode(after), 20)
+
+	assert.NoError(t, err)
+	assert.Empty(t, list.Items)
+	assert.Empty(t, list.NextCursor)
+}
+
+func TestUserService_GetFollowList_NextCursor(t *testing.T) {
+	tests := []struct {
+		name       string
+		found      int
+		limit      int
+		nextCursor bool
+	}{
+		{name: "full page", found: 3, limit: 3, nextCursor: true},
+		{name: "last page", found: 2, limit: 3, nextCursor: false},
+		{name: "empty page", found: 0, limit: 3, nextCursor: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mocksRepo.NewMockUserRepository(ctrl)
+			svc := services.NewUserService(userRepo, nil, nil, nil)
+
+			viewerID := uuid.New()
+			owner := &entities.User{ID: uuid.New(), Username: "owner"}
+			found := followUsers(tt.found)
+
+			userRepo.EXPECT().FindByUsername(owner.Username).Return(owner, nil)
+			userRepo.EXPECT().CanView(viewerID, owner.ID).Return(true, nil)
+			userRepo.EXPECT().FindFollowings(owner.ID, viewerID, nil, tt.limit).Return(found, nil)
+
+			list, err := svc.GetFollowings(owner.Username, viewerID, "", tt.limit)
+
+			require.NoError(t, err)
+			assert.Len(t, list.Items, tt.found)
+
+			if !tt.nextCursor {
+				assert.Empty(t, list.NextCursor)
+				return
+			}
+
+			next, err := cursor.Decode(list.NextCursor)
+			require.NoError(t, err)
+
+			last := found[len(found)-1]
+			assert.Equal(t, last.FollowID, next.ID)
+			assert.True(t, last.FollowedAt.Equal(next.CreatedAt))
+		})
+	}
+}
+
+func TestUserService_GetMutuals_InvalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	svc := services.NewUserService(userRepo, nil, nil, nil)
+
+	for _, after := range []string{"not a cursor!", "bm90LWEtY3Vyc29y"} {
+		_, err := svc.GetMutuals("owner", uuid.New(), after, 20)
+
+		assert.ErrorIs(t, err, cursor.ErrInvalidCursor)
+	}
+}
+
+func TestUserService_BlockAndMute_InvalidTarget(t *testing.T) {
+	actions := []struct {
+		name string
+		run  func(services.UserService, uuid.UUID, uuid.UUID) error
+	}{
+		{name: "block", run: services.UserService.BlockUser},
+		{name: "mute", run: services.UserService.MuteUser},
+	}
+
+	for _, action := range actions {
+		t.Run(action.name+" self", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := services.NewUserService(mocksRepo.NewMockUserRepository(ctrl), nil, nil, nil)
+
+			userID := uuid.New()
+
+			assert.ErrorIs(t, action.run(svc, userID, userID), services.ErrSelfAction)
+		})
+
+		t.Run(action.name+" unknown user", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mocksRepo.NewMockUserRepository(ctrl)
+			svc := services.NewUserService(userRepo, nil, nil, nil)
+
+			targetID := uuid.New()
+
+			userRepo.EXPECT().FindByID(targetID).Return(nil, sql.ErrNoRows)
+
+			assert.ErrorIs(t, action.run(svc, uuid.New(), targetID), services.ErrUserNotFound)
+		})
+	}
+}
+
+func TestUserService_BlockUser_RemovesFollowsBothWays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	svc := services.NewUserService(userRepo, nil, nil, nil)
+
+	blockerID := uuid.New()
+	blockedID := uuid.New()
+
+	userRepo.EXPECT().FindByID(blockedID).Return(&entities.User{ID: blockedID}, nil)
+	gomock.InOrder(
+		userRepo.EXPECT().Block(blockerID, blockedID).Return(nil),
+		userRepo.EXPECT().Unfollow(blockerID, blockedID).Return(nil),
+		userRepo.EXPECT().CancelFollowRequest(blockerID, blockedID).Return(nil),
+		userRepo.EXPECT().Unfollow(blockedID, blockerID).Return(nil),
+		userRepo.EXPECT().CancelFollowRequest(blockedID, blockerID).Return(nil),
+	)
+
+	assert.NoError(t, svc.BlockUser(blockerID, blockedID))
+}
+
+func TestUserService_BlockUser_StopsOnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	svc := services.NewUserService(userRepo, nil, nil, nil)
+
+	blockerID := uuid.New()
+	blockedID := uuid.New()
+	unfollowErr := errors.New("db down")
+
+	userRepo.EXPECT().FindByID(blockedID).Return(&entities.User{ID: blockedID}, nil)
+	userRepo.EXPECT().Block(blockerID, blockedID).Return(nil)
+	userRepo.EXPECT().Unfollow(blockerID, blockedID).Return(unfollowErr)
+
+	assert.ErrorIs(t, svc.BlockUser(blockerID, blockedID), unfollowErr)
+}
+
+func TestUserService_MuteUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	svc := services.NewUserService(userRepo, nil, nil, nil)
+
+	muterID := uuid.New()
+	mutedID := uuid.New()
+
+	userRepo.EXPECT().FindByID(mutedID).Return(&entities.User{ID: mutedID}, nil)
+	userRepo.EXPECT().Mute(muterID, mutedID).Return(nil)
+
+	assert.NoError(t, svc.MuteUser(muterID, mutedID))
+}
+
+func TestUserService_UnblockAndUnmute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocksRepo.NewMockUserRepository(ctrl)
+	svc := services.NewUserService(userRepo, nil, nil, nil)
+
+	userID := uuid.New()
+	otherID := uuid.New()
+
+	userRepo.EXPECT().Unblock(userID, otherID).Return(nil)
+	userRepo.EXPECT().Unmute(userID, otherID).Return(nil)
+
+	assert.NoError(t, svc.UnblockUser(userID, otherID))
+	assert.NoError(t, svc.UnmuteUser(userID, otherID))
+}
+
+func TestNotificationService_GetNotifications_SkipsBlockedAndMutedActors(t *testing.T) {
+	blockedActor, mutedActor := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name     string
+		blocked  []uuid.UUID
+		muted    []uuid.UUID
+		excluded []uuid.UUID
+	}{
+		{name: "no blocks or mutes", excluded: []uuid.UUID{}},
+		{name: "blocked actor", blocked: []uuid.UUID{blockedActor}, excluded: []uuid.UUID{blockedActor}},
+		{name: "muted actor", muted: []uuid.UUID{mutedActor}, excluded: []uuid.UUID{mutedActor}},
+		{name: "blocked and muted actors", blocked: []uuid.UUID{blockedActor}, muted: []uuid.UUID{mutedActor}, excluded: []uuid.UUID{blockedActor, mutedActor}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+
+			userRepo := mocksRepo.NewMockUserRepository(ctrl)
+			notificationRepo := mocksRepo.NewMockNotificationRepository(ctrl)
+			svc := services.NewNotificationService(notificationRepo, userRepo)
+
+			userID := uuid.New()
+			actor := &entities.User{ID: uuid.New(), Username: "jane"}
+
+			userRepo.EXPECT().FindBlockedIDs(userID).Return(tt.blocked, nil)
+			userRepo.EXPECT().FindMutedIDs(userID).Return(tt.muted, nil)
+			notificationRepo.EXPECT().FindByUser(ctx, userID, tt.excluded, 100).Return([]*entities.Notification{
+				{ID: uuid.New(), UserID: userID, ActorID: actor.ID, Actor: actor, Type: entities.NotificationRepost},
+			}, nil)
+
+			notifications, err := svc.GetNotifications(ctx, userID)
+
+			require.NoError(t, err)
+			require.Len(t, notifications, 1)
+			assert.Equal(t, actor.Username, notifications[0].Actor.Username)
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davidafdal/post-app/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a minimal S3-compatible server keeping objects in memory, like a
+// local MinIO. It only accepts requests signed for its access key.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3(t *testing.T) *httptest.Server {
+	fake := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/") {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := r.URL.Path
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodHead:
+		body, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[key])
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newS3(t *testing.T, srv *httptest.Server) *storage.S3 {
+	s3, err := storage.NewS3(storage.S3Config{
+		Endpoint:  srv.URL,
+		Bucket:    "media",
+		AccessKey: "minio",
+		SecretKey: "minio-secret",
+	})
+	require.NoError(t, err)
+	return s3
+}
+
+func TestS3_PutStatDelete(t *testing.T) {
+	ctx := context.Background()
+	srv := newFakeS3(t)
+	s3 := newS3(t, srv)
+
+	object, err := s3.Put(ctx, "feeds/1/photo.jpg", strings.NewReader("jpeg"), 4, "image/jpeg")
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/media/feeds/1/photo.jpg", object.URL)
+
+	key, err := s3.KeyFromURL(object.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "feeds/1/photo.jpg", key)
+
+	stat, err := s3.Stat(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), stat.Size)
+	assert.Equal(t, "image/jpeg", stat.ContentType)
+
+	require.NoError(t, s3.Delete(ctx, key))
+
+	_, err = s3.Stat(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestS3_SignedURL(t *testing.T) {
+	s3 := newS3(t, newFakeS3(t))
+
+	signed, err := s3.SignedURL(context.Background(), "avatars/a.png", time.Hour)
+	require.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "/media/avatars/a.png", u.Path)
+	assert.Equal(t, "3600", u.Query().Get("X-Amz-Expires"))
+	assert.NotEmpty(t, u.Query().Get("X-Amz-Signature"))
+
+	_, err = s3.SignedURL(context.Background(), "avatars/a.png", 8*24*time.Hour)
+	assert.Error(t, err)
+}
+
+func TestLocal_PutServeDelete(t *testing.T) {
+	ctx := context.Background()
+	local, err := storage.NewLocal(t.TempDir(), "http://localhost/media", "secret")
+	require.NoError(t, err)
+
+	object, err := local.Put(ctx, "avatars/a.txt", strings.NewReader("hello"), 5, "text/plain")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost/media/avatars/a.txt", object.URL)
+
+	rec := httptest.NewRecorder()
+	local.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/avatars/a.txt", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "hello", rec.Body.String())
+
+	require.NoError(t, storage.DeleteURL(ctx, local, object.URL))
+
+	_, err = local.Stat(ctx, "avatars/a.txt")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.ErrorIs(t, local.Delete(ctx, "avatars/a.txt"), storage.ErrNotFound)
+}
+
+func TestLocal_List(t *testing.T) {
+	ctx := context.Background()
+	local, err := storage.NewLocal(t.TempDir(), "http://localhost/media", "secret")
+	require.NoError(t, err)
+
+	for _, key := range []string{"feeds/f1/a.jpg", "feeds/f1/v/b.jpg", "feeds/f2/c.jpg", "avatars/d.png"} {
+		_, err := local.Put(ctx, key, strings.NewReader("x"), 1, "image/jpeg")
+		require.NoError(t, err)
+	}
+
+	var keys []string
+	require.NoError(t, local.List(ctx, "feeds/f1", func(object *storage.Object) error {
+		keys = append(keys, object.Key)
+		return nil
+	}))
+	assert.ElementsMatch(t, []string{"feeds/f1/a.jpg", "feeds/f1/v/b.jpg"}, keys)
+
+	require.NoError(t, local.List(ctx, "missing/", func(*storage.Object) error {
+		t.Fatal("nothing should be listed")
+		return nil
+	}))
+}
+
+func TestLocal_SignedURL(t *testing.T) {
+	ctx := context.Background()
+	local, err := storage.NewLocal(t.TempDir(), "http://localhost/media", "secret")
+	require.NoError(t, err)
+
+	for _, key := range []string{"a.txt", "b.txt"} {
+		_, err = local.Put(ctx, key, strings.NewReader("hi"), 2, "text/plain")
+		require.NoError(t, err)
+	}
+
+	signed, err := local.SignedURL(ctx, "a.txt", time.Minute)
+	require.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "/media/a.txt", u.Path)
+
+	expired, err := local.SignedURL(ctx, "a.txt", -time.Minute)
+	require.NoError(t, err)
+	expiredURL, err := url.Parse(expired)
+	require.NoError(t, err)
+
+	otherKey, err := storage.NewLocal(t.TempDir(), "http://localhost/media", "other")
+	require.NoError(t, err)
+	forged, err := otherKey.SignedURL(ctx, "a.txt", time.Minute)
+	require.NoError(t, err)
+	forgedURL, err := url.Parse(forged)
+	require.NoError(t, err)
+
+	extended := u.Query()
+	extended.Set("expires", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+
+	unsigned := u.Query()
+	unsigned.Del("signature")
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+	}{
+		{name: "valid", target: "/a.txt?" + u.RawQuery, status: http.StatusOK},
+		{name: "expired", target: "/a.txt?" + expiredURL.RawQuery, status: http.StatusForbidden},
+		{name: "extended expiry", target: "/a.txt?" + extended.Encode(), status: http.StatusForbidden},
+		{name: "signed with another key", target: "/a.txt?" + forgedURL.RawQuery, status: http.StatusForbidden},
+		{name: "signature of another object", target: "/b.txt?" + u.RawQuery, status: http.StatusForbidden},
+		{name: "expiry without signature", target: "/a.txt?" + unsigned.Encode(), status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			local.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestLocal_RequiresSigningKey(t *testing.T) {
+	_, err := storage.NewLocal(t.TempDir(), "http://localhost/media", "")
+	assert.ErrorIs(t, err, storage.ErrNoSigningKey)
+}
+
+func TestLocal_RejectsKeysOutsideRoot(t *testing.T) {
+	local, err := storage.NewLocal(t.TempDir(), "http://localhost/media", "secret")
+	require.NoError(t, err)
+
+	object, err := local.Put(context.Background(), "../../etc/passwd", strings.NewReader("x"), 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, "etc/passwd", object.Key)
+
+	_, err = local.Put(context.Background(), "..", strings.NewReader("x"), 1, "")
+	assert.ErrorIs(t, err, storage.ErrInvalidKey)
+}
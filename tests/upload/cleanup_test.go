@@ -0,0 +1,45 @@
+package upload_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/davidafdal/post-app/pkg/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaleFiles(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-2 * time.Hour)
+
+	write := func(name string, modTime time.Time) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte("x"), 0o644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	write("stale.jpg", old)
+	write("fresh.jpg", time.Now())
+	// An upload created long ago whose last chunk came in just now.
+	write("active.info", old)
+	write("active.part", time.Now())
+	write("abandoned.info", old)
+	write("abandoned.part", old)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0o755))
+
+	stale, err := upload.StaleFiles(dir, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		filepath.Join(dir, "stale.jpg"),
+		filepath.Join(dir, "abandoned.info"),
+		filepath.Join(dir, "abandoned.part"),
+	}, stale)
+
+	missing, err := upload.StaleFiles(filepath.Join(dir, "missing"), time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
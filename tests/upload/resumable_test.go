@@ -0,0 +1,138 @@
+package upload_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/davidafdal/post-app/pkg/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumableStore_AppendAndClaim(t *testing.T) {
+	dir := t.TempDir()
+	store := upload.NewResumableStore(dir)
+
+	created, err := store.Create("user-1", 10, map[string]string{"filename": "clip.mp4"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), created.Offset)
+
+	appended, err := store.Append(created.ID, 0, strings.NewReader("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), appended.Offset)
+	assert.False(t, appended.Complete())
+
+	_, err = store.Append(created.ID, 0, strings.NewReader("again"))
+	assert.ErrorIs(t, err, upload.ErrOffsetMismatch)
+
+	_, err = store.Claim(created.ID)
+	assert.ErrorIs(t, err, upload.ErrUploadIncomplete)
+
+	found, err := store.Get(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), found.Offset)
+	assert.Equal(t, "user-1", found.OwnerID)
+	assert.Equal(t, "clip.mp4", found.Name())
+
+	appended, err = store.Append(created.ID, 5, strings.NewReader("world"))
+	require.NoError(t, err)
+	assert.True(t, appended.Complete())
+
+	tempPath, err := store.Claim(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dir, filepath.Dir(tempPath))
+	assert.Equal(t, ".mp4", filepath.Ext(tempPath))
+
+	content, err := os.ReadFile(tempPath)
+	require.NoError(t, err)
+	assert.Equal(t, "helloworld", string(content))
+
+	_, err = store.Get(created.ID)
+	assert.ErrorIs(t, err, upload.ErrUploadNotFound)
+}
+
+func TestResumableStore_Release(t *testing.T) {
+	store := upload.NewResumableStore(t.TempDir())
+
+	created, err := store.Create("user-1", 5, map[string]string{"filename": "clip.mp4"})
+	require.NoError(t, err)
+	appended, err := store.Append(created.ID, 0, strings.NewReader("hello"))
+	require.NoError(t, err)
+
+	tempPath, err := store.Claim(created.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Release(appended, tempPath))
+
+	_, err = os.Stat(tempPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	found, err := store.Get(created.ID)
+	require.NoError(t, err)
+	assert.True(t, found.Complete())
+	assert.Equal(t, "clip.mp4", found.Name())
+
+	tempPath, err = store.Claim(created.ID)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(tempPath)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+}
+
+func TestResumableStore_KeepsPartialChunk(t *testing.T) {
+	store := upload.NewResumableStore(t.TempDir())
+
+	created, err := store.Create("user-1", 10, nil)
+	require.NoError(t, err)
+
+	broken := io.MultiReader(strings.NewReader("abc"), errReader{})
+
+	appended, err := store.Append(created.ID, 0, broken)
+	assert.Error(t, err)
+	assert.Equal(t, int64(3), appended.Offset)
+
+	found, err := store.Get(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), found.Offset)
+}
+
+func TestResumableStore_RejectsDataPastLength(t *testing.T) {
+	store := upload.NewResumableStore(t.TempDir())
+
+	created, err := store.Create("user-1", 4, nil)
+	require.NoError(t, err)
+
+	appended, err := store.Append(created.ID, 0, bytes.NewReader([]byte("toolong")))
+	assert.ErrorIs(t, err, upload.ErrUploadTooLarge)
+	assert.Equal(t, int64(4), appended.Offset)
+}
+
+func TestResumableStore_Delete(t *testing.T) {
+	dir := t.TempDir()
+	store := upload.NewResumableStore(dir)
+
+	created, err := store.Create("user-1", 4, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Delete(created.ID))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	assert.ErrorIs(t, store.Delete(created.ID), upload.ErrUploadNotFound)
+	_, err = store.Get("../" + created.ID)
+	assert.ErrorIs(t, err, upload.ErrUploadNotFound)
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}